@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Show the in-world date, moon phases and holidays",
+	Long: `Show the current session's date on the campaign calendar, the phase of
+each moon, today's holidays and those coming up.
+
+The calendar is defined under "calendar" in spells.yaml in the campaign
+directory:
+
+  calendar:
+    start: {year: 1023, month: 1, day: 1}
+    months:
+      - {name: Deepwinter, days: 30}
+      - {name: Thaw, days: 28}
+    weekdays: [Moonday, Fireday, Starday]
+    moons:
+      - {name: Selune, cycle: 28, offset: 14}
+    holidays:
+      - {name: Midwinter, month: Deepwinter, day: 15, description: Feast}
+
+Use --schedule to queue the upcoming holidays as time events, so they
+trigger in the tracker when play reaches them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		days, _ := cmd.Flags().GetInt64("days")
+		schedule, _ := cmd.Flags().GetBool("schedule")
+
+		if days < 0 {
+			return fmt.Errorf("--days cannot be negative")
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+
+			calendar, err := eng.Calendar()
+			if err != nil {
+				return err
+			}
+			if calendar == nil {
+				return fmt.Errorf("no calendar configured; add a calendar section to %s", campaignSettingsFile)
+			}
+
+			now, err := eng.Now(session)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("%s, %s (%s)\n", calendar.Date(now.Day), now.Clock(), now.TimeOfDay())
+
+			for _, moon := range calendar.Moons(now.Day) {
+				cmd.Printf("%s: %s (day %d of %d)\n", moon.Moon, moon.Phase, moon.Day+1, moon.Cycle)
+			}
+
+			holidays := calendar.Holidays(now.Day, days)
+			if len(holidays) == 0 {
+				cmd.Printf("No holidays in the next %d days\n", days)
+			}
+			for _, holiday := range holidays {
+				cmd.Println(formatHoliday(holiday))
+			}
+
+			if schedule {
+				events, err := eng.ScheduleHolidays(session, days)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("Scheduled %d holiday events\n", len(events))
+			}
+			return nil
+		})
+	},
+}
+
+func formatHoliday(holiday clock.HolidayDate) string {
+	when := fmt.Sprintf("In %d days", holiday.InDays)
+	switch holiday.InDays {
+	case 0:
+		when = "Today"
+	case 1:
+		when = "Tomorrow"
+	}
+
+	line := fmt.Sprintf("%s: %s (%d %s)", when, holiday.Holiday.Name, holiday.Date.Day, holiday.Date.MonthName)
+	if holiday.Holiday.Description != "" {
+		line += " - " + holiday.Holiday.Description
+	}
+	return line
+}
+
+func init() {
+	addCalendarFlags(calendarCmd)
+}
+
+func addCalendarFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Int64("session-id", 0, "session to use (default latest)")
+	cmd.Flags().Int64("days", 30, "how many days ahead to look for holidays")
+	cmd.Flags().Bool("schedule", false, "queue upcoming holidays as time events")
+}
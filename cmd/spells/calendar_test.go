@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+const testCalendarYAML = `calendar:
+  start: {year: 1023, month: 1, day: 14}
+  months:
+    - {name: Deepwinter, days: 30}
+    - {name: Thaw, days: 28}
+  weekdays: [Moonday, Fireday, Starday]
+  moons:
+    - {name: Selune, cycle: 8, offset: 4}
+  holidays:
+    - {name: Midwinter, month: Deepwinter, day: 15, description: Feast}
+    - {name: Thawing, month: 2, day: 1}
+`
+
+func TestCalendarCommand(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+	dir := filepath.Dir(dbPath)
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	newCalendarCommand := func() *cobra.Command {
+		cmd := &cobra.Command{Use: calendarCmd.Use, Args: calendarCmd.Args, RunE: calendarCmd.RunE}
+		addCalendarFlags(cmd)
+		return cmd
+	}
+
+	if _, err := executeCommand(newCalendarCommand(), dbPath); err == nil {
+		t.Fatal("Expected an error without a calendar")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, campaignSettingsFile), []byte(testCalendarYAML), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	output, err := executeCommand(newCalendarCommand(), dbPath, "--schedule")
+	if err != nil {
+		t.Fatalf("calendar failed: %v", err)
+	}
+	expected := "Moonday, 14 Deepwinter 1023, 08:00 (morning)\n" +
+		"Selune: full (day 5 of 8)\n" +
+		"Tomorrow: Midwinter (15 Deepwinter) - Feast\n" +
+		"In 17 days: Thawing (1 Thaw)\n" +
+		"Scheduled 2 holiday events\n"
+	if output != expected {
+		t.Errorf("Unexpected calendar output:\n%s\nexpected:\n%s", output, expected)
+	}
+
+	events, err := model.ListPendingTimeEvents(database, 1)
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(events) != 2 || events[0].EventType != model.TimeEventHoliday || events[0].TriggerTurn != 96 {
+		t.Errorf("Expected holiday events from turn 96, got %+v", events)
+	}
+
+	clock := &cobra.Command{Use: clockCmd.Use, Args: clockCmd.Args, RunE: clockCmd.RunE}
+	addClockFlags(clock)
+	output, err = executeCommand(clock, dbPath)
+	if err != nil {
+		t.Fatalf("clock failed: %v", err)
+	}
+	if output != "Moonday, 14 Deepwinter 1023, 08:00 (morning, watch 3)\n" {
+		t.Errorf("Expected the clock to show the calendar date, got %q", output)
+	}
+}
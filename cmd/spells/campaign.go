@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	configpkg "github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/engine"
+)
+
+// campaignConfigPath returns the config file that sits alongside the
+// campaign database, e.g. campaign.yaml for campaign.db.
+func campaignConfigPath(dbPath string) string {
+	return strings.TrimSuffix(dbPath, filepath.Ext(dbPath)) + ".yaml"
+}
+
+// campaignSettingsFile is the shared campaign settings file in the campaign
+// directory. The config beside the database overrides it.
+const campaignSettingsFile = "spells.yaml"
+
+// loadCampaignConfig reads the campaign config for the database at dbPath:
+// spells.yaml in the campaign directory, then the config file beside the
+// database, falling back to the defaults for anything neither sets.
+func loadCampaignConfig(dbPath string) (configpkg.Config, error) {
+	settingsPath := filepath.Join(filepath.Dir(dbPath), campaignSettingsFile)
+
+	config, err := configpkg.LoadFiles(settingsPath, campaignConfigPath(dbPath))
+	if err != nil {
+		return config, fmt.Errorf("failed to load campaign config: %w", err)
+	}
+	return config, nil
+}
+
+// withCampaignEngine opens the campaign at path and runs fn with an engine
+// that has the campaign's config and oracle tables loaded.
+func withCampaignEngine(path string, fn func(*engine.Engine) error) error {
+	database, err := db.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	config, err := loadCampaignConfig(path)
+	if err != nil {
+		return err
+	}
+	tables, err := loadOracleTables(path)
+	if err != nil {
+		return err
+	}
+
+	return fn(&engine.Engine{DB: database, Config: &config, Tables: tables})
+}
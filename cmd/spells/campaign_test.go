@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// newTestCampaign creates a campaign database with one session, and a
+// spells.yaml beside it holding settings when they are given. It returns
+// the database path.
+func newTestCampaign(t *testing.T, settings string) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "campaign.db")
+
+	if settings != "" {
+		if err := os.WriteFile(filepath.Join(dir, campaignSettingsFile), []byte(settings), 0644); err != nil {
+			t.Fatalf("Failed to write settings: %v", err)
+		}
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	return dbPath
+}
+
+// executeCommand runs a command against the campaign at dbPath, returning
+// what it printed and its error.
+func executeCommand(cmd *cobra.Command, dbPath string, args ...string) (string, error) {
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append(args, "--path", dbPath))
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+// runCommand runs a command against the campaign at dbPath and returns
+// what it printed, failing the test if the command fails.
+func runCommand(t *testing.T, cmd *cobra.Command, dbPath string, args ...string) string {
+	t.Helper()
+	output, err := executeCommand(cmd, dbPath, args...)
+	if err != nil {
+		t.Fatalf("%s %v failed: %v", cmd.Use, args, err)
+	}
+	return output
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+var clockCmd = &cobra.Command{
+	Use:   "clock",
+	Short: "Show the in-world time of the current session",
+	Long: `Show the in-world date and time of the current session. Time is kept
+in turns (10 minutes) and combat rounds (10 seconds); a watch is 4 hours.
+The unit lengths and the hour play starts at are set under "clock" in the
+campaign config.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			return printClock(cmd, eng, session)
+		})
+	},
+}
+
+var clockAdvanceCmd = &cobra.Command{
+	Use:   "advance <duration>",
+	Short: "Advance time by a duration such as \"3 hours\" or \"1 watch\"",
+	Long: `Advance the current session by an in-world duration. Durations are
+numbers with units: rounds, turns, watches, days, hours, minutes and
+seconds, e.g. "3 hours", "1 watch", "2 turns 3 rounds" or "1d4h". A bare
+number counts turns.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+
+			duration, err := eng.Clock().ParseDuration(strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			warnings := collectWarnings(eng)
+			if err := eng.AdvanceDuration(session, duration); err != nil {
+				return err
+			}
+			if err := printClock(cmd, eng, session); err != nil {
+				return err
+			}
+			for _, warning := range *warnings {
+				cmd.Println(warning)
+			}
+			return nil
+		})
+	},
+}
+
+var clockModeCmd = &cobra.Command{
+	Use:   "mode <exploration|combat>",
+	Short: "Switch between exploration turns and combat rounds",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			if err := eng.SetTimeMode(session, args[0]); err != nil {
+				return err
+			}
+			cmd.Printf("Time mode: %s\n", args[0])
+			return nil
+		})
+	},
+}
+
+func printClock(cmd *cobra.Command, eng *engine.Engine, sessionID int64) error {
+	now, err := eng.Now(sessionID)
+	if err != nil {
+		return err
+	}
+
+	calendar, err := eng.Calendar()
+	if err != nil {
+		return err
+	}
+	if calendar != nil {
+		cmd.Printf("%s, %s (%s, watch %d)\n", calendar.Date(now.Day), now.Clock(), now.TimeOfDay(), now.Watch)
+		return nil
+	}
+	cmd.Printf("%s (%s, watch %d)\n", now, now.TimeOfDay(), now.Watch)
+	return nil
+}
+
+// collectWarnings gathers the warnings about lights and supplies running
+// out that advancing time raises, to print once the new time is shown.
+func collectWarnings(eng *engine.Engine) *[]string {
+	var warnings []string
+	eng.EventBus = engine.NewEventBus()
+	for _, eventType := range []string{"LightLow", "LightOut", "ConsumableRanOut"} {
+		eng.EventBus.Subscribe(eventType, func(event engine.Event) {
+			switch event := event.(type) {
+			case engine.LightLow:
+				warnings = append(warnings, event.Summary())
+			case engine.LightOut:
+				warnings = append(warnings, event.Summary())
+			case engine.ConsumableRanOut:
+				warnings = append(warnings, event.Summary())
+			}
+		})
+	}
+	return &warnings
+}
+
+func init() {
+	addClockFlags(clockCmd)
+	clockCmd.AddCommand(clockAdvanceCmd, clockModeCmd)
+}
+
+func addClockFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+	cmd.PersistentFlags().Int64("session-id", 0, "session to use (default latest)")
+}
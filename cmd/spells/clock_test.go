@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestClockCommand() *cobra.Command {
+	parent := &cobra.Command{Use: clockCmd.Use, Args: clockCmd.Args, RunE: clockCmd.RunE}
+	addClockFlags(parent)
+	parent.AddCommand(
+		&cobra.Command{Use: clockAdvanceCmd.Use, Args: clockAdvanceCmd.Args, RunE: clockAdvanceCmd.RunE},
+		&cobra.Command{Use: clockModeCmd.Use, Args: clockModeCmd.Args, RunE: clockModeCmd.RunE},
+	)
+	return parent
+}
+
+func TestClockCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	run := func(args ...string) string {
+		t.Helper()
+		return runCommand(t, newTestClockCommand(), dbPath, args...)
+	}
+
+	if output := run(); output != "Day 1, 08:00 (morning, watch 3)\n" {
+		t.Errorf("Unexpected time: %q", output)
+	}
+	if output := run("advance", "1", "watch"); output != "Day 1, 12:00 (afternoon, watch 4)\n" {
+		t.Errorf("Unexpected time after a watch: %q", output)
+	}
+	if output := run("advance", "13 hours 3 rounds"); output != "Day 2, 01:00:30 (night, watch 1)\n" {
+		t.Errorf("Unexpected time after 13 hours: %q", output)
+	}
+	if output := run("mode", "combat"); output != "Time mode: combat\n" {
+		t.Errorf("Unexpected mode output: %q", output)
+	}
+}
@@ -0,0 +1,618 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var combatCmd = &cobra.Command{
+	Use:   "combat",
+	Short: "Run combat in the current session",
+	Long: `Track an encounter's initiative order, hit points and rounds. With no
+subcommand, shows the running encounter.
+
+  spells combat start Goblin ambush
+  spells combat add Goblin --init 12 --hp 4
+  spells combat next                 pass the turn
+  spells combat damage 2 5           take 5 HP from combatant 2
+  spells combat end
+
+Combatants at 0 HP are defeated and skipped, as are those taken out of
+the turn order with deactivate. Starting an encounter switches the
+session to combat time, and every round that passes advances the clock.
+
+With "initiative: side" under combat in the campaign config, every side
+(party, monsters or any faction given with --side) rolls 1d6 at the
+start of each round and its members act on that roll. "ties" picks how
+tied sides resolve: simultaneous, reroll or dex.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			sides := eng.Config != nil && eng.Config.Combat.Initiative == engine.InitiativeSide
+			return printCombat(cmd, eng, encounter, sides)
+		})
+	},
+}
+
+var combatStartCmd = &cobra.Command{
+	Use:   "start [name...]",
+	Short: "Start an encounter",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			encounter, err := eng.StartEncounter(session, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Started encounter %d\n", encounter.ID)
+			return nil
+		})
+	},
+}
+
+var combatEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "End the running encounter",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			if err := eng.EndEncounter(encounter.ID); err != nil {
+				return err
+			}
+			cmd.Printf("Ended encounter %d after %s\n", encounter.ID, plural(encounter.Round, "round"))
+			return nil
+		})
+	},
+}
+
+var combatAddCmd = &cobra.Command{
+	Use:   "add <name...>",
+	Short: "Add a combatant to the running encounter",
+	Long: `Add a combatant to the running encounter. A name in the monster catalog,
+optionally with a count such as "3 goblins", adds that many of the monster
+with hit points rolled from its hit dice, unless --hp is given.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initiative, _ := cmd.Flags().GetInt("init")
+		hp, _ := cmd.Flags().GetInt("hp")
+		side, _ := cmd.Flags().GetString("side")
+		dex, _ := cmd.Flags().GetInt("dex")
+		ac, _ := cmd.Flags().GetInt("ac")
+		bonus, _ := cmd.Flags().GetInt("bonus")
+		thac0, _ := cmd.Flags().GetInt("thac0")
+		damage, _ := cmd.Flags().GetString("damage")
+		name := strings.Join(args, " ")
+		if cmd.Flags().Changed("bonus") && cmd.Flags().Changed("thac0") {
+			return fmt.Errorf("give an attack bonus or a THAC0, not both")
+		}
+
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			if hp == 0 {
+				monster, count, err := eng.FindMonsters(name)
+				if err != nil {
+					return err
+				}
+				if monster != nil {
+					added, err := eng.AddMonsters(encounter.ID, monster, count, initiative, side)
+					if err != nil {
+						return err
+					}
+					for _, combatant := range added {
+						cmd.Printf("Added combatant %d: %s (%d HP)\n", combatant.ID, *combatant.CharacterName, *combatant.HPMax)
+					}
+					return nil
+				}
+			}
+
+			combatant := &model.InitiativeOrder{
+				EncounterID:   encounter.ID,
+				CharacterName: &name,
+				Initiative:    initiative,
+				Side:          side,
+			}
+			if hp > 0 {
+				combatant.HPCurrent, combatant.HPMax = &hp, &hp
+			}
+			if cmd.Flags().Changed("dex") {
+				combatant.Dex = &dex
+			}
+			if cmd.Flags().Changed("ac") {
+				combatant.ArmorClass = &ac
+			}
+			switch {
+			case cmd.Flags().Changed("bonus"):
+				combatant.AttackBonus = &bonus
+			case cmd.Flags().Changed("thac0"):
+				bonus = 19 - thac0
+				combatant.AttackBonus = &bonus
+			}
+			if damage != "" {
+				combatant.Damage = &damage
+			}
+			if err := eng.AddCombatant(combatant); err != nil {
+				return err
+			}
+			cmd.Printf("Added combatant %d: %s\n", combatant.ID, name)
+			return nil
+		})
+	},
+}
+
+var combatRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a combatant from its encounter",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withCombatant(cmd, args[0], func(eng *engine.Engine, id int64) error {
+			if err := eng.RemoveCombatant(id); err != nil {
+				return err
+			}
+			cmd.Printf("Removed combatant %d\n", id)
+			return nil
+		})
+	},
+}
+
+var combatDamageCmd = &cobra.Command{
+	Use:   "damage <id> <amount>",
+	Short: "Take hit points from a combatant",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeCombatantHP(cmd, args, (*engine.Engine).Damage)
+	},
+}
+
+var combatHealCmd = &cobra.Command{
+	Use:   "heal <id> <amount>",
+	Short: "Restore hit points to a combatant",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeCombatantHP(cmd, args, (*engine.Engine).Heal)
+	},
+}
+
+var combatNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Pass the turn to the next combatant",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			eng.EventBus = engine.NewEventBus()
+			eng.EventBus.Subscribe("SideInitiativeRolled", func(event engine.Event) {
+				rolled := event.(engine.SideInitiativeRolled)
+				cmd.Printf("Initiative: %s\n", rolled.Summary())
+				if len(rolled.Tied) > 0 {
+					cmd.Printf("Tied: %s\n", strings.Join(rolled.Tied, ", "))
+				}
+			})
+
+			combatant, err := eng.NextTurn(encounter.ID)
+			if err != nil {
+				return err
+			}
+			current, err := model.GetEncounter(eng.DB, encounter.ID)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Round %d: %s\n", current.Round, combatant.Name)
+			return nil
+		})
+	},
+}
+
+var combatPartyCmd = &cobra.Command{
+	Use:   "party",
+	Short: "Add the active characters on the roster to the running encounter",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		initiative, _ := cmd.Flags().GetInt("init")
+
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			added, err := eng.AddParty(encounter.ID, initiative)
+			if err != nil {
+				return err
+			}
+			if len(added) == 0 {
+				cmd.Println("The party is already in the encounter.")
+				return nil
+			}
+			for _, combatant := range added {
+				cmd.Printf("Added combatant %d: %s\n", combatant.ID, *combatant.CharacterName)
+			}
+			return nil
+		})
+	},
+}
+
+var combatSideCmd = &cobra.Command{
+	Use:   "side <id> <side>",
+	Short: "Move a combatant to another side",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withCombatant(cmd, args[0], func(eng *engine.Engine, id int64) error {
+			if err := eng.SetCombatantSide(id, args[1]); err != nil {
+				return err
+			}
+			cmd.Printf("Combatant %d is on the %s side\n", id, args[1])
+			return nil
+		})
+	},
+}
+
+var combatAttackCmd = &cobra.Command{
+	Use:   "attack <attacker-id> <target-id>",
+	Short: "Resolve an attack and apply its damage",
+	Long: `Roll a d20 for each attack in the attacker's routine against the
+target's AC, then roll damage for each hit and take it off the target's
+HP. To-hit follows the campaign's combat.armor_class setting: descending
+(THAC0) or ascending. The outcome goes to the combat log.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		damage, _ := cmd.Flags().GetString("damage")
+		target, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid combatant ID %q", args[1])
+		}
+
+		return withCombatant(cmd, args[0], func(eng *engine.Engine, attacker int64) error {
+			var morale []engine.MoraleChecked
+			var earned []engine.XPEarned
+			eng.EventBus = engine.NewEventBus()
+			eng.EventBus.Subscribe("MoraleChecked", func(event engine.Event) {
+				morale = append(morale, event.(engine.MoraleChecked))
+			})
+			eng.EventBus.Subscribe("XPEarned", func(event engine.Event) {
+				earned = append(earned, event.(engine.XPEarned))
+			})
+
+			resolved, err := eng.Attack(attacker, target, damage)
+			if err != nil {
+				return err
+			}
+			cmd.Println(resolved.Summary())
+			for _, xp := range earned {
+				cmd.Printf("Earned %d XP for %s\n", xp.XP, xp.Description)
+			}
+			for _, checked := range morale {
+				printMorale(cmd, checked)
+			}
+			return nil
+		})
+	},
+}
+
+var combatLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the running encounter's combat log",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			entries, err := model.ListCombatLog(eng.DB, encounter.ID, limit)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				cmd.Println("The combat log is empty.")
+				return nil
+			}
+			for _, entry := range entries {
+				cmd.Printf("Round %d: %s\n", entry.Round, entry.Message)
+			}
+			return nil
+		})
+	},
+}
+
+var combatMoraleCmd = &cobra.Command{
+	Use:   "morale [side]",
+	Short: "Roll a morale check for a side",
+	Long: `Roll 2d6 morale for each monster on a side (the monsters by default).
+A roll over the monster's morale score means it flees. Checks are rolled
+automatically on a side's first death and when it drops to half strength.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		side := model.SideMonsters
+		if len(args) > 0 {
+			side = args[0]
+		}
+
+		return withCombat(cmd, func(eng *engine.Engine, encounter *model.Encounter) error {
+			checked, err := eng.CheckMorale(encounter.ID, side)
+			if err != nil {
+				return err
+			}
+			printMorale(cmd, *checked)
+			return nil
+		})
+	},
+}
+
+var combatConditionCmd = &cobra.Command{
+	Use:   "condition <id> <name>",
+	Short: "Put a condition on a combatant, or take it off",
+	Long: `Put a condition such as poisoned, held, asleep or prone on a combatant.
+With --rounds or --turns it wears off as that much time passes; without,
+it lasts until removed with --remove.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rounds, _ := cmd.Flags().GetInt64("rounds")
+		turns, _ := cmd.Flags().GetInt64("turns")
+		remove, _ := cmd.Flags().GetBool("remove")
+		if rounds > 0 && turns > 0 {
+			return fmt.Errorf("give a duration in rounds or turns, not both")
+		}
+
+		return withCombatant(cmd, args[0], func(eng *engine.Engine, id int64) error {
+			var err error
+			switch {
+			case remove:
+				err = eng.RemoveCondition(id, args[1])
+			case turns > 0:
+				err = eng.AddCondition(id, args[1], turns, model.ConditionTurns)
+			default:
+				err = eng.AddCondition(id, args[1], rounds, model.ConditionRounds)
+			}
+			if err != nil {
+				return err
+			}
+
+			combatant, err := model.FindCombatant(eng.DB, id)
+			if err != nil {
+				return err
+			}
+			conditions := model.FormatConditions(combatant.Conditions)
+			if conditions == "" {
+				conditions = "no conditions"
+			}
+			cmd.Printf("%s: %s\n", combatant.Name, conditions)
+			return nil
+		})
+	},
+}
+
+var combatActivateCmd = &cobra.Command{
+	Use:   "activate <id>",
+	Short: "Put a combatant back in the turn order",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCombatantActive(cmd, args[0], true)
+	},
+}
+
+var combatDeactivateCmd = &cobra.Command{
+	Use:   "deactivate <id>",
+	Short: "Take a combatant out of the turn order",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCombatantActive(cmd, args[0], false)
+	},
+}
+
+// withCombat runs fn with the current session's running encounter.
+func withCombat(cmd *cobra.Command, fn func(*engine.Engine, *model.Encounter) error) error {
+	path, _ := cmd.Flags().GetString("path")
+	sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		session, err := requireSession(eng.DB, sessionID)
+		if err != nil {
+			return err
+		}
+		encounter, err := model.GetActiveEncounter(eng.DB, session)
+		if err != nil {
+			return err
+		}
+		if encounter == nil {
+			return fmt.Errorf("no encounter running, start one with spells combat start")
+		}
+		return fn(eng, encounter)
+	})
+}
+
+// withCombatant parses a combatant ID and runs fn with it.
+func withCombatant(cmd *cobra.Command, arg string, fn func(*engine.Engine, int64) error) error {
+	path, _ := cmd.Flags().GetString("path")
+
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid combatant ID %q", arg)
+	}
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		return fn(eng, id)
+	})
+}
+
+func changeCombatantHP(cmd *cobra.Command, args []string, change func(*engine.Engine, int64, int) error) error {
+	amount, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", args[1])
+	}
+
+	return withCombatant(cmd, args[0], func(eng *engine.Engine, id int64) error {
+		var morale []engine.MoraleChecked
+		var earned []engine.XPEarned
+		eng.EventBus = engine.NewEventBus()
+		eng.EventBus.Subscribe("MoraleChecked", func(event engine.Event) {
+			morale = append(morale, event.(engine.MoraleChecked))
+		})
+		eng.EventBus.Subscribe("XPEarned", func(event engine.Event) {
+			earned = append(earned, event.(engine.XPEarned))
+		})
+
+		if err := change(eng, id, amount); err != nil {
+			return err
+		}
+		combatant, err := model.FindCombatant(eng.DB, id)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("%s: %s%s\n", combatant.Name, combatantHP(*combatant), combatantState(*combatant))
+		for _, xp := range earned {
+			cmd.Printf("Earned %d XP for %s\n", xp.XP, xp.Description)
+		}
+		for _, checked := range morale {
+			printMorale(cmd, checked)
+		}
+		return nil
+	})
+}
+
+// printMorale shows each roll of a morale check and who flees.
+func printMorale(cmd *cobra.Command, checked engine.MoraleChecked) {
+	cmd.Printf("Morale (%s, %s): %s\n", checked.Side, checked.Trigger, checked.Summary())
+	if fled := checked.Fled(); len(fled) > 0 {
+		cmd.Printf("Fleeing: %s\n", strings.Join(fled, ", "))
+	}
+}
+
+func setCombatantActive(cmd *cobra.Command, arg string, active bool) error {
+	return withCombatant(cmd, arg, func(eng *engine.Engine, id int64) error {
+		if err := eng.SetCombatantActive(id, active); err != nil {
+			return err
+		}
+		if active {
+			cmd.Printf("Combatant %d is back in the turn order\n", id)
+		} else {
+			cmd.Printf("Combatant %d is out of the turn order\n", id)
+		}
+		return nil
+	})
+}
+
+// printCombat lists an encounter's combatants in turn order, with their
+// sides when sides is set.
+func printCombat(cmd *cobra.Command, eng *engine.Engine, encounter *model.Encounter, sides bool) error {
+	combatants, err := eng.Combatants(encounter.ID)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("Encounter %d", encounter.ID)
+	if encounter.Name != nil && *encounter.Name != "" {
+		name = *encounter.Name
+	}
+	if encounter.Round > 0 {
+		cmd.Printf("%s, round %d\n", name, encounter.Round)
+	} else {
+		cmd.Printf("%s, not started\n", name)
+	}
+
+	if len(combatants) == 0 {
+		cmd.Println("No combatants.")
+		return nil
+	}
+	for _, combatant := range combatants {
+		marker := " "
+		if encounter.CurrentCombatantID != nil && *encounter.CurrentCombatantID == combatant.ID {
+			marker = "▶"
+		}
+		name := combatant.Name
+		if sides {
+			name += " (" + combatant.Side + ")"
+		}
+		armor := ""
+		if combatant.ArmorClass != nil {
+			armor = fmt.Sprintf("  AC %d", *combatant.ArmorClass)
+		}
+		conditions := ""
+		if formatted := model.FormatConditions(combatant.Conditions); formatted != "" {
+			conditions = "  [" + formatted + "]"
+		}
+		cmd.Printf("%s %4d  %s  init %d  %s%s%s%s\n", marker, combatant.ID, name, combatant.Initiative,
+			combatantHP(combatant), armor, combatantState(combatant), conditions)
+	}
+	return nil
+}
+
+// combatantHP shows a combatant's hit points, e.g. "3/8 HP".
+func combatantHP(combatant model.Combatant) string {
+	switch {
+	case combatant.HPCurrent != nil && combatant.HPMax != nil:
+		return fmt.Sprintf("%d/%d HP", *combatant.HPCurrent, *combatant.HPMax)
+	case combatant.HPCurrent != nil:
+		return fmt.Sprintf("%d HP", *combatant.HPCurrent)
+	case combatant.HPMax != nil:
+		return fmt.Sprintf("?/%d HP", *combatant.HPMax)
+	default:
+		return "? HP"
+	}
+}
+
+// combatantState flags combatants that do not take turns.
+func combatantState(combatant model.Combatant) string {
+	switch {
+	case combatant.Dead():
+		return "  defeated"
+	case !combatant.IsActive:
+		return "  inactive"
+	default:
+		return ""
+	}
+}
+
+// plural formats a count with a noun, e.g. "1 round" or "3 rounds".
+func plural(n int64, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func init() {
+	addCombatFlags(combatCmd)
+	combatCmd.AddCommand(combatStartCmd, combatEndCmd, combatAddCmd, combatRemoveCmd, combatDamageCmd,
+		combatHealCmd, combatNextCmd, combatSideCmd, combatConditionCmd, combatMoraleCmd, combatAttackCmd, combatLogCmd, combatActivateCmd, combatDeactivateCmd, combatPartyCmd)
+	addCombatantFlags(combatAddCmd)
+	addPartyFlags(combatPartyCmd)
+	addConditionFlags(combatConditionCmd)
+	addAttackFlags(combatAttackCmd)
+	addCombatLogFlags(combatLogCmd)
+}
+
+func addCombatantFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("init", 0, "initiative")
+	cmd.Flags().Int("hp", 0, "hit points")
+	cmd.Flags().String("side", model.SideMonsters, "side for side initiative, e.g. party or monsters")
+	cmd.Flags().Int("dex", 0, "Dexterity, for breaking initiative ties")
+	cmd.Flags().Int("ac", 0, "armor class")
+	cmd.Flags().Int("bonus", 0, "attack bonus")
+	cmd.Flags().Int("thac0", 0, "THAC0, instead of an attack bonus")
+	cmd.Flags().String("damage", "", "damage dice of each attack, e.g. 1d8 or 1d4/1d4")
+}
+
+func addPartyFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("init", 0, "initiative")
+}
+
+func addAttackFlags(cmd *cobra.Command) {
+	cmd.Flags().String("damage", "", "damage dice to roll instead of the attacker's own")
+}
+
+func addCombatLogFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", 20, "number of lines to show (0 for all)")
+}
+
+func addConditionFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64("rounds", 0, "rounds until the condition wears off")
+	cmd.Flags().Int64("turns", 0, "turns until the condition wears off")
+	cmd.Flags().Bool("remove", false, "take the condition off")
+}
+
+func addCombatFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+	cmd.PersistentFlags().Int64("session-id", 0, "session to use (default latest)")
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestCombatCommand() *cobra.Command {
+	parent := &cobra.Command{Use: combatCmd.Use, Args: combatCmd.Args, RunE: combatCmd.RunE}
+	addCombatFlags(parent)
+
+	add := &cobra.Command{Use: combatAddCmd.Use, Args: combatAddCmd.Args, RunE: combatAddCmd.RunE}
+	addCombatantFlags(add)
+	parent.AddCommand(add)
+	condition := &cobra.Command{Use: combatConditionCmd.Use, Args: combatConditionCmd.Args, RunE: combatConditionCmd.RunE}
+	addConditionFlags(condition)
+	parent.AddCommand(condition)
+	attack := &cobra.Command{Use: combatAttackCmd.Use, Args: combatAttackCmd.Args, RunE: combatAttackCmd.RunE}
+	addAttackFlags(attack)
+	parent.AddCommand(attack)
+	log := &cobra.Command{Use: combatLogCmd.Use, Args: combatLogCmd.Args, RunE: combatLogCmd.RunE}
+	addCombatLogFlags(log)
+	parent.AddCommand(log)
+	party := &cobra.Command{Use: combatPartyCmd.Use, Args: combatPartyCmd.Args, RunE: combatPartyCmd.RunE}
+	addPartyFlags(party)
+	parent.AddCommand(party)
+	for _, source := range []*cobra.Command{combatStartCmd, combatEndCmd, combatRemoveCmd, combatDamageCmd,
+		combatHealCmd, combatNextCmd, combatSideCmd, combatMoraleCmd, combatActivateCmd, combatDeactivateCmd} {
+		parent.AddCommand(&cobra.Command{Use: source.Use, Args: source.Args, RunE: source.RunE})
+	}
+	return parent
+}
+
+func TestCombatCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	run := func(args ...string) string {
+		t.Helper()
+		return runCommand(t, newTestCombatCommand(), dbPath, args...)
+	}
+
+	if output := run("start", "Goblin", "ambush"); output != "Started encounter 1\n" {
+		t.Errorf("Unexpected start output: %q", output)
+	}
+	run("add", "Fighter", "--init", "15", "--hp", "8")
+	if output := run("add", "Goblin", "--init", "12", "--hp", "4"); output != "Added combatant 2: Goblin\n" {
+		t.Errorf("Unexpected add output: %q", output)
+	}
+	if output := run("next"); output != "Round 1: Fighter\n" {
+		t.Errorf("Unexpected next output: %q", output)
+	}
+	if output := run("damage", "2", "5"); output != "Goblin: 0/4 HP  defeated\n" {
+		t.Errorf("Unexpected damage output: %q", output)
+	}
+
+	if output := run("condition", "1", "blessed", "--rounds", "6"); output != "Fighter: blessed (6 rounds)\n" {
+		t.Errorf("Unexpected condition output: %q", output)
+	}
+
+	output := run()
+	expected := "Goblin ambush, round 1\n" +
+		"▶    1  Fighter  init 15  8/8 HP  [blessed (6 rounds)]\n" +
+		"     2  Goblin  init 12  0/4 HP  defeated\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	if output := run("next"); output != "Round 2: Fighter\n" {
+		t.Errorf("Expected the defeated goblin to be skipped, got %q", output)
+	}
+	if output := run("end"); output != "Ended encounter 1 after 2 rounds\n" {
+		t.Errorf("Unexpected end output: %q", output)
+	}
+
+	if _, err := executeCommand(newTestCombatCommand(), dbPath, "next"); err == nil || !strings.Contains(err.Error(), "no encounter running") {
+		t.Errorf("Expected no running encounter, got %v", err)
+	}
+}
+
+func TestCombatSideInitiative(t *testing.T) {
+	dbPath := newTestCampaign(t, "combat:\n  initiative: side\n  initiative_die: 1d1\n  ties: dex\n")
+
+	run := func(args ...string) string {
+		t.Helper()
+		return runCommand(t, newTestCombatCommand(), dbPath, args...)
+	}
+
+	run("start")
+	run("add", "Goblin", "--dex", "9")
+	run("add", "Thief", "--side", "party", "--dex", "17")
+
+	// Every side rolls a 1, so the tie goes to the higher Dexterity.
+	expected := "Initiative: monsters 1, party 1\nTied: monsters, party\nRound 1: Thief\n"
+	if output := run("next"); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	output := run()
+	if !strings.Contains(output, "▶    2  Thief (party)  init 1") || !strings.Contains(output, "     1  Goblin (monsters)  init 1") {
+		t.Errorf("Expected sides in the listing, got %q", output)
+	}
+
+	if output := run("side", "1", "party"); output != "Combatant 1 is on the party side\n" {
+		t.Errorf("Unexpected side output: %q", output)
+	}
+}
+
+func TestCombatAttack(t *testing.T) {
+	dbPath := newTestCampaign(t, "combat:\n  armor_class: ascending\n")
+
+	run := func(args ...string) string {
+		t.Helper()
+		return runCommand(t, newTestCombatCommand(), dbPath, args...)
+	}
+
+	run("start")
+	if output := run("log"); output != "The combat log is empty.\n" {
+		t.Errorf("Unexpected empty log output: %q", output)
+	}
+	run("add", "Fighter", "--hp", "10", "--ac", "15", "--bonus", "2", "--side", "party")
+	run("add", "Goblin", "--hp", "3", "--ac", "12", "--thac0", "19", "--damage", "1d6")
+
+	// Ascending AC: +2 against AC 12 needs 10.
+	output := run("attack", "1", "2", "--damage", "5")
+	if !strings.HasPrefix(output, "Fighter attacks Goblin: ") || !strings.Contains(output, " vs 10)") {
+		t.Errorf("Unexpected attack output: %q", output)
+	}
+	if strings.Contains(output, "hit for 5") != strings.Contains(output, "Goblin is defeated") {
+		t.Errorf("Expected a hit for 5 to defeat the goblin, got %q", output)
+	}
+	if log := run("log"); log != "Round 0: "+output {
+		t.Errorf("Expected the attack in the log, got %q", log)
+	}
+	if listing := run(); !strings.Contains(listing, "Fighter  init 0  10/10 HP  AC 15") {
+		t.Errorf("Expected AC in the listing, got %q", listing)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var eventCmd = &cobra.Command{
+	Use:   "event",
+	Short: "Schedule and manage in-world time events",
+	Long: `Time events trigger when the session's clock reaches them, as time is
+advanced with "spells clock advance", "spells explore" or the TUI. A
+recurring event triggers again every interval until it is cancelled.
+
+  spells event add --in "1 watch" The ogre wakes
+  spells event add --every "2 turns" --type wandering_check
+  spells event                 list the events still to come
+  spells event cancel 3
+
+Wandering check events roll on the wandering monster table when they
+trigger; other types are reminders.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listEvents(cmd)
+	},
+}
+
+var eventAddCmd = &cobra.Command{
+	Use:   "add [description...]",
+	Short: "Schedule an event after a duration, or every interval",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		in, _ := cmd.Flags().GetString("in")
+		every, _ := cmd.Flags().GetString("every")
+		eventType, _ := cmd.Flags().GetString("type")
+		description := strings.Join(args, " ")
+		if (in == "") == (every == "") {
+			return fmt.Errorf("give one of --in or --every")
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+
+			var event *model.TimeEvent
+			if every != "" {
+				turns, err := durationTurns(eng, every)
+				if err != nil {
+					return err
+				}
+				event, err = eng.ScheduleRecurring(session, eventType, description, turns)
+				if err != nil {
+					return err
+				}
+			} else {
+				turns, err := durationTurns(eng, in)
+				if err != nil {
+					return err
+				}
+				event, err = eng.Schedule(session, eventType, description, turns)
+				if err != nil {
+					return err
+				}
+			}
+			cmd.Printf("Scheduled event %d: %s\n", event.ID, describeEvent(eng, *event))
+			return nil
+		})
+	},
+}
+
+var eventListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the events still to come",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listEvents(cmd)
+	},
+}
+
+var eventCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "Cancel a scheduled event",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid event ID %q", args[0])
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			if err := eng.CancelEvent(id); err != nil {
+				return err
+			}
+			cmd.Printf("Cancelled event %d\n", id)
+			return nil
+		})
+	},
+}
+
+func listEvents(cmd *cobra.Command) error {
+	path, _ := cmd.Flags().GetString("path")
+	sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		session, err := requireSession(eng.DB, sessionID)
+		if err != nil {
+			return err
+		}
+		events, err := model.ListPendingTimeEvents(eng.DB, session)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			cmd.Println("No events scheduled.")
+			return nil
+		}
+		for _, event := range events {
+			cmd.Printf("%4d  %s\n", event.ID, describeEvent(eng, event))
+		}
+		return nil
+	})
+}
+
+// durationTurns converts an in-world duration such as "3 turns" or
+// "1 watch" into the turns events are scheduled in, rounding part turns up.
+func durationTurns(eng *engine.Engine, s string) (int64, error) {
+	d, err := eng.Clock().ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	turns, rounds := eng.Clock().Split(d)
+	if rounds > 0 {
+		turns++
+	}
+	return turns, nil
+}
+
+// describeEvent shows when an event triggers and what it is, e.g.
+// "Day 1, 10:00: The ogre wakes (every 2 turns)".
+func describeEvent(eng *engine.Engine, event model.TimeEvent) string {
+	desc := fmt.Sprintf("%s: %s", eng.Clock().At(event.TriggerTurn, 0), eventName(event))
+	if event.RepeatEvery != nil {
+		desc += fmt.Sprintf(" (every %s)", plural(*event.RepeatEvery, "turn"))
+	}
+	return desc
+}
+
+// eventName is an event's description, or its type when it has none.
+func eventName(event model.TimeEvent) string {
+	if event.Description != nil && *event.Description != "" {
+		return *event.Description
+	}
+	return strings.ReplaceAll(event.EventType, "_", " ")
+}
+
+func init() {
+	addEventFlags(eventCmd)
+	eventCmd.AddCommand(eventAddCmd, eventListCmd, eventCancelCmd)
+	addScheduleFlags(eventAddCmd)
+}
+
+func addEventFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+	cmd.PersistentFlags().Int64("session-id", 0, "session to use (default latest)")
+}
+
+func addScheduleFlags(cmd *cobra.Command) {
+	cmd.Flags().String("in", "", "in-world time until the event, e.g. \"3 turns\" or \"1 watch\"")
+	cmd.Flags().String("every", "", "make the event recurring at this interval, e.g. \"2 turns\"")
+	cmd.Flags().String("type", model.TimeEventReminder, "event type, e.g. wandering_check")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestEventCommand() *cobra.Command {
+	parent := &cobra.Command{Use: eventCmd.Use, Args: eventCmd.Args, RunE: eventCmd.RunE}
+	addEventFlags(parent)
+	add := &cobra.Command{Use: eventAddCmd.Use, Args: eventAddCmd.Args, RunE: eventAddCmd.RunE}
+	addScheduleFlags(add)
+	parent.AddCommand(
+		add,
+		&cobra.Command{Use: eventListCmd.Use, Args: eventListCmd.Args, RunE: eventListCmd.RunE},
+		&cobra.Command{Use: eventCancelCmd.Use, Args: eventCancelCmd.Args, RunE: eventCancelCmd.RunE},
+	)
+	return parent
+}
+
+func TestEventCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+	run := func(args ...string) string {
+		t.Helper()
+		return runCommand(t, newTestEventCommand(), dbPath, args...)
+	}
+
+	if output := run(); output != "No events scheduled.\n" {
+		t.Errorf("Unexpected events: %q", output)
+	}
+	if output := run("add", "--in", "1 watch", "The", "ogre", "wakes"); output != "Scheduled event 1: Day 1, 12:00: The ogre wakes\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+	output := run("add", "--every", "2 turns", "--type", "wandering_check")
+	if output != "Scheduled event 2: Day 1, 08:20: wandering check (every 2 turns)\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+	if _, err := executeCommand(newTestEventCommand(), dbPath, "add", "Nothing"); err == nil {
+		t.Error("Expected an error without --in or --every")
+	}
+
+	expected := "   2  Day 1, 08:20: wandering check (every 2 turns)\n   1  Day 1, 12:00: The ogre wakes\n"
+	if output := run("list"); output != expected {
+		t.Errorf("Expected the events soonest first, got %q", output)
+	}
+	if output := run("cancel", "2"); output != "Cancelled event 2\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+	if output := run(); output != "   1  Day 1, 12:00: The ogre wakes\n" {
+		t.Errorf("Expected the recurring event cancelled, got %q", output)
+	}
+}
@@ -2,8 +2,6 @@ package main
 
 import (
 	"fmt"
-	"path/filepath"
-	"strings"
 
 	configpkg "github.com/script-wizards/spells/internal/config"
 	"github.com/script-wizards/spells/internal/db"
@@ -24,10 +22,10 @@ var initCmd = &cobra.Command{
 		}
 		defer database.Close()
 
-		// Create default config YAML alongside the database
-		configPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".yaml"
-		defaultConfig := configpkg.DefaultConfig()
-		if err := configpkg.Save(defaultConfig, configPath); err != nil {
+		// Create the config YAML alongside the database, with the defaults
+		// commented out so that spells.yaml still takes effect
+		configPath := campaignConfigPath(path)
+		if err := configpkg.SaveTemplate(configPath); err != nil {
 			return fmt.Errorf("failed to create config file: %w", err)
 		}
 
@@ -5,6 +5,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func TestInitCommand(t *testing.T) {
@@ -32,3 +34,32 @@ func TestInitCommand(t *testing.T) {
 		t.Fatalf("Config file does not exist: %s", expectedConfigPath)
 	}
 }
+
+func TestInitCommand_CampaignSettings(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "campaign.db")
+
+	cmd := &cobra.Command{Use: initCmd.Use, RunE: initCmd.RunE}
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.SetArgs([]string{"--path", dbPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	settings := "torch_duration_turns: 6\ncombat:\n  initiative: side\n"
+	if err := os.WriteFile(filepath.Join(dir, campaignSettingsFile), []byte(settings), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	config, err := loadCampaignConfig(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to load campaign config: %v", err)
+	}
+	if config.Combat.Initiative != "side" || config.TorchDuration != 6 {
+		t.Errorf("Expected spells.yaml to take effect after init, got %+v", config)
+	}
+	if config.Combat.InitiativeDie != "1d6" || config.PartySize != 4 {
+		t.Errorf("Expected the defaults for settings spells.yaml leaves out, got %+v", config)
+	}
+}
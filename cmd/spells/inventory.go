@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory [name|id]",
+	Short: "Track what characters carry and how it slows them",
+	Long: `Track each character's gear, carried or stashed. Carried items count
+towards encumbrance, by slots or by weight in coins as the campaign's
+encumbrance rules say, and the encumbrance tier sets the character's
+movement rate.
+
+  spells inventory add Theron Plate mail --slots 6 --weight 500
+  spells inventory stash Theron Plate mail
+  spells inventory Theron
+
+With no character, shows the party's movement rate: that of its slowest
+member.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			if len(args) == 0 {
+				party, err := eng.PartyMovement()
+				if err != nil {
+					return err
+				}
+				for _, member := range party.Members {
+					cmd.Printf("%s: %s\n", member.Character.Name, describeEncumbrance(eng, member))
+				}
+				if party.Slowest == nil {
+					cmd.Printf("The party moves %d' per turn\n", party.Movement)
+				} else {
+					cmd.Printf("The party moves %d' per turn (%s)\n", party.Movement, party.Slowest.Character.Name)
+				}
+				return nil
+			}
+
+			character, err := findCharacter(eng.DB, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			items, err := model.ListItems(eng.DB, character.ID)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				cmd.Printf("  %s\n", describeItem(eng, item))
+			}
+			encumbrance, err := eng.Encumbrance(character.ID)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("%s: %s\n", character.Name, describeEncumbrance(eng, *encumbrance))
+			return nil
+		})
+	},
+}
+
+var inventoryAddCmd = &cobra.Command{
+	Use:   "add <name|id> <item...>",
+	Short: "Put an item in a character's inventory",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		item := &model.Item{Name: strings.Join(args[1:], " ")}
+		item.Quantity, _ = cmd.Flags().GetInt("qty")
+		item.Slots, _ = cmd.Flags().GetInt("slots")
+		item.Weight, _ = cmd.Flags().GetInt("weight")
+		item.Stashed, _ = cmd.Flags().GetBool("stashed")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			character, err := findCharacter(eng.DB, args[0])
+			if err != nil {
+				return err
+			}
+			item.CharacterID = character.ID
+			if err := eng.AddItem(item); err != nil {
+				return err
+			}
+			cmd.Printf("%s: %s\n", character.Name, describeItem(eng, *item))
+			return nil
+		})
+	},
+}
+
+var inventoryStashCmd = &cobra.Command{
+	Use:   "stash <name|id> <item...>",
+	Short: "Leave one of a character's items somewhere safe",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stashItem(cmd, args, true)
+	},
+}
+
+var inventoryCarryCmd = &cobra.Command{
+	Use:   "carry <name|id> <item...>",
+	Short: "Have a character carry a stashed item again",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return stashItem(cmd, args, false)
+	},
+}
+
+var inventoryDropCmd = &cobra.Command{
+	Use:   "drop <name|id> <item...>",
+	Short: "Take an item out of a character's inventory",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			character, err := findCharacter(eng.DB, args[0])
+			if err != nil {
+				return err
+			}
+			name := strings.Join(args[1:], " ")
+			if err := eng.DropItem(character.ID, name); err != nil {
+				return err
+			}
+			cmd.Printf("%s dropped %s\n", character.Name, name)
+			return nil
+		})
+	},
+}
+
+var exploreCmd = &cobra.Command{
+	Use:   "explore <distance>",
+	Short: "Advance time by the turns the party takes to explore a distance",
+	Long: `Advance the current session by the turns the party takes to cover a
+distance in feet at its movement rate, that of its slowest member:
+
+  spells explore 240'         240' at 90' per turn takes 3 turns`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		feet, err := parseFeet(args[0])
+		if err != nil {
+			return err
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			warnings := collectWarnings(eng)
+			exploration, err := eng.Explore(session, feet)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Explored %d' at %d' per turn in %s\n", exploration.Feet, exploration.Movement,
+				plural(exploration.Turns, "turn"))
+			if err := printClock(cmd, eng, session); err != nil {
+				return err
+			}
+			for _, warning := range *warnings {
+				cmd.Println(warning)
+			}
+			return nil
+		})
+	},
+}
+
+func stashItem(cmd *cobra.Command, args []string, stashed bool) error {
+	path, _ := cmd.Flags().GetString("path")
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		character, err := findCharacter(eng.DB, args[0])
+		if err != nil {
+			return err
+		}
+		item, err := eng.StashItem(character.ID, strings.Join(args[1:], " "), stashed)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("%s: %s\n", character.Name, describeItem(eng, *item))
+		return nil
+	})
+}
+
+// parseFeet reads a distance in feet such as 240, 240' or 240ft.
+func parseFeet(arg string) (int, error) {
+	trimmed := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(arg), "'"), "ft")
+	feet, err := strconv.Atoi(strings.TrimSpace(trimmed))
+	if err != nil {
+		return 0, fmt.Errorf("invalid distance %q, expected feet such as 240'", arg)
+	}
+	return feet, nil
+}
+
+// byWeight reports whether the campaign counts encumbrance by weight.
+func byWeight(eng *engine.Engine) bool {
+	return eng.Config != nil && eng.Config.Encumbrance.Load == engine.LoadWeight
+}
+
+// describeLoad shows a load in the campaign's units, e.g. "12 slots".
+func describeLoad(eng *engine.Engine, load int) string {
+	if byWeight(eng) {
+		return plural(int64(load), "coin")
+	}
+	return plural(int64(load), "slot")
+}
+
+func describeEncumbrance(eng *engine.Engine, encumbrance engine.Encumbrance) string {
+	return fmt.Sprintf("%s, %s, %d'", describeLoad(eng, encumbrance.Load), encumbrance.Tier, encumbrance.Movement)
+}
+
+func describeItem(eng *engine.Engine, item model.Item) string {
+	desc := item.Name
+	if item.Quantity > 1 {
+		desc += fmt.Sprintf(" x%d", item.Quantity)
+	}
+	load := item.Slots
+	if byWeight(eng) {
+		load = item.Weight
+	}
+	desc += " (" + describeLoad(eng, load) + ")"
+	if item.Stashed {
+		desc += "  stashed"
+	}
+	return desc
+}
+
+func init() {
+	addInventoryFlags(inventoryCmd)
+	inventoryCmd.AddCommand(inventoryAddCmd, inventoryStashCmd, inventoryCarryCmd, inventoryDropCmd)
+	addItemFlags(inventoryAddCmd)
+	addExploreFlags(exploreCmd)
+}
+
+func addInventoryFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+}
+
+func addItemFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("qty", 1, "how many")
+	cmd.Flags().Int("slots", 1, "inventory slots the items take up")
+	cmd.Flags().Int("weight", 0, "weight of the items in coins")
+	cmd.Flags().Bool("stashed", false, "leave the items stashed rather than carried")
+}
+
+func addExploreFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Int64("session-id", 0, "session to use (default latest)")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestInventoryCommand() *cobra.Command {
+	parent := &cobra.Command{Use: inventoryCmd.Use, Args: inventoryCmd.Args, RunE: inventoryCmd.RunE}
+	addInventoryFlags(parent)
+
+	add := &cobra.Command{Use: inventoryAddCmd.Use, Args: inventoryAddCmd.Args, RunE: inventoryAddCmd.RunE}
+	addItemFlags(add)
+	parent.AddCommand(
+		add,
+		&cobra.Command{Use: inventoryStashCmd.Use, Args: inventoryStashCmd.Args, RunE: inventoryStashCmd.RunE},
+		&cobra.Command{Use: inventoryCarryCmd.Use, Args: inventoryCarryCmd.Args, RunE: inventoryCarryCmd.RunE},
+		&cobra.Command{Use: inventoryDropCmd.Use, Args: inventoryDropCmd.Args, RunE: inventoryDropCmd.RunE},
+	)
+	return parent
+}
+
+func newTestExploreCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: exploreCmd.Use, Args: exploreCmd.Args, RunE: exploreCmd.RunE}
+	addExploreFlags(cmd)
+	return cmd
+}
+
+func TestInventoryCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	if output := runCommand(t, newTestInventoryCommand(), dbPath); output != "The party moves 120' per turn\n" {
+		t.Errorf("Unexpected output with no party: %q", output)
+	}
+
+	runCommand(t, newTestRosterCommand(), dbPath, "add", "Theron")
+	runCommand(t, newTestRosterCommand(), dbPath, "add", "Mira")
+	output := runCommand(t, newTestInventoryCommand(), dbPath, "add", "Theron", "Plate", "mail", "--slots", "6", "--weight", "500")
+	if output != "Theron: Plate mail (6 slots)\n" {
+		t.Errorf("Unexpected add output: %q", output)
+	}
+	runCommand(t, newTestInventoryCommand(), dbPath, "add", "Theron", "Torches", "--qty", "6", "--slots", "2")
+	runCommand(t, newTestInventoryCommand(), dbPath, "add", "Theron", "Tent", "--slots", "4")
+	runCommand(t, newTestInventoryCommand(), dbPath, "add", "Mira", "Spellbook")
+
+	expected := "Mira: 1 slot, unencumbered, 120'\n" +
+		"Theron: 12 slots, lightly encumbered, 90'\n" +
+		"The party moves 90' per turn (Theron)\n"
+	if output := runCommand(t, newTestInventoryCommand(), dbPath); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	expected = "Explored 240' at 90' per turn in 3 turns\nDay 1, 08:30 (morning, watch 3)\n"
+	if output := runCommand(t, newTestExploreCommand(), dbPath, "240'"); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	if output := runCommand(t, newTestInventoryCommand(), dbPath, "stash", "Theron", "tent"); output != "Theron: Tent (4 slots)  stashed\n" {
+		t.Errorf("Unexpected stash output: %q", output)
+	}
+	runCommand(t, newTestInventoryCommand(), dbPath, "drop", "Mira", "Spellbook")
+	expected = "  Plate mail (6 slots)\n" +
+		"  Torches x6 (2 slots)\n" +
+		"  Tent (4 slots)  stashed\n" +
+		"Theron: 8 slots, unencumbered, 120'\n"
+	if output := runCommand(t, newTestInventoryCommand(), dbPath, "Theron"); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+	if output := runCommand(t, newTestExploreCommand(), dbPath, "60ft"); output != "Explored 60' at 120' per turn in 1 turn\nDay 1, 08:40 (morning, watch 3)\n" {
+		t.Errorf("Unexpected explore output: %q", output)
+	}
+}
@@ -31,8 +31,28 @@ func init() {
 	rootCmd.Flags().BoolVar(&showVersion, "version", false, "show version")
 
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(sessionCmd)
 	rootCmd.AddCommand(trackCmd)
 	rootCmd.AddCommand(oracleCmd)
+	rootCmd.AddCommand(rollCmd)
+	rootCmd.AddCommand(wanderCmd)
+	rootCmd.AddCommand(clockCmd)
+	rootCmd.AddCommand(eventCmd)
+	rootCmd.AddCommand(calendarCmd)
+	rootCmd.AddCommand(timerCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(redoCmd)
+	rootCmd.AddCommand(journalCmd)
+	rootCmd.AddCommand(combatCmd)
+	rootCmd.AddCommand(monsterCmd)
+	rootCmd.AddCommand(reactCmd)
+	rootCmd.AddCommand(xpCmd)
+	rootCmd.AddCommand(treasureCmd)
+	rootCmd.AddCommand(rosterCmd)
+	rootCmd.AddCommand(lightCmd)
+	rootCmd.AddCommand(suppliesCmd)
+	rootCmd.AddCommand(inventoryCmd)
+	rootCmd.AddCommand(exploreCmd)
 }
 
 func main() {
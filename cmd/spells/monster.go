@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/importer"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var monsterCmd = &cobra.Command{
+	Use:   "monster",
+	Short: "Browse and import the monster catalog",
+	Long: `The monster catalog holds OSR stat blocks. Monsters are imported from
+markdown notes whose front matter has type: monster, with the stat block in
+ac, hd, attacks, damage, movement, morale, xp and alignment fields.`,
+}
+
+var monsterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the monsters in the catalog",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			monsters, err := model.ListMonsters(eng.DB)
+			if err != nil {
+				return err
+			}
+			if len(monsters) == 0 {
+				cmd.Println("No monsters. Import some with spells monster import.")
+				return nil
+			}
+			for _, monster := range monsters {
+				cmd.Printf("%-20s AC %d  HD %s  MV %s  ML %d  XP %d\n", monster.Name, monster.ArmorClass,
+					monster.HitDice, movement(monster.Movement), monster.Morale, monster.XP)
+			}
+			return nil
+		})
+	},
+}
+
+var monsterShowCmd = &cobra.Command{
+	Use:   "show <name...>",
+	Short: "Show a monster's stat block",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		name := strings.Join(args, " ")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			monster, _, err := eng.FindMonsters(name)
+			if err != nil {
+				return err
+			}
+			if monster == nil {
+				return fmt.Errorf("monster %q not found", name)
+			}
+
+			cmd.Println(monster.Name)
+			if monster.Description != nil {
+				cmd.Println(*monster.Description)
+			}
+			cmd.Printf("AC %d, HD %s, Att %s, MV %s, ML %d, XP %d\n", monster.ArmorClass, monster.HitDice,
+				attacks(monster), movement(monster.Movement), monster.Morale, monster.XP)
+			if monster.Alignment != nil {
+				cmd.Printf("Alignment: %s\n", *monster.Alignment)
+			}
+			return nil
+		})
+	},
+}
+
+var monsterImportCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Import monsters and NPCs from markdown notes",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			before, err := model.ListMonsters(eng.DB)
+			if err != nil {
+				return err
+			}
+
+			watcher, err := importer.NewWatcher(eng.DB)
+			if err != nil {
+				return err
+			}
+			defer watcher.Stop()
+			if err := watcher.Import(args[0]); err != nil {
+				return err
+			}
+
+			after, err := model.ListMonsters(eng.DB)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Imported notes from %s: %s in the catalog (%d new)\n", args[0],
+				plural(int64(len(after)), "monster"), len(after)-len(before))
+			return nil
+		})
+	},
+}
+
+// movement formats feet per turn with the per-round rate, as in 120' (40').
+func movement(feet int) string {
+	return fmt.Sprintf("%d' (%d')", feet, feet/3)
+}
+
+func attacks(monster *model.Monster) string {
+	attacks := "-"
+	if monster.Attacks != nil {
+		attacks = *monster.Attacks
+	}
+	if monster.Damage != nil {
+		attacks += " (" + *monster.Damage + ")"
+	}
+	return attacks
+}
+
+func init() {
+	addMonsterFlags(monsterCmd)
+	monsterCmd.AddCommand(monsterListCmd, monsterShowCmd, monsterImportCmd)
+}
+
+func addMonsterFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestMonsterCommand() *cobra.Command {
+	parent := &cobra.Command{Use: monsterCmd.Use}
+	addMonsterFlags(parent)
+	for _, source := range []*cobra.Command{monsterListCmd, monsterShowCmd, monsterImportCmd} {
+		parent.AddCommand(&cobra.Command{Use: source.Use, Args: source.Args, RunE: source.RunE})
+	}
+	return parent
+}
+
+func TestMonsterCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	notes := filepath.Join(filepath.Dir(dbPath), "notes")
+	if err := os.MkdirAll(notes, 0755); err != nil {
+		t.Fatalf("Failed to create notes dir: %v", err)
+	}
+	goblin := "---\ntype: monster\nname: Goblin\nac: 6\nhd: 1-1\nattacks: 1 weapon\ndamage: 1d6\nmovement: 60\nmorale: 12\nxp: 5\n---\n"
+	if err := os.WriteFile(filepath.Join(notes, "goblin.md"), []byte(goblin), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+
+	if output := runCommand(t, newTestMonsterCommand(), dbPath, "list"); !strings.Contains(output, "No monsters") {
+		t.Errorf("Unexpected empty list output: %q", output)
+	}
+	if output := runCommand(t, newTestMonsterCommand(), dbPath, "import", notes); !strings.Contains(output, "1 monster in the catalog (1 new)") {
+		t.Errorf("Unexpected import output: %q", output)
+	}
+	if output := runCommand(t, newTestMonsterCommand(), dbPath, "list"); output != "Goblin               AC 6  HD 1-1  MV 60' (20')  ML 12  XP 5\n" {
+		t.Errorf("Unexpected list output: %q", output)
+	}
+	if output := runCommand(t, newTestMonsterCommand(), dbPath, "show", "goblins"); !strings.Contains(output, "AC 6, HD 1-1, Att 1 weapon (1d6), MV 60' (20'), ML 12, XP 5") {
+		t.Errorf("Unexpected show output: %q", output)
+	}
+
+	runCommand(t, newTestCombatCommand(), dbPath, "start")
+	output := runCommand(t, newTestCombatCommand(), dbPath, "add", "2", "goblins")
+	if !strings.Contains(output, "Added combatant 1: Goblin 1 (") || !strings.Contains(output, "Added combatant 2: Goblin 2 (") {
+		t.Errorf("Unexpected add output: %q", output)
+	}
+	if output := runCommand(t, newTestCombatCommand(), dbPath, "add", "Goblin", "--hp", "3"); output != "Added combatant 3: Goblin\n" {
+		t.Errorf("Expected --hp to add a plain combatant, got %q", output)
+	}
+
+	if output := runCommand(t, newTestCombatCommand(), dbPath, "damage", "1", "20"); !strings.Contains(output, "Goblin 1: 0/") ||
+		!strings.Contains(output, "Morale (monsters, first death): Goblin 2 ") {
+		t.Errorf("Expected the first death to check morale, got %q", output)
+	}
+	if output := runCommand(t, newTestCombatCommand(), dbPath, "morale"); !strings.HasPrefix(output, "Morale (monsters, called): ") {
+		t.Errorf("Unexpected morale output: %q", output)
+	}
+}
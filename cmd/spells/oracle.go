@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"time"
 
+	configpkg "github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
 	"github.com/script-wizards/spells/internal/oracle"
 	"github.com/spf13/cobra"
 )
@@ -14,37 +19,172 @@ var oracleCmd = &cobra.Command{
 	Use:   "oracle [text]",
 	Short: "Parse and resolve oracle text with choices, tables, and dice",
 	Long: `Parse oracle text containing:
-- Choices: {option1|option2|option3}  
+- Choices: {option1|option2|option3}
 - Tables: [table_name]
-- Dice: 1d4, 2d6, etc.
+- Dice: 1d4, 2d6+1, 4d6kh3, 1d6!, 1d8r1, etc.
 - Plain text
 
-Returns JSON with the resolved result.`,
-	Args: cobra.ExactArgs(1),
+Tables are loaded from *.table and *.perchance files in the global
+tables directory (~/.config/spells/tables) and the campaign directory.
+Campaign tables override global tables with the same name. Nested
+table references deeper than --depth are left as [table_name].
+
+Returns JSON with the resolved result. With --json the output also holds
+the resolution tree: one node per choice, table, dice roll and text span,
+with the option picked and the individual dice rolled.
+
+Each result is saved to the campaign database, when there is one, against
+the current session. Use "spells oracle history" to look them up again.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if tableName, _ := cmd.Flags().GetString("table"); tableName != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		input := args[0]
+		path, _ := cmd.Flags().GetString("path")
+		tableName, _ := cmd.Flags().GetString("table")
+		jsonTree, _ := cmd.Flags().GetBool("json")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		depth, err := cmd.Flags().GetInt("depth")
+		if err != nil {
+			return err
+		}
+
+		tables, err := loadOracleTables(path)
+		if err != nil {
+			return err
+		}
+
+		var input string
+		if tableName != "" {
+			if _, exists := tables[tableName]; !exists {
+				return fmt.Errorf("table %q not found", tableName)
+			}
+			input = fmt.Sprintf("[%s]", tableName)
+		} else {
+			input = args[0]
+		}
 
-		// Create resolver with empty tables and random seed
 		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-		resolver := oracle.NewResolver(map[string]string{}, rng)
+		resolver := oracle.NewResolver(tables, rng)
+		resolver.SetMaxDepth(depth)
 
-		result, err := resolver.Resolve(input)
+		tree, err := resolver.ResolveTree(input)
 		if err != nil {
 			return fmt.Errorf("failed to resolve oracle: %w", err)
 		}
 
-		// Create JSON output
-		output := map[string]interface{}{
-			"input":  input,
-			"result": result,
+		if tableName == "" && len(tree.Children) == 1 && tree.Children[0].Type == oracle.NodeTable {
+			tableName = tree.Children[0].Table
 		}
 
-		jsonBytes, err := json.MarshalIndent(output, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
+		if err := logOracleResult(path, sessionID, tableName, tree); err != nil {
+			return err
 		}
 
-		cmd.Println(string(jsonBytes))
-		return nil
+		return printOracleResult(cmd, input, tableName, tree, jsonTree)
 	},
 }
+
+func printOracleResult(cmd *cobra.Command, input, tableName string, tree *oracle.Node, jsonTree bool) error {
+	// Create JSON output
+	output := map[string]interface{}{
+		"input":  input,
+		"result": tree.Result,
+	}
+	if tableName != "" {
+		output["table"] = tableName
+	}
+	if jsonTree {
+		output["tree"] = tree
+	}
+
+	jsonBytes, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	cmd.Println(string(jsonBytes))
+	return nil
+}
+
+// logOracleResult records a consultation in the campaign database at dbPath
+// against the current session. Nothing is logged when there is no database.
+func logOracleResult(dbPath string, sessionID int64, tableName string, tree *oracle.Node) error {
+	if dbPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	currentSession, err := currentSessionID(database, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+
+	nested, err := json.Marshal(tree.Children)
+	if err != nil {
+		return fmt.Errorf("failed to marshal oracle tree: %w", err)
+	}
+	nestedStr := string(nested)
+
+	result := &model.OracleResult{
+		QueryContext:  &tree.Source,
+		Result:        tree.Result,
+		NestedResults: &nestedStr,
+		SessionID:     currentSession,
+	}
+	if tableName != "" {
+		result.TableName = &tableName
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := model.CreateOracleResult(tx, result); err != nil {
+		return fmt.Errorf("failed to save oracle result: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// loadOracleTables loads the global tables followed by the tables in the
+// campaign directory holding the database at dbPath.
+func loadOracleTables(dbPath string) (map[string]*oracle.List, error) {
+	globalDir, err := configpkg.TablesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate global tables: %w", err)
+	}
+
+	campaignDir := filepath.Dir(dbPath)
+
+	tables, err := oracle.LoadTables(globalDir, campaignDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tables: %w", err)
+	}
+	return tables, nil
+}
+
+func init() {
+	addOracleFlags(oracleCmd)
+	oracleCmd.AddCommand(oracleHistoryCmd)
+}
+
+func addOracleFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().String("table", "", "roll a whole table by name")
+	cmd.Flags().Bool("json", false, "include the full resolution tree in the output")
+	cmd.Flags().Int("depth", oracle.DefaultMaxDepth, "levels of nested tables to resolve automatically")
+	cmd.Flags().Int64("session-id", 0, "session to log the result against (default latest)")
+}
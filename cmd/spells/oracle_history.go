@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/script-wizards/spells/internal/oracle"
+	"github.com/spf13/cobra"
+)
+
+var oracleHistoryCmd = &cobra.Command{
+	Use:   "history [id]",
+	Short: "List and re-show past oracle results",
+	Long: `List past oracle results, newest first. Filter by session, table,
+text, or age. Pass a result ID to show that result again in the same
+format "spells oracle --json" prints.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		tableName, _ := cmd.Flags().GetString("table")
+		contains, _ := cmd.Flags().GetString("contains")
+		since, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		database, err := db.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer database.Close()
+
+		if len(args) == 1 {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid result ID %q", args[0])
+			}
+
+			result, err := model.GetOracleResult(database, id)
+			if err != nil {
+				return err
+			}
+			if result == nil {
+				return fmt.Errorf("oracle result %d not found", id)
+			}
+			return showOracleResult(cmd, result)
+		}
+
+		filter := model.OracleResultFilter{
+			TableName: tableName,
+			Contains:  contains,
+			Limit:     limit,
+		}
+		if sessionID > 0 {
+			filter.SessionID = &sessionID
+		}
+		if since != "" {
+			age, err := parseAge(since)
+			if err != nil {
+				return err
+			}
+			cutoff := time.Now().Add(-age)
+			filter.Since = &cutoff
+		}
+
+		results, err := model.ListOracleResults(database, filter)
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			jsonBytes, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			cmd.Println(string(jsonBytes))
+			return nil
+		}
+
+		if len(results) == 0 {
+			cmd.Println("No oracle results found.")
+			return nil
+		}
+
+		for _, result := range results {
+			source := ""
+			if result.TableName != nil {
+				source = fmt.Sprintf("[%s]", *result.TableName)
+			} else if result.QueryContext != nil {
+				source = *result.QueryContext
+			}
+			cmd.Printf("%4d  %s  %s → %s\n", result.ID,
+				result.CreatedAt.Local().Format("2006-01-02 15:04"), source, result.Result)
+		}
+		return nil
+	},
+}
+
+// showOracleResult prints a saved result with its resolution tree.
+func showOracleResult(cmd *cobra.Command, result *model.OracleResult) error {
+	tree := &oracle.Node{
+		Type:   oracle.NodeExpression,
+		Result: result.Result,
+	}
+	if result.QueryContext != nil {
+		tree.Source = *result.QueryContext
+	}
+	if result.NestedResults != nil {
+		if err := json.Unmarshal([]byte(*result.NestedResults), &tree.Children); err != nil {
+			return fmt.Errorf("failed to parse saved oracle tree: %w", err)
+		}
+	}
+
+	tableName := ""
+	if result.TableName != nil {
+		tableName = *result.TableName
+	}
+	return printOracleResult(cmd, tree.Source, tableName, tree, true)
+}
+
+// parseAge parses a duration such as "90m", "12h", or "7d".
+func parseAge(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return age, nil
+}
+
+func init() {
+	addOracleHistoryFlags(oracleHistoryCmd)
+}
+
+func addOracleHistoryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Int64("session-id", 0, "only show results from this session")
+	cmd.Flags().String("table", "", "only show results from this table")
+	cmd.Flags().String("contains", "", "only show results containing this text")
+	cmd.Flags().String("since", "", "only show results newer than this age, e.g. 12h or 7d")
+	cmd.Flags().Int("limit", 20, "maximum number of results to list")
+	cmd.Flags().Bool("json", false, "output results as JSON")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func TestOracleHistory(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+	tmpDir := filepath.Dir(dbPath)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "tavern_rumor.table"), []byte("the mayor is {a doppelganger|a vampire}"), 0644); err != nil {
+		t.Fatalf("Failed to write table: %v", err)
+	}
+
+	runOracle := func(args ...string) string {
+		t.Helper()
+		cmd := &cobra.Command{Use: oracleCmd.Use, Args: oracleCmd.Args, RunE: oracleCmd.RunE}
+		addOracleFlags(cmd)
+		return runCommand(t, cmd, dbPath, args...)
+	}
+
+	runOracle("--table", "tavern_rumor")
+	runOracle("1d6 goblins")
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	results, err := model.ListOracleResults(database, model.OracleResultFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list oracle results: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 logged results, got %d", len(results))
+	}
+	rumor := results[1]
+	if rumor.TableName == nil || *rumor.TableName != "tavern_rumor" {
+		t.Errorf("Expected table name tavern_rumor, got %v", rumor.TableName)
+	}
+	if rumor.SessionID == nil || *rumor.SessionID != 1 {
+		t.Errorf("Expected result logged against session 1, got %v", rumor.SessionID)
+	}
+
+	runHistory := func(args ...string) string {
+		t.Helper()
+		cmd := &cobra.Command{Use: oracleHistoryCmd.Use, Args: oracleHistoryCmd.Args, RunE: oracleHistoryCmd.RunE}
+		addOracleHistoryFlags(cmd)
+		return runCommand(t, cmd, dbPath, args...)
+	}
+
+	listing := runHistory("--table", "tavern_rumor")
+	if !strings.Contains(listing, "[tavern_rumor] → the mayor is") || strings.Contains(listing, "goblins") {
+		t.Errorf("Unexpected filtered listing:\n%s", listing)
+	}
+
+	shown := runHistory(strconv.FormatInt(rumor.ID, 10))
+	var output map[string]interface{}
+	if err := json.Unmarshal([]byte(shown), &output); err != nil {
+		t.Fatalf("Re-shown result is not valid JSON: %v\nOutput: %s", err, shown)
+	}
+	if output["result"] != rumor.Result || output["tree"] == nil {
+		t.Errorf("Unexpected re-shown result: %v", output)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for value, expected := range tests {
+		age, err := parseAge(value)
+		if err != nil || age != expected {
+			t.Errorf("parseAge(%q) = %v, %v; expected %v", value, age, err, expected)
+		}
+	}
+
+	if _, err := parseAge("soon"); err == nil {
+		t.Error("Expected error for invalid age")
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -61,6 +63,7 @@ func TestOracleCommand(t *testing.T) {
 				Args:  oracleCmd.Args,
 				RunE:  oracleCmd.RunE,
 			}
+			addOracleFlags(cmd)
 
 			// Capture output
 			var buf bytes.Buffer
@@ -124,6 +127,7 @@ func TestOracleCommandComplexInput(t *testing.T) {
 		Args:  cobra.ExactArgs(1),
 		RunE:  oracleCmd.RunE,
 	}
+	addOracleFlags(cmd)
 
 	// Capture output
 	var buf bytes.Buffer
@@ -168,3 +172,90 @@ func TestOracleCommandComplexInput(t *testing.T) {
 		t.Errorf("Expected dice to be resolved, but found '1d4' in result: %s", resultStr)
 	}
 }
+
+func TestOracleCommandTableFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "encounter.table"), []byte("1d4 [creature]"), 0644); err != nil {
+		t.Fatalf("Failed to write table: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "creature.table"), []byte("goblins"), 0644); err != nil {
+		t.Fatalf("Failed to write table: %v", err)
+	}
+
+	cmd := &cobra.Command{
+		Use:  oracleCmd.Use,
+		Args: oracleCmd.Args,
+		RunE: oracleCmd.RunE,
+	}
+	addOracleFlags(cmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"--path", filepath.Join(tmpDir, "campaign.db"), "--table", "encounter"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, buf.String())
+	}
+
+	if result["table"] != "encounter" {
+		t.Errorf("Expected table to be %q, got %v", "encounter", result["table"])
+	}
+
+	resultStr, _ := result["result"].(string)
+	if !strings.HasSuffix(resultStr, " goblins") {
+		t.Errorf("Expected result to end with ' goblins', got %q", resultStr)
+	}
+}
+
+func TestOracleCommandJSONTree(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cmd := &cobra.Command{
+		Use:  oracleCmd.Use,
+		Args: oracleCmd.Args,
+		RunE: oracleCmd.RunE,
+	}
+	addOracleFlags(cmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"--path", filepath.Join(t.TempDir(), "campaign.db"), "--json", "2d6 {goblins|orcs}"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var result struct {
+		Result string `json:"result"`
+		Tree   struct {
+			Type     string `json:"type"`
+			Result   string `json:"result"`
+			Children []struct {
+				Type  string `json:"type"`
+				Rolls []int  `json:"rolls"`
+			} `json:"children"`
+		} `json:"tree"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\nOutput: %s", err, buf.String())
+	}
+
+	if result.Tree.Type != "expression" || result.Tree.Result != result.Result {
+		t.Fatalf("Unexpected tree root: %+v", result.Tree)
+	}
+	if len(result.Tree.Children) != 3 {
+		t.Fatalf("Expected 3 tree children, got %d", len(result.Tree.Children))
+	}
+	if dice := result.Tree.Children[0]; dice.Type != "dice" || len(dice.Rolls) != 2 {
+		t.Errorf("Expected dice node with 2 rolls, got %+v", dice)
+	}
+}
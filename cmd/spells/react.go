@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var reactCmd = &cobra.Command{
+	Use:   "react <npc...>",
+	Short: "Roll an NPC's reaction to the party",
+	Long: `Roll 2d6 on the reaction table for an NPC, given by name or ID: 2 or
+less attacks, 3-5 hostile, 6-8 uncertain, 9-11 indifferent and 12 or more
+friendly. The NPC's status becomes hostile, neutral or ally to match,
+unless --keep-status is set.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		modifier, _ := cmd.Flags().GetInt("mod")
+		keep, _ := cmd.Flags().GetBool("keep-status")
+		name := strings.Join(args, " ")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			npc, err := model.GetNPCByName(eng.DB, name)
+			if err != nil {
+				return err
+			}
+			if npc == nil {
+				if id, err := strconv.ParseInt(name, 10, 64); err == nil {
+					npc, err = model.GetNPC(eng.DB, id)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if npc == nil {
+				return fmt.Errorf("npc %q not found", name)
+			}
+
+			rolled, err := eng.RollReaction(npc.ID, modifier, !keep)
+			if err != nil {
+				return err
+			}
+
+			roll := strconv.Itoa(rolled.Roll)
+			if rolled.Modifier != 0 {
+				roll = fmt.Sprintf("%d%+d = %d", rolled.Roll, rolled.Modifier, rolled.Total)
+			}
+			cmd.Printf("%s: %s (%s)\n", rolled.Name, rolled.Reaction, roll)
+			if rolled.Applied {
+				cmd.Printf("%s is now %s (was %s)\n", rolled.Name, rolled.Status, rolled.OldStatus)
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	addReactFlags(reactCmd)
+}
+
+func addReactFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Int("mod", 0, "modifier to the roll, e.g. the speaker's Charisma bonus")
+	cmd.Flags().Bool("keep-status", false, "roll without changing the NPC's status")
+}
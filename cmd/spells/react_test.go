@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func TestReactCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "campaign.db")
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	npc := &model.NPC{Name: "Gareth the Merchant", Status: model.NPCNeutral}
+	if err := model.CreateNPC(tx, npc); err != nil {
+		t.Fatalf("Failed to create npc: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	defer database.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := &cobra.Command{Use: reactCmd.Use, Args: reactCmd.Args, RunE: reactCmd.RunE}
+		addReactFlags(cmd)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append(args, "--path", dbPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	output, err := run("gareth", "the", "merchant", "--mod", "-12", "--keep-status")
+	if err != nil {
+		t.Fatalf("react failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "Gareth the Merchant: attacks (") || strings.Contains(output, "is now") {
+		t.Errorf("Unexpected kept reaction output: %q", output)
+	}
+
+	output, err = run("1", "--mod", "12")
+	if err != nil {
+		t.Fatalf("react failed: %v", err)
+	}
+	if !strings.Contains(output, "friendly") || !strings.Contains(output, "Gareth the Merchant is now ally (was neutral)") {
+		t.Errorf("Unexpected reaction output: %q", output)
+	}
+	if stored, _ := model.GetNPC(database, npc.ID); stored.Status != model.NPCAlly {
+		t.Errorf("Expected Gareth to be an ally, got %s", stored.Status)
+	}
+
+	if _, err := run("Nobody"); err == nil {
+		t.Error("Expected an error for an unknown npc")
+	}
+}
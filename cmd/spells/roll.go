@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var rollCmd = &cobra.Command{
+	Use:   "roll <expression|macro>",
+	Short: "Roll dice and log the result to the current session",
+	Long: `Roll a dice expression such as 2d6+1, 4d6kh3, 1d6! or 1d8r1 and print
+the dice rolled for each term. Dropped dice are shown in parentheses.
+
+Named macros from the campaign config can be rolled by name:
+
+  macros:
+    reaction: 2d6
+    morale: 2d6
+
+  spells roll reaction
+
+Each roll is saved to the campaign database, when there is one, against
+the current session.
+
+With --stats the expression is not rolled. Instead the exact odds of
+every total are printed with the mean, standard deviation and a
+histogram.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		showStats, _ := cmd.Flags().GetBool("stats")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		config, err := loadCampaignConfig(path)
+		if err != nil {
+			return err
+		}
+
+		expr, macro := args[0], ""
+		if macroExpr, ok := config.Macros[args[0]]; ok {
+			expr, macro = macroExpr, args[0]
+		}
+
+		if showStats {
+			return printDiceStats(cmd, expr, macro, asJSON)
+		}
+
+		if !cmd.Flags().Changed("seed") {
+			seed = time.Now().UnixNano()
+		}
+		rng := rand.New(rand.NewSource(seed))
+
+		result, err := dice.Evaluate(expr, rng)
+		if err != nil {
+			return err
+		}
+
+		if err := logDiceRoll(path, sessionID, macro, result); err != nil {
+			return err
+		}
+
+		if asJSON {
+			output := struct {
+				Macro string `json:"macro,omitempty"`
+				*dice.Result
+			}{macro, result}
+
+			jsonBytes, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			cmd.Println(string(jsonBytes))
+			return nil
+		}
+
+		if macro != "" {
+			cmd.Printf("%s: %s\n", macro, result.Breakdown())
+		} else {
+			cmd.Println(result.Breakdown())
+		}
+		return nil
+	},
+}
+
+// printDiceStats prints the exact distribution of expr instead of rolling
+// it.
+func printDiceStats(cmd *cobra.Command, expr, macro string, asJSON bool) error {
+	stats, err := dice.Distribution(expr)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		output := struct {
+			Macro string `json:"macro,omitempty"`
+			*dice.Stats
+		}{macro, stats}
+
+		jsonBytes, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		cmd.Println(string(jsonBytes))
+		return nil
+	}
+
+	label := stats.Expr
+	if macro != "" {
+		label = fmt.Sprintf("%s (%s)", macro, stats.Expr)
+	}
+	cmd.Printf("%s: min %d, max %d, mean %.2f, sd %.2f\n\n", label, stats.Min, stats.Max, stats.Mean, stats.StdDev)
+	cmd.Print(stats.Histogram(40))
+	return nil
+}
+
+// logDiceRoll records a roll in the campaign database at dbPath against the
+// current session. Nothing is logged when there is no database.
+func logDiceRoll(dbPath string, sessionID int64, macro string, result *dice.Result) error {
+	if dbPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	currentSession, err := currentSessionID(database, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+
+	breakdown, err := json.Marshal(result.Terms)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dice breakdown: %w", err)
+	}
+	breakdownStr := string(breakdown)
+
+	roll := &model.DiceRoll{
+		Expression: result.Expr,
+		Total:      result.Total,
+		Breakdown:  &breakdownStr,
+		SessionID:  currentSession,
+	}
+	if macro != "" {
+		roll.Macro = &macro
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := model.CreateDiceRoll(tx, roll); err != nil {
+		return fmt.Errorf("failed to save dice roll: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func init() {
+	addRollFlags(rollCmd)
+}
+
+func addRollFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Bool("json", false, "output the roll as JSON")
+	cmd.Flags().Int64("seed", 0, "seed the dice for a repeatable roll")
+	cmd.Flags().Bool("stats", false, "show the probability distribution instead of rolling")
+	cmd.Flags().Int64("session-id", 0, "session to log the roll against (default latest)")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func runRollCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{Use: rollCmd.Use, Args: rollCmd.Args, RunE: rollCmd.RunE}
+	addRollFlags(cmd)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestRollCommandSeed(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "campaign.db")
+
+	first, err := runRollCommand(t, "--path", dbPath, "--seed", "42", "4d6kh3+1")
+	if err != nil {
+		t.Fatalf("roll failed: %v", err)
+	}
+	second, err := runRollCommand(t, "--path", dbPath, "--seed", "42", "4d6kh3+1")
+	if err != nil {
+		t.Fatalf("roll failed: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("Expected the same seed to give the same roll, got %q and %q", first, second)
+	}
+	if !strings.HasPrefix(first, "4d6kh3 [") || !strings.Contains(first, "(") {
+		t.Errorf("Expected a breakdown with a dropped die, got %q", first)
+	}
+}
+
+func TestRollCommandJSON(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "campaign.db")
+
+	output, err := runRollCommand(t, "--path", dbPath, "--seed", "1", "--json", "2d6+1")
+	if err != nil {
+		t.Fatalf("roll failed: %v", err)
+	}
+
+	var result struct {
+		Expr  string `json:"expr"`
+		Total int    `json:"total"`
+		Terms []struct {
+			Term  string `json:"term"`
+			Rolls []int  `json:"rolls"`
+		} `json:"terms"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", output, err)
+	}
+	if result.Expr != "2d6+1" || len(result.Terms) != 2 || len(result.Terms[0].Rolls) != 2 {
+		t.Fatalf("Unexpected roll: %+v", result)
+	}
+	if result.Total != result.Terms[0].Rolls[0]+result.Terms[0].Rolls[1]+1 {
+		t.Errorf("Total %d does not match rolls %v", result.Total, result.Terms[0].Rolls)
+	}
+}
+
+func TestRollCommandMacroAndLog(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+	tmpDir := filepath.Dir(dbPath)
+
+	config := "macros:\n  surprise: 1d6\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "campaign.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	output, err := runRollCommand(t, "--path", dbPath, "surprise")
+	if err != nil {
+		t.Fatalf("roll failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "surprise: 1d6 [") {
+		t.Errorf("Expected surprise macro breakdown, got %q", output)
+	}
+
+	if _, err := runRollCommand(t, "--path", dbPath, "reaction"); err != nil {
+		t.Fatalf("default reaction macro failed: %v", err)
+	}
+	if _, err := runRollCommand(t, "--path", dbPath, "goblins"); err == nil {
+		t.Error("Expected an error for an unknown macro")
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	sessionID := int64(1)
+	rolls, err := model.ListDiceRolls(database, &sessionID, 0)
+	if err != nil {
+		t.Fatalf("Failed to list dice rolls: %v", err)
+	}
+	if len(rolls) != 2 {
+		t.Fatalf("Expected 2 logged rolls, got %d", len(rolls))
+	}
+	if rolls[1].Macro == nil || *rolls[1].Macro != "surprise" || rolls[1].Expression != "1d6" {
+		t.Errorf("Unexpected logged roll: %+v", rolls[1])
+	}
+	if rolls[0].Expression != "2d6" {
+		t.Errorf("Expected reaction macro to roll 2d6, got %q", rolls[0].Expression)
+	}
+}
+
+func TestRollCommandStats(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "campaign.db")
+
+	output, err := runRollCommand(t, "--path", dbPath, "--stats", "reaction")
+	if err != nil {
+		t.Fatalf("roll --stats failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "reaction (2d6): min 2, max 12, mean 7.00, sd 2.42") {
+		t.Errorf("Unexpected stats summary: %q", output)
+	}
+	if !strings.Contains(output, " 9   11.11%   27.78%  ") {
+		t.Errorf("Expected a histogram row for 9, got %q", output)
+	}
+
+	output, err = runRollCommand(t, "--path", dbPath, "--stats", "--json", "2d6")
+	if err != nil {
+		t.Fatalf("roll --stats --json failed: %v", err)
+	}
+	var stats struct {
+		Mean     float64 `json:"mean"`
+		Outcomes []struct {
+			Value   int     `json:"value"`
+			AtLeast float64 `json:"at_least"`
+		} `json:"outcomes"`
+	}
+	if err := json.Unmarshal([]byte(output), &stats); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", output, err)
+	}
+	if stats.Mean != 7 || len(stats.Outcomes) != 11 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var rosterCmd = &cobra.Command{
+	Use:   "roster",
+	Short: "Manage the party's player characters",
+	Long: `The roster holds a record for each player character: player, class,
+level, hit points, AC, XP and light source. With no subcommand, lists it.
+
+  spells roster add Theron --player Alex --class Fighter --hp 12 --ac 4
+  spells roster set Theron --level 2 --light lantern
+  spells combat party                add the active characters to combat
+
+Damage and healing in combat carry over to the character's record.
+Inactive characters stay on the roster but sit out of the party.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			characters, err := model.ListCharacters(eng.DB, false)
+			if err != nil {
+				return err
+			}
+			if len(characters) == 0 {
+				cmd.Println("The roster is empty. Add characters with spells roster add.")
+				return nil
+			}
+			for _, character := range characters {
+				cmd.Printf("%4d  %s\n", character.ID, describeCharacter(character))
+			}
+			return nil
+		})
+	},
+}
+
+var rosterAddCmd = &cobra.Command{
+	Use:   "add <name...>",
+	Short: "Add a character to the roster",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		character := &model.Character{Name: strings.Join(args, " "), IsActive: true}
+		setCharacterFields(cmd, character)
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			if err := eng.AddCharacter(character); err != nil {
+				return err
+			}
+			cmd.Printf("Added character %d: %s\n", character.ID, describeCharacter(*character))
+			return nil
+		})
+	},
+}
+
+var rosterSetCmd = &cobra.Command{
+	Use:   "set <name|id>",
+	Short: "Change a character's record",
+	Long: `Change the fields of a character's record given as flags, e.g.
+--level 2 or --hp-current 5. --inactive takes the character out of the
+party and --active puts them back.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		active, _ := cmd.Flags().GetBool("active")
+		inactive, _ := cmd.Flags().GetBool("inactive")
+		if active && inactive {
+			return fmt.Errorf("a character cannot be both active and inactive")
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			character, err := findCharacter(eng.DB, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			setCharacterFields(cmd, character)
+			if active || inactive {
+				character.IsActive = active
+			}
+			if err := eng.UpdateCharacter(character); err != nil {
+				return err
+			}
+			cmd.Println(describeCharacter(*character))
+			return nil
+		})
+	},
+}
+
+var rosterRemoveCmd = &cobra.Command{
+	Use:   "remove <name|id>",
+	Short: "Take a character off the roster",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			character, err := findCharacter(eng.DB, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			if err := eng.RemoveCharacter(character.ID); err != nil {
+				return err
+			}
+			cmd.Printf("Removed %s from the roster\n", character.Name)
+			return nil
+		})
+	},
+}
+
+// findCharacter looks a character up by name, or by ID.
+func findCharacter(database *sqlx.DB, arg string) (*model.Character, error) {
+	character, err := model.GetCharacterByName(database, arg)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+			if character, err = model.GetCharacter(database, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if character == nil {
+		return nil, fmt.Errorf("character %q not found", arg)
+	}
+	return character, nil
+}
+
+// setCharacterFields copies the record flags that were given onto a
+// character.
+func setCharacterFields(cmd *cobra.Command, character *model.Character) {
+	flags := cmd.Flags()
+	text := func(name string, field **string) {
+		if flags.Changed(name) {
+			value, _ := flags.GetString(name)
+			*field = &value
+		}
+	}
+	number := func(name string, field **int) {
+		if flags.Changed(name) {
+			value, _ := flags.GetInt(name)
+			*field = &value
+		}
+	}
+
+	text("player", &character.Player)
+	text("class", &character.Class)
+	text("light", &character.LightSource)
+	number("hp", &character.HPMax)
+	number("hp-current", &character.HPCurrent)
+	number("ac", &character.ArmorClass)
+	if flags.Changed("level") {
+		character.Level, _ = flags.GetInt("level")
+	}
+	if flags.Changed("xp") {
+		character.XP, _ = flags.GetInt("xp")
+	}
+}
+
+// describeCharacter sums up a character's record on one line.
+func describeCharacter(character model.Character) string {
+	desc := character.Name
+	if character.Player != nil {
+		desc += " (" + *character.Player + ")"
+	}
+	class := "level"
+	if character.Class != nil {
+		class = *character.Class
+	}
+	desc += fmt.Sprintf("  %s %d", class, character.Level)
+	desc += "  " + combatantHP(model.Combatant{HPCurrent: character.HPCurrent, HPMax: character.HPMax})
+	if character.ArmorClass != nil {
+		desc += fmt.Sprintf("  AC %d", *character.ArmorClass)
+	}
+	desc += fmt.Sprintf("  XP %d", character.XP)
+	if character.LightSource != nil {
+		desc += "  " + describeLight(character)
+	}
+	if !character.IsActive {
+		desc += "  inactive"
+	}
+	return desc
+}
+
+func init() {
+	addRosterFlags(rosterCmd)
+	rosterCmd.AddCommand(rosterAddCmd, rosterSetCmd, rosterRemoveCmd)
+	addCharacterFlags(rosterAddCmd)
+	addRosterSetFlags(rosterSetCmd)
+}
+
+func addRosterFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+}
+
+func addCharacterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("player", "", "who plays the character")
+	cmd.Flags().String("class", "", "the character's class")
+	cmd.Flags().Int("level", 1, "the character's level")
+	cmd.Flags().Int("hp", 0, "maximum hit points")
+	cmd.Flags().Int("hp-current", 0, "current hit points (default the maximum)")
+	cmd.Flags().Int("ac", 0, "armor class, in the campaign's AC system")
+	cmd.Flags().Int("xp", 0, "experience points")
+	cmd.Flags().String("light", "", "light source carried, e.g. torch or lantern")
+}
+
+func addRosterSetFlags(cmd *cobra.Command) {
+	addCharacterFlags(cmd)
+	cmd.Flags().Bool("active", false, "put the character back in the party")
+	cmd.Flags().Bool("inactive", false, "take the character out of the party")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestRosterCommand() *cobra.Command {
+	parent := &cobra.Command{Use: rosterCmd.Use, Args: rosterCmd.Args, RunE: rosterCmd.RunE}
+	addRosterFlags(parent)
+
+	add := &cobra.Command{Use: rosterAddCmd.Use, Args: rosterAddCmd.Args, RunE: rosterAddCmd.RunE}
+	addCharacterFlags(add)
+	set := &cobra.Command{Use: rosterSetCmd.Use, Args: rosterSetCmd.Args, RunE: rosterSetCmd.RunE}
+	addRosterSetFlags(set)
+	remove := &cobra.Command{Use: rosterRemoveCmd.Use, Args: rosterRemoveCmd.Args, RunE: rosterRemoveCmd.RunE}
+	parent.AddCommand(add, set, remove)
+	return parent
+}
+
+func TestRosterCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	if output := runCommand(t, newTestRosterCommand(), dbPath); output != "The roster is empty. Add characters with spells roster add.\n" {
+		t.Errorf("Unexpected empty roster output: %q", output)
+	}
+	output := runCommand(t, newTestRosterCommand(), dbPath, "add", "Theron", "--player", "Alex", "--class", "Fighter", "--hp", "12", "--ac", "4", "--light", "torch")
+	if output != "Added character 1: Theron (Alex)  Fighter 1  12/12 HP  AC 4  XP 0  torch\n" {
+		t.Errorf("Unexpected add output: %q", output)
+	}
+	runCommand(t, newTestRosterCommand(), dbPath, "add", "Gareth", "--class", "Cleric", "--hp", "6")
+	runCommand(t, newTestRosterCommand(), dbPath, "add", "Mira")
+	if output := runCommand(t, newTestRosterCommand(), dbPath, "set", "mira", "--inactive", "--level", "3"); output != "Mira  level 3  ? HP  XP 0  inactive\n" {
+		t.Errorf("Unexpected set output: %q", output)
+	}
+
+	runCommand(t, newTestCombatCommand(), dbPath, "start")
+	output = runCommand(t, newTestCombatCommand(), dbPath, "party", "--init", "4")
+	if output != "Added combatant 1: Gareth\nAdded combatant 2: Theron\n" {
+		t.Errorf("Unexpected party output: %q", output)
+	}
+	if output := runCommand(t, newTestCombatCommand(), dbPath, "party"); output != "The party is already in the encounter.\n" {
+		t.Errorf("Unexpected second party output: %q", output)
+	}
+	runCommand(t, newTestCombatCommand(), dbPath, "damage", "2", "5")
+
+	expected := "   2  Gareth  Cleric 1  6/6 HP  XP 0\n" +
+		"   3  Mira  level 3  ? HP  XP 0  inactive\n" +
+		"   1  Theron (Alex)  Fighter 1  7/12 HP  AC 4  XP 0  torch\n"
+	if output := runCommand(t, newTestRosterCommand(), dbPath); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	if output := runCommand(t, newTestRosterCommand(), dbPath, "remove", "3"); output != "Removed Mira from the roster\n" {
+		t.Errorf("Unexpected remove output: %q", output)
+	}
+	if output := runCommand(t, newTestRosterCommand(), dbPath); strings.Contains(output, "Mira") {
+		t.Errorf("Expected Mira off the roster, got %q", output)
+	}
+}
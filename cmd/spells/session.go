@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Show the current session",
+	Long: `Show the current session: the latest one started. Commands that need a
+session start the first one themselves, so a new campaign can be played
+straight away; use "spells session new" at the start of each game night.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := model.GetLatestSession(eng.DB)
+			if err != nil {
+				return err
+			}
+			if session == nil {
+				cmd.Println("No sessions yet.")
+				return nil
+			}
+			cmd.Printf("Session %d (%s)\n", session.ID, session.TimeMode)
+			return printClock(cmd, eng, session.ID)
+		})
+	},
+}
+
+var sessionNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Start a new session, carrying on the in-world time",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := startSession(eng.DB)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Started session %d\n", session.ID)
+			return printClock(cmd, eng, session.ID)
+		})
+	},
+}
+
+// currentSessionID returns sessionID when it is set, otherwise the latest
+// session in the database. It returns nil when there are no sessions.
+func currentSessionID(database *sqlx.DB, sessionID int64) (*int64, error) {
+	if sessionID > 0 {
+		session, err := model.GetSession(database, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			return nil, fmt.Errorf("session %d not found", sessionID)
+		}
+		return &session.ID, nil
+	}
+
+	session, err := model.GetLatestSession(database)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, nil
+	}
+	return &session.ID, nil
+}
+
+// requireSession resolves the current session, starting the campaign's
+// first session when there is none yet.
+func requireSession(database *sqlx.DB, sessionID int64) (int64, error) {
+	current, err := currentSessionID(database, sessionID)
+	if err != nil {
+		return 0, err
+	}
+	if current != nil {
+		return *current, nil
+	}
+
+	session, err := startSession(database)
+	if err != nil {
+		return 0, err
+	}
+	return session.ID, nil
+}
+
+// startSession starts a new session in exploration mode. The in-world time
+// and the party's location carry on from the latest session, if there is
+// one.
+func startSession(database *sqlx.DB) (*model.Session, error) {
+	session := &model.Session{}
+	latest, err := model.GetLatestSession(database)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		session.CurrentTurn = latest.CurrentTurn
+		session.Location = latest.Location
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := session.Create(tx); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return session, nil
+}
+
+func init() {
+	addSessionFlags(sessionCmd)
+	sessionCmd.AddCommand(sessionNewCmd)
+}
+
+func addSessionFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestSessionCommand() *cobra.Command {
+	parent := &cobra.Command{Use: sessionCmd.Use, Args: sessionCmd.Args, RunE: sessionCmd.RunE}
+	addSessionFlags(parent)
+	parent.AddCommand(&cobra.Command{Use: sessionNewCmd.Use, Args: sessionNewCmd.Args, RunE: sessionNewCmd.RunE})
+	return parent
+}
+
+func TestSessionCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "campaign.db")
+
+	initCommand := &cobra.Command{Use: initCmd.Use, RunE: initCmd.RunE}
+	initCommand.Flags().String("path", "./campaign.db", "path to the database file")
+	runCommand(t, initCommand, dbPath)
+
+	if output := runCommand(t, newTestSessionCommand(), dbPath); output != "No sessions yet.\n" {
+		t.Errorf("Expected a fresh campaign to have no sessions, got %q", output)
+	}
+	if output := runCommand(t, newTestClockCommand(), dbPath, "advance", "1", "watch"); output != "Day 1, 12:00 (afternoon, watch 4)\n" {
+		t.Errorf("Expected advancing a fresh campaign to start its first session, got %q", output)
+	}
+	if output := runCommand(t, newTestSessionCommand(), dbPath, "new"); output != "Started session 2\nDay 1, 12:00 (afternoon, watch 4)\n" {
+		t.Errorf("Expected the new session to carry on the time, got %q", output)
+	}
+	if output := runCommand(t, newTestSessionCommand(), dbPath); output != "Session 2 (exploration)\nDay 1, 12:00 (afternoon, watch 4)\n" {
+		t.Errorf("Unexpected current session: %q", output)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var lightCmd = &cobra.Command{
+	Use:   "light [name|id] [torch|lantern|magic light]",
+	Short: "Light a character's torch, lantern or magic light",
+	Long: `Light a fresh light source for a character. Without a kind, the
+character's own light source is lit, or a torch. Lights burn down as time
+advances, for torch_duration_turns, lantern_duration_turns or
+magic_light_duration_turns, and warn once light_warning_turns are left.
+
+  spells light Theron
+  spells light Mira magic light
+  spells light Theron --out
+
+With no arguments, lists the party's lights.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		out, _ := cmd.Flags().GetBool("out")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			if len(args) == 0 {
+				party, err := model.ListCharacters(eng.DB, true)
+				if err != nil {
+					return err
+				}
+				lit := false
+				for _, character := range party {
+					if character.Lit() {
+						cmd.Printf("%s: %s\n", character.Name, describeLight(character))
+						lit = true
+					}
+				}
+				if !lit {
+					cmd.Println("No lights are burning.")
+				}
+				return nil
+			}
+
+			character, err := findCharacter(eng.DB, args[0])
+			if err != nil {
+				return err
+			}
+			if out {
+				if character, err = eng.PutOutLight(character.ID); err != nil {
+					return err
+				}
+				cmd.Printf("%s put out their %s\n", character.Name, *character.LightSource)
+				return nil
+			}
+			if character, err = eng.LightSource(character.ID, strings.Join(args[1:], " ")); err != nil {
+				return err
+			}
+			cmd.Printf("%s lit a %s: %s\n", character.Name, *character.LightSource, describeLight(*character))
+			return nil
+		})
+	},
+}
+
+var suppliesCmd = &cobra.Command{
+	Use:   "supplies [name|id]",
+	Short: "Track the consumables characters carry",
+	Long: `Track rations, oil flasks, arrows and other consumables. Daily supplies
+are used up one a day as time advances; the rest only when spent.
+
+  spells supplies add Theron 7 rations --daily
+  spells supplies add Theron 20 arrows
+  spells supplies use Theron arrows -n 3
+
+With no subcommand, lists a character's supplies, or everyone's.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			var characters []model.Character
+			if len(args) > 0 {
+				character, err := findCharacter(eng.DB, args[0])
+				if err != nil {
+					return err
+				}
+				characters = append(characters, *character)
+			} else {
+				var err error
+				if characters, err = model.ListCharacters(eng.DB, false); err != nil {
+					return err
+				}
+			}
+
+			listed := false
+			for _, character := range characters {
+				consumables, err := model.ListConsumables(eng.DB, character.ID)
+				if err != nil {
+					return err
+				}
+				if len(consumables) == 0 {
+					continue
+				}
+				cmd.Printf("%s:\n", character.Name)
+				for _, consumable := range consumables {
+					cmd.Printf("  %s\n", describeConsumable(consumable))
+				}
+				listed = true
+			}
+			if !listed {
+				cmd.Println("No supplies. Add them with spells supplies add.")
+			}
+			return nil
+		})
+	},
+}
+
+var suppliesAddCmd = &cobra.Command{
+	Use:   "add <name|id> <quantity> <item...>",
+	Short: "Give a character some supplies",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		daily, _ := cmd.Flags().GetBool("daily")
+		quantity, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid quantity %q: %w", args[1], err)
+		}
+		usage := ""
+		if daily {
+			usage = model.ConsumablePerDay
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			character, err := findCharacter(eng.DB, args[0])
+			if err != nil {
+				return err
+			}
+			consumable, err := eng.AddConsumable(character.ID, strings.Join(args[2:], " "), quantity, usage)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("%s: %s\n", character.Name, describeConsumable(*consumable))
+			return nil
+		})
+	},
+}
+
+var suppliesUseCmd = &cobra.Command{
+	Use:   "use <name|id> <item...>",
+	Short: "Spend some of a character's supplies",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		n, _ := cmd.Flags().GetInt("count")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			character, err := findCharacter(eng.DB, args[0])
+			if err != nil {
+				return err
+			}
+			consumable, err := eng.UseConsumable(character.ID, strings.Join(args[1:], " "), n)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("%s: %s\n", character.Name, describeConsumable(*consumable))
+			if consumable.Quantity == 0 {
+				cmd.Printf("%s is out of %s\n", character.Name, consumable.Name)
+			}
+			return nil
+		})
+	},
+}
+
+// describeLight shows a light's turns left, e.g. "torch 3/10", or that it
+// has burnt out.
+func describeLight(character model.Character) string {
+	if character.LightSource == nil {
+		return ""
+	}
+	switch {
+	case character.Lit() && character.LightDuration != nil:
+		return fmt.Sprintf("%s %d/%d", *character.LightSource, *character.LightRemaining, *character.LightDuration)
+	case character.LightRemaining != nil:
+		return *character.LightSource + " (out)"
+	}
+	return *character.LightSource
+}
+
+func describeConsumable(consumable model.Consumable) string {
+	desc := fmt.Sprintf("%s: %d", consumable.Name, consumable.Quantity)
+	if consumable.Usage == model.ConsumablePerDay {
+		desc += " (daily)"
+	}
+	return desc
+}
+
+func init() {
+	addLightFlags(lightCmd)
+	addSuppliesFlags(suppliesCmd)
+	suppliesCmd.AddCommand(suppliesAddCmd, suppliesUseCmd)
+	addSuppliesAddFlags(suppliesAddCmd)
+	addSuppliesUseFlags(suppliesUseCmd)
+}
+
+func addLightFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Bool("out", false, "put the light out")
+}
+
+func addSuppliesFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+}
+
+func addSuppliesAddFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("daily", false, "used up one a day, like rations")
+}
+
+func addSuppliesUseFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("count", "n", 1, "how many to use")
+}
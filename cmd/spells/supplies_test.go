@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestLightCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: lightCmd.Use, Args: lightCmd.Args, RunE: lightCmd.RunE}
+	addLightFlags(cmd)
+	return cmd
+}
+
+func newTestSuppliesCommand() *cobra.Command {
+	parent := &cobra.Command{Use: suppliesCmd.Use, Args: suppliesCmd.Args, RunE: suppliesCmd.RunE}
+	addSuppliesFlags(parent)
+
+	add := &cobra.Command{Use: suppliesAddCmd.Use, Args: suppliesAddCmd.Args, RunE: suppliesAddCmd.RunE}
+	addSuppliesAddFlags(add)
+	use := &cobra.Command{Use: suppliesUseCmd.Use, Args: suppliesUseCmd.Args, RunE: suppliesUseCmd.RunE}
+	addSuppliesUseFlags(use)
+	parent.AddCommand(add, use)
+	return parent
+}
+
+func TestLightAndSuppliesCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	runCommand(t, newTestRosterCommand(), dbPath, "add", "Theron", "--light", "torch")
+	runCommand(t, newTestRosterCommand(), dbPath, "add", "Mira")
+	if output := runCommand(t, newTestLightCommand(), dbPath); output != "No lights are burning.\n" {
+		t.Errorf("Unexpected output with no lights: %q", output)
+	}
+	if output := runCommand(t, newTestLightCommand(), dbPath, "Theron"); output != "Theron lit a torch: torch 10/10\n" {
+		t.Errorf("Unexpected light output: %q", output)
+	}
+	if output := runCommand(t, newTestLightCommand(), dbPath, "Mira", "magic", "light"); output != "Mira lit a magic light: magic light 12/12\n" {
+		t.Errorf("Unexpected magic light output: %q", output)
+	}
+
+	if output := runCommand(t, newTestSuppliesCommand(), dbPath); output != "No supplies. Add them with spells supplies add.\n" {
+		t.Errorf("Unexpected output with no supplies: %q", output)
+	}
+	if output := runCommand(t, newTestSuppliesCommand(), dbPath, "add", "Theron", "1", "iron", "rations", "--daily"); output != "Theron: iron rations: 1 (daily)\n" {
+		t.Errorf("Unexpected add output: %q", output)
+	}
+	runCommand(t, newTestSuppliesCommand(), dbPath, "add", "Mira", "20", "arrows")
+	if output := runCommand(t, newTestSuppliesCommand(), dbPath, "use", "Mira", "arrows", "-n", "3"); output != "Mira: arrows: 17\n" {
+		t.Errorf("Unexpected use output: %q", output)
+	}
+
+	expected := "Day 1, 09:50 (morning, watch 3)\n" +
+		"Theron's torch goes out\n" +
+		"Mira's magic light is about to go out (1 turn left)\n"
+	if output := runCommand(t, newTestClockCommand(), dbPath, "advance", "11"); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+	if output := runCommand(t, newTestClockCommand(), dbPath, "advance", "1", "day"); output != "Day 2, 09:50 (morning, watch 3)\n"+
+		"Mira's magic light goes out\nTheron is out of iron rations\n" {
+		t.Errorf("Unexpected output after a day: %q", output)
+	}
+
+	expected = "   2  Mira  level 1  ? HP  XP 0  magic light (out)\n" +
+		"   1  Theron  level 1  ? HP  XP 0  torch (out)\n"
+	if output := runCommand(t, newTestRosterCommand(), dbPath); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+	expected = "Mira:\n  arrows: 17\nTheron:\n  iron rations: 0 (daily)\n"
+	if output := runCommand(t, newTestSuppliesCommand(), dbPath); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// timerPollInterval is how often long-running commands look for expired
+// timers.
+const timerPollInterval = time.Second
+
+var timerCmd = &cobra.Command{
+	Use:   "timer [duration] [label...]",
+	Short: "Start and manage real-world timers",
+	Long: `Real-world timers for breaks, food orders and the like. Timers are
+stored in the campaign database, so they keep running between commands
+and every spells process on the campaign, including track, notices when
+they expire.
+
+  spells timer 10m pizza     start a 10 minute timer labelled pizza
+  spells timer               list running timers
+
+Durations are Go durations such as 90s, 10m or 1h30m; a bare number
+counts minutes.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return listTimers(cmd, false)
+		}
+		return startTimer(cmd, args)
+	},
+}
+
+var timerStartCmd = &cobra.Command{
+	Use:   "start <duration> [label...]",
+	Short: "Start a timer",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return startTimer(cmd, args)
+	},
+}
+
+var timerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List running timers",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		return listTimers(cmd, all)
+	},
+}
+
+var timerCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "Cancel a running timer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return finishTimer(cmd, args[0], model.TimerCancelled)
+	},
+}
+
+var timerCompleteCmd = &cobra.Command{
+	Use:   "complete <id>",
+	Short: "Mark a timer as done",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return finishTimer(cmd, args[0], model.TimerCompleted)
+	},
+}
+
+var timerWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Wait for timers to expire and announce them",
+	Long: `Keep running and print a line, with a terminal bell, whenever a timer
+expires. Timers that ran out while nothing was watching are announced
+straight away. Stop with Ctrl+C.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			eng.EventBus = engine.NewEventBus()
+			eng.EventBus.Subscribe("TimerExpired", func(event engine.Event) {
+				expired := event.(engine.TimerExpired)
+				cmd.Printf("\a%s Timer %d expired: %s\n", time.Now().Format("15:04:05"), expired.TimerID, expired.Label)
+			})
+
+			watcher := engine.NewTimerWatcher(eng)
+			watcher.Start(timerPollInterval)
+			defer watcher.Stop()
+
+			<-ctx.Done()
+			return nil
+		})
+	},
+}
+
+func startTimer(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("path")
+	sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+	duration, err := parseTimerDuration(args[0])
+	if err != nil {
+		return err
+	}
+	label := strings.Join(args[1:], " ")
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		session, err := currentSessionID(eng.DB, sessionID)
+		if err != nil {
+			return err
+		}
+
+		timer, err := eng.StartTimer(label, duration, session)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Started timer %d: %s for %v (until %s)\n", timer.ID, timer.Label, timer.Duration(),
+			timer.ExpiresAt().Local().Format("15:04:05"))
+		return nil
+	})
+}
+
+func listTimers(cmd *cobra.Command, all bool) error {
+	path, _ := cmd.Flags().GetString("path")
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		var timers []model.Timer
+		var err error
+		if all {
+			timers, err = model.ListTimers(eng.DB, 0)
+		} else {
+			timers, err = model.ListRunningTimers(eng.DB)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(timers) == 0 {
+			cmd.Println("No running timers.")
+			return nil
+		}
+		now := time.Now()
+		for _, timer := range timers {
+			cmd.Printf("%4d  %s: %s\n", timer.ID, timer.Label, timerStatus(timer, now))
+		}
+		return nil
+	})
+}
+
+func finishTimer(cmd *cobra.Command, arg, status string) error {
+	path, _ := cmd.Flags().GetString("path")
+
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timer ID %q", arg)
+	}
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		if status == model.TimerCancelled {
+			if err := eng.CancelTimer(id); err != nil {
+				return err
+			}
+			cmd.Printf("Cancelled timer %d\n", id)
+			return nil
+		}
+
+		if err := eng.CompleteTimer(id); err != nil {
+			return err
+		}
+		cmd.Printf("Completed timer %d\n", id)
+		return nil
+	})
+}
+
+// parseTimerDuration parses a Go duration such as 10m or 1h30m. A bare
+// number counts minutes.
+func parseTimerDuration(s string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(s); err == nil {
+		s = fmt.Sprintf("%dm", minutes)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timer duration %q, expected e.g. 10m or 1h30m", s)
+	}
+	return d, nil
+}
+
+// timerStatus describes how long a timer has left, or how it finished.
+func timerStatus(timer model.Timer, now time.Time) string {
+	switch {
+	case timer.Status != model.TimerRunning:
+		return timer.Status
+	case timer.Expired(now):
+		return fmt.Sprintf("EXPIRED %v ago", now.Sub(timer.ExpiresAt()).Round(time.Second))
+	default:
+		return fmt.Sprintf("%v left of %v", timer.Remaining(now).Round(time.Second), timer.Duration())
+	}
+}
+
+func init() {
+	addTimerFlags(timerCmd)
+	timerCmd.AddCommand(timerStartCmd, timerListCmd, timerCancelCmd, timerCompleteCmd, timerWatchCmd)
+	timerListCmd.Flags().Bool("all", false, "include completed and cancelled timers")
+}
+
+func addTimerFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+	cmd.PersistentFlags().Int64("session-id", 0, "session to attach new timers to (default latest)")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestTimerCommand() *cobra.Command {
+	parent := &cobra.Command{Use: timerCmd.Use, Args: timerCmd.Args, RunE: timerCmd.RunE}
+	addTimerFlags(parent)
+
+	list := &cobra.Command{Use: timerListCmd.Use, Args: timerListCmd.Args, RunE: timerListCmd.RunE}
+	list.Flags().Bool("all", false, "")
+	parent.AddCommand(
+		&cobra.Command{Use: timerStartCmd.Use, Args: timerStartCmd.Args, RunE: timerStartCmd.RunE},
+		list,
+		&cobra.Command{Use: timerCancelCmd.Use, Args: timerCancelCmd.Args, RunE: timerCancelCmd.RunE},
+		&cobra.Command{Use: timerCompleteCmd.Use, Args: timerCompleteCmd.Args, RunE: timerCompleteCmd.RunE},
+	)
+	return parent
+}
+
+func TestTimerCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "campaign.db")
+
+	run := func(args ...string) (string, error) {
+		cmd := newTestTimerCommand()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append(args, "--path", dbPath))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	output, err := run("10m", "pizza", "order")
+	if err != nil {
+		t.Fatalf("timer 10m failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "Started timer 1: pizza order for 10m0s") {
+		t.Errorf("Unexpected start output: %q", output)
+	}
+	if _, err := run("start", "5", "break"); err != nil {
+		t.Fatalf("timer start failed: %v", err)
+	}
+
+	output, err = run()
+	if err != nil {
+		t.Fatalf("timer failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "break: ") || !strings.Contains(lines[0], "left of 5m0s") ||
+		!strings.Contains(lines[1], "pizza order: ") {
+		t.Errorf("Expected break then pizza order, got %q", output)
+	}
+
+	if output, err := run("complete", "1"); err != nil || output != "Completed timer 1\n" {
+		t.Fatalf("timer complete: %q, %v", output, err)
+	}
+	if _, err := run("cancel", "1"); err == nil {
+		t.Error("Expected an error cancelling a completed timer")
+	}
+	if _, err := run("nonsense"); err == nil {
+		t.Error("Expected an error for an invalid duration")
+	}
+
+	output, err = run("list", "--all")
+	if err != nil {
+		t.Fatalf("timer list --all failed: %v", err)
+	}
+	if !strings.Contains(output, "pizza order: completed") {
+		t.Errorf("Expected the completed timer in the full list, got %q", output)
+	}
+}
+
+func TestParseTimerDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"10m":   10 * time.Minute,
+		"90s":   90 * time.Second,
+		"1h30m": 90 * time.Minute,
+		"15":    15 * time.Minute,
+	}
+	for input, expected := range tests {
+		got, err := parseTimerDuration(input)
+		if err != nil || got != expected {
+			t.Errorf("parseTimerDuration(%q) = %v, %v; expected %v", input, got, err, expected)
+		}
+	}
+	if _, err := parseTimerDuration("soon"); err == nil {
+		t.Error("Expected an error for an invalid duration")
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/script-wizards/spells/internal/db"
@@ -12,6 +13,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// holidayLookaheadDays is how far ahead track queues holiday events.
+const holidayLookaheadDays = 30
+
+// changePollInterval is how often track checks for writes made by other
+// processes.
+const changePollInterval = 250 * time.Millisecond
+
 var trackCmd = &cobra.Command{
 	Use:   "track",
 	Short: "Start the interactive spell tracking TUI",
@@ -44,9 +52,27 @@ var trackCmd = &cobra.Command{
 			log.Printf("Started watching for markdown files in: %s", watchPath)
 		}
 
+		config, err := loadCampaignConfig(path)
+		if err != nil {
+			return err
+		}
+
+		tables, err := loadOracleTables(path)
+		if err != nil {
+			return err
+		}
+
 		// Create engine
 		eng := &engine.Engine{
-			DB: database,
+			DB:       database,
+			EventBus: engine.NewEventBus(),
+			Config:   &config,
+			Tables:   tables,
+		}
+
+		// Queue the coming month's holidays so they trigger during play
+		if _, err := eng.ScheduleHolidays(sessionID, holidayLookaheadDays); err != nil {
+			log.Printf("Failed to schedule holidays: %v", err)
 		}
 
 		// Create TUI model
@@ -57,6 +83,19 @@ var trackCmd = &cobra.Command{
 
 		program := tea.NewProgram(model)
 
+		// Watch for timers expiring, including those started elsewhere
+		timerWatcher := engine.NewTimerWatcher(eng)
+		timerWatcher.Start(timerPollInterval)
+		defer timerWatcher.Stop()
+
+		// Pick up changes made by other spells processes
+		changeWatcher, err := engine.NewChangeWatcher(eng)
+		if err != nil {
+			return err
+		}
+		changeWatcher.Start(changePollInterval)
+		defer changeWatcher.Stop()
+
 		if _, err := program.Run(); err != nil {
 			return fmt.Errorf("failed to start TUI: %w", err)
 		}
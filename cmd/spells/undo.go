@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last action in a session",
+	Long: `Revert the latest action still in effect in the session: a turn
+advance, scheduled event, wandering check, HP change, combatant or NPC
+edit. Undo repeatedly to step further back, and redo to reapply.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return revertAction(cmd, model.JournalUndo)
+	},
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone action in a session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return revertAction(cmd, model.JournalRedo)
+	},
+}
+
+var journalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "List recent actions in a session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := currentSessionID(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+
+			entries, err := model.ListJournal(eng.DB, session, limit)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				cmd.Println("No actions recorded.")
+				return nil
+			}
+			for _, entry := range entries {
+				cmd.Printf("%4d  %s  %s\n", entry.ID, entry.CreatedAt.Local().Format("15:04:05"), entry.Description)
+			}
+			return nil
+		})
+	},
+}
+
+func revertAction(cmd *cobra.Command, action string) error {
+	path, _ := cmd.Flags().GetString("path")
+	sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+	return withCampaignEngine(path, func(eng *engine.Engine) error {
+		session, err := requireSession(eng.DB, sessionID)
+		if err != nil {
+			return err
+		}
+
+		if action == model.JournalUndo {
+			entry, err := eng.Undo(session)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Undid: %s\n", entry.Description)
+			return nil
+		}
+
+		entry, err := eng.Redo(session)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Redid: %s\n", entry.Description)
+		return nil
+	})
+}
+
+func init() {
+	addUndoFlags(undoCmd)
+	addUndoFlags(redoCmd)
+	addUndoFlags(journalCmd)
+	journalCmd.Flags().Int("limit", 20, "number of actions to list")
+}
+
+func addUndoFlags(cmd *cobra.Command) {
+	cmd.Flags().String("path", "./campaign.db", "path to the database file")
+	cmd.Flags().Int64("session-id", 0, "session to use (default latest)")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+func TestUndoCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := (&engine.Engine{DB: database}).Advance(1, 2); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	database.Close()
+
+	run := func(source *cobra.Command, args ...string) (string, error) {
+		cmd := &cobra.Command{Use: source.Use, Args: source.Args, RunE: source.RunE}
+		addUndoFlags(cmd)
+		cmd.Flags().Int("limit", 20, "")
+		return executeCommand(cmd, dbPath, args...)
+	}
+
+	if output, err := run(redoCmd); err == nil {
+		t.Errorf("Expected nothing to redo, got %q", output)
+	}
+	output, err := run(undoCmd)
+	if err != nil || output != "Undid: Advanced 2 turns to turn 2\n" {
+		t.Fatalf("undo: %q, %v", output, err)
+	}
+	output, err = run(redoCmd)
+	if err != nil || output != "Redid: Advanced 2 turns to turn 2\n" {
+		t.Fatalf("redo: %q, %v", output, err)
+	}
+
+	output, err = run(journalCmd)
+	if err != nil {
+		t.Fatalf("journal failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "Redo: Advanced 2 turns to turn 2") ||
+		!strings.HasSuffix(lines[2], "Advanced 2 turns to turn 2") {
+		t.Errorf("Unexpected journal %q", output)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var wanderCmd = &cobra.Command{
+	Use:   "wander",
+	Short: "Manage wandering monster checks",
+	Long: `Wandering monster checks roll every few turns as time advances. A roll
+of the chance or less on the die (1-in-6 by default) resolves the check's
+oracle table for the encounter, and can start an encounter with the
+rolled monsters. A check for a location only rolls while the party is
+there; "spells wander location" sets where that is.`,
+}
+
+var wanderAddCmd = &cobra.Command{
+	Use:   "add <table>",
+	Short: "Add a wandering monster check to the current session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		every, _ := cmd.Flags().GetInt64("every")
+		chance, _ := cmd.Flags().GetInt("chance")
+		die, _ := cmd.Flags().GetInt("die")
+		location, _ := cmd.Flags().GetString("location")
+		startEncounter, _ := cmd.Flags().GetBool("encounter")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			if _, exists := eng.Tables[args[0]]; !exists {
+				return fmt.Errorf("table %q not found", args[0])
+			}
+
+			check := &model.WanderingCheck{
+				SessionID:       session,
+				FrequencyTurns:  every,
+				Chance:          chance,
+				Die:             die,
+				TableName:       args[0],
+				CreateEncounter: startEncounter,
+			}
+			if location != "" {
+				check.Location = &location
+			}
+			if err := eng.AddWanderingCheck(check); err != nil {
+				return err
+			}
+
+			cmd.Printf("Added wandering check %d: %s\n", check.ID, describeWanderingCheck(*check))
+			return nil
+		})
+	},
+}
+
+var wanderListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the current session's wandering monster checks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+
+			checks, err := model.ListWanderingChecks(eng.DB, session)
+			if err != nil {
+				return err
+			}
+			if len(checks) == 0 {
+				cmd.Println("No wandering checks.")
+				return nil
+			}
+			for _, check := range checks {
+				next := ""
+				if check.TimeEventID != nil {
+					if event, err := model.GetTimeEvent(eng.DB, *check.TimeEventID); err == nil && event != nil {
+						next = fmt.Sprintf(", next @turn %d", event.TriggerTurn)
+					}
+				}
+				cmd.Printf("%4d  %s%s\n", check.ID, describeWanderingCheck(check), next)
+			}
+			return nil
+		})
+	},
+}
+
+var wanderRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a wandering monster check",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid check ID %q", args[0])
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			if err := eng.RemoveWanderingCheck(id); err != nil {
+				return err
+			}
+			cmd.Printf("Removed wandering check %d\n", id)
+			return nil
+		})
+	},
+}
+
+var wanderRollCmd = &cobra.Command{
+	Use:   "roll <id>",
+	Short: "Roll a wandering monster check now",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid check ID %q", args[0])
+		}
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			check, err := model.GetWanderingCheck(eng.DB, id)
+			if err != nil {
+				return err
+			}
+			if check == nil {
+				return fmt.Errorf("wandering check %d not found", id)
+			}
+
+			rolled, err := eng.RollWanderingCheck(check)
+			if err != nil {
+				return err
+			}
+			if !rolled.Encountered {
+				cmd.Printf("%d on d%d: no encounter\n", rolled.Roll, rolled.Die)
+				return nil
+			}
+			cmd.Printf("%d on d%d: %s\n", rolled.Roll, rolled.Die, rolled.Result)
+			if rolled.EncounterID != nil {
+				cmd.Printf("Started encounter %d\n", *rolled.EncounterID)
+			}
+			return nil
+		})
+	},
+}
+
+var wanderLocationCmd = &cobra.Command{
+	Use:   "location [name]",
+	Short: "Show or set where the party is",
+	Long: `Show or set where the party is. Checks for a location only roll while
+the party is there; checks without one roll wherever it goes.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		leave, _ := cmd.Flags().GetBool("clear")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			if leave || len(args) > 0 {
+				location := ""
+				if len(args) > 0 && !leave {
+					location = args[0]
+				}
+				if err := eng.SetLocation(session, location); err != nil {
+					return err
+				}
+			}
+
+			current, err := model.GetSession(eng.DB, session)
+			if err != nil {
+				return err
+			}
+			if current.Location == nil {
+				cmd.Println("The party is nowhere in particular.")
+				return nil
+			}
+			cmd.Printf("The party is at %s.\n", *current.Location)
+			return nil
+		})
+	},
+}
+
+func describeWanderingCheck(check model.WanderingCheck) string {
+	where := "session"
+	if check.Location != nil {
+		where = *check.Location
+	}
+	desc := fmt.Sprintf("%s: %d-in-%d every %d turns on [%s]", where, check.Chance, check.Die,
+		check.FrequencyTurns, check.TableName)
+	if check.CreateEncounter {
+		desc += ", starts encounters"
+	}
+	return desc
+}
+
+func init() {
+	addWanderFlags(wanderCmd)
+	wanderCmd.AddCommand(wanderAddCmd, wanderListCmd, wanderRemoveCmd, wanderRollCmd, wanderLocationCmd)
+	addWanderAddFlags(wanderAddCmd)
+	addWanderLocationFlags(wanderLocationCmd)
+}
+
+func addWanderFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+	cmd.PersistentFlags().Int64("session-id", 0, "session to use (default latest)")
+}
+
+func addWanderAddFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64("every", 2, "turns between checks")
+	cmd.Flags().Int("chance", 1, "encounter on this roll or lower")
+	cmd.Flags().Int("die", 6, "sides of the die rolled for the check")
+	cmd.Flags().String("location", "", "only roll the check while the party is at this location")
+	cmd.Flags().Bool("encounter", false, "start an encounter with the rolled monsters")
+}
+
+func addWanderLocationFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("clear", false, "leave the party nowhere in particular")
+}
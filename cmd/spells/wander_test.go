@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestWanderCommand() *cobra.Command {
+	parent := &cobra.Command{Use: wanderCmd.Use}
+	addWanderFlags(parent)
+
+	add := &cobra.Command{Use: wanderAddCmd.Use, Args: wanderAddCmd.Args, RunE: wanderAddCmd.RunE}
+	addWanderAddFlags(add)
+	list := &cobra.Command{Use: wanderListCmd.Use, Args: wanderListCmd.Args, RunE: wanderListCmd.RunE}
+	remove := &cobra.Command{Use: wanderRemoveCmd.Use, Args: wanderRemoveCmd.Args, RunE: wanderRemoveCmd.RunE}
+	roll := &cobra.Command{Use: wanderRollCmd.Use, Args: wanderRollCmd.Args, RunE: wanderRollCmd.RunE}
+	location := &cobra.Command{Use: wanderLocationCmd.Use, Args: wanderLocationCmd.Args, RunE: wanderLocationCmd.RunE}
+	addWanderLocationFlags(location)
+
+	parent.AddCommand(add, list, remove, roll, location)
+	return parent
+}
+
+func TestWanderCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "")
+	if err := os.WriteFile(filepath.Join(filepath.Dir(dbPath), "crypt.table"), []byte("2 skeletons"), 0644); err != nil {
+		t.Fatalf("Failed to write table: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		return executeCommand(newTestWanderCommand(), dbPath, args...)
+	}
+
+	output, err := run("add", "crypt", "--every", "3", "--chance", "6", "--location", "Crypt", "--encounter")
+	if err != nil {
+		t.Fatalf("wander add failed: %v", err)
+	}
+	if !strings.Contains(output, "Crypt: 6-in-6 every 3 turns on [crypt], starts encounters") {
+		t.Errorf("Unexpected add output: %q", output)
+	}
+
+	if _, err := run("add", "missing"); err == nil {
+		t.Error("Expected an error for a missing table")
+	}
+
+	output, err = run("list")
+	if err != nil {
+		t.Fatalf("wander list failed: %v", err)
+	}
+	if !strings.Contains(output, "next @turn 3") {
+		t.Errorf("Expected the next check turn, got %q", output)
+	}
+
+	output, err = run("roll", "1")
+	if err != nil {
+		t.Fatalf("wander roll failed: %v", err)
+	}
+	if !strings.Contains(output, "2 skeletons") || !strings.Contains(output, "Started encounter") {
+		t.Errorf("Unexpected roll output: %q", output)
+	}
+
+	if output := runCommand(t, newTestWanderCommand(), dbPath, "location"); output != "The party is nowhere in particular.\n" {
+		t.Errorf("Expected no location yet, got %q", output)
+	}
+	if output := runCommand(t, newTestWanderCommand(), dbPath, "location", "Crypt"); output != "The party is at Crypt.\n" {
+		t.Errorf("Unexpected location output: %q", output)
+	}
+	if output := runCommand(t, newTestWanderCommand(), dbPath, "location", "--clear"); output != "The party is nowhere in particular.\n" {
+		t.Errorf("Expected the location to be cleared, got %q", output)
+	}
+
+	if _, err := run("remove", "1"); err != nil {
+		t.Fatalf("wander remove failed: %v", err)
+	}
+	output, err = run("list")
+	if err != nil {
+		t.Fatalf("wander list failed: %v", err)
+	}
+	if !strings.Contains(output, "No wandering checks.") {
+		t.Errorf("Expected no checks after removal, got %q", output)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var xpCmd = &cobra.Command{
+	Use:   "xp",
+	Short: "Show the XP the party has earned",
+	Long: `Show the current session's XP ledger, the session and campaign totals and
+each PC's share. Defeated monsters are credited with the XP on their stat
+block as they fall; treasure counts once it is awarded with "spells xp
+award", at xp_conversion_rate XP per gold piece. Every award is
+multiplied by xp_multiplier, and shares are split between party_size PCs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			summary, err := eng.XP(session)
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range summary.Ledger {
+				cmd.Printf("  %s (%s): %d XP\n", entry.Description, entry.Source, entry.XP)
+			}
+			printXP(cmd, summary)
+			if summary.Pending > 0 {
+				cmd.Printf("Unawarded treasure: %d XP\n", summary.Pending)
+			}
+			cmd.Printf("Campaign: %d XP\n", summary.Campaign)
+			return nil
+		})
+	},
+}
+
+var xpAwardCmd = &cobra.Command{
+	Use:   "award",
+	Short: "Award the XP for the session's treasure",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+			awarded, err := eng.AwardXP(session)
+			if err != nil {
+				return err
+			}
+			if len(awarded.Treasure) == 0 {
+				cmd.Println("No treasure waiting to be awarded.")
+			} else {
+				cmd.Printf("Awarded %d XP for %s\n", awarded.XP, plural(int64(len(awarded.Treasure)), "treasure"))
+			}
+
+			summary, err := eng.XP(session)
+			if err != nil {
+				return err
+			}
+			printXP(cmd, summary)
+			return nil
+		})
+	},
+}
+
+var treasureCmd = &cobra.Command{
+	Use:   "treasure [description...]",
+	Short: "Record treasure the party found, or list it",
+	Long: `Record treasure found in the current session, with its value in gold
+pieces and where it was found:
+
+  spells treasure Gold chalice --gp 50 --location "Room 12"
+
+With no description, lists the session's treasure.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("path")
+		sessionID, _ := cmd.Flags().GetInt64("session-id")
+		gold, _ := cmd.Flags().GetInt("gp")
+		location, _ := cmd.Flags().GetString("location")
+
+		return withCampaignEngine(path, func(eng *engine.Engine) error {
+			session, err := requireSession(eng.DB, sessionID)
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				treasure, err := eng.RecordTreasure(session, strings.Join(args, " "), gold, location)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("Recorded treasure %d: %s\n", treasure.ID, describeTreasure(*treasure))
+				return nil
+			}
+
+			treasure, err := model.ListTreasure(eng.DB, session)
+			if err != nil {
+				return err
+			}
+			if len(treasure) == 0 {
+				cmd.Println("No treasure found yet.")
+				return nil
+			}
+			for _, found := range treasure {
+				line := describeTreasure(found)
+				if found.XPAwarded {
+					line += "  awarded"
+				}
+				cmd.Printf("%4d  %s\n", found.ID, line)
+			}
+			return nil
+		})
+	},
+}
+
+// printXP prints the session's XP and each PC's share of it.
+func printXP(cmd *cobra.Command, summary *engine.XPSummary) {
+	cmd.Printf("Session: %d XP, %d each for a party of %d\n", summary.Session, summary.Share(), max(summary.PartySize, 1))
+}
+
+func describeTreasure(treasure model.Treasure) string {
+	desc := fmt.Sprintf("%s, %d gp", treasure.Description, treasure.GoldValue)
+	if treasure.Location != nil {
+		desc += " (" + *treasure.Location + ")"
+	}
+	return desc
+}
+
+func init() {
+	addXPFlags(xpCmd)
+	xpCmd.AddCommand(xpAwardCmd)
+	addXPFlags(treasureCmd)
+	addTreasureFlags(treasureCmd)
+}
+
+func addXPFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("path", "./campaign.db", "path to the database file")
+	cmd.PersistentFlags().Int64("session-id", 0, "session to use (default latest)")
+}
+
+func addTreasureFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("gp", 0, "value in gold pieces")
+	cmd.Flags().String("location", "", "where the treasure was found")
+}
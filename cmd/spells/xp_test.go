@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/spf13/cobra"
+)
+
+func newTestXPCommand() *cobra.Command {
+	parent := &cobra.Command{Use: xpCmd.Use, Args: xpCmd.Args, RunE: xpCmd.RunE}
+	addXPFlags(parent)
+	parent.AddCommand(&cobra.Command{Use: xpAwardCmd.Use, Args: xpAwardCmd.Args, RunE: xpAwardCmd.RunE})
+	return parent
+}
+
+func newTestTreasureCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: treasureCmd.Use, Args: treasureCmd.Args, RunE: treasureCmd.RunE}
+	addXPFlags(cmd)
+	addTreasureFlags(cmd)
+	return cmd
+}
+
+func TestXPCommands(t *testing.T) {
+	dbPath := newTestCampaign(t, "party_size: 2\nxp_multiplier: 2\n")
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	goblin := &model.Monster{Name: "Goblin", ArmorClass: 6, HitDice: "1", Movement: 60, Morale: 12, XP: 5}
+	if err := model.CreateMonster(tx, goblin); err != nil {
+		t.Fatalf("Failed to create monster: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	database.Close()
+
+	if output := runCommand(t, newTestTreasureCommand(), dbPath); output != "No treasure found yet.\n" {
+		t.Errorf("Unexpected empty treasure output: %q", output)
+	}
+	if output := runCommand(t, newTestTreasureCommand(), dbPath, "Gold", "chalice", "--gp", "50", "--location", "Room 12"); output != "Recorded treasure 1: Gold chalice, 50 gp (Room 12)\n" {
+		t.Errorf("Unexpected treasure output: %q", output)
+	}
+
+	runCommand(t, newTestCombatCommand(), dbPath, "start")
+	runCommand(t, newTestCombatCommand(), dbPath, "add", "Goblin")
+	if output := runCommand(t, newTestCombatCommand(), dbPath, "damage", "1", "20"); !strings.HasSuffix(output, " HP  defeated\nEarned 10 XP for Goblin\n") {
+		t.Errorf("Unexpected damage output: %q", output)
+	}
+
+	expected := "  Goblin (monster): 10 XP\n" +
+		"Session: 10 XP, 5 each for a party of 2\n" +
+		"Unawarded treasure: 100 XP\n" +
+		"Campaign: 10 XP\n"
+	if output := runCommand(t, newTestXPCommand(), dbPath); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	expected = "Awarded 100 XP for 1 treasure\nSession: 110 XP, 55 each for a party of 2\n"
+	if output := runCommand(t, newTestXPCommand(), dbPath, "award"); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+	if output := runCommand(t, newTestXPCommand(), dbPath, "award"); output != "No treasure waiting to be awarded.\nSession: 110 XP, 55 each for a party of 2\n" {
+		t.Errorf("Unexpected second award output: %q", output)
+	}
+	if output := runCommand(t, newTestTreasureCommand(), dbPath); output != "   1  Gold chalice, 50 gp (Room 12)  awarded\n" {
+		t.Errorf("Unexpected treasure listing: %q", output)
+	}
+}
@@ -0,0 +1,217 @@
+package clock
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/config"
+)
+
+var moonPhases = []string{
+	"new",
+	"waxing crescent",
+	"first quarter",
+	"waxing gibbous",
+	"full",
+	"waning gibbous",
+	"last quarter",
+	"waning crescent",
+}
+
+// Calendar maps day numbers of play to dates on a fantasy calendar. Day 1
+// is the configured start date.
+type Calendar struct {
+	cfg        config.CalendarConfig
+	yearLength int
+	// startOffset is the day of the year, from 0, of the first day of play.
+	startOffset int
+}
+
+// Date is a calendar date. Month and Day count from 1.
+type Date struct {
+	Year      int
+	Month     int
+	MonthName string
+	Day       int
+	Weekday   string
+}
+
+func (d Date) String() string {
+	date := fmt.Sprintf("%d %s %d", d.Day, d.MonthName, d.Year)
+	if d.Weekday != "" {
+		return d.Weekday + ", " + date
+	}
+	return date
+}
+
+// MoonPhase is the phase of one moon on a day. Day counts from 0, the new
+// moon, up to Cycle-1.
+type MoonPhase struct {
+	Moon  string
+	Phase string
+	Day   int
+	Cycle int
+}
+
+// HolidayDate is one occurrence of a holiday. InDays is 0 for today.
+type HolidayDate struct {
+	Holiday config.HolidayConfig
+	Date    Date
+	GameDay int64
+	InDays  int64
+}
+
+// NewCalendar validates a calendar definition. It returns nil and no error
+// when the config defines no months.
+func NewCalendar(cfg config.CalendarConfig) (*Calendar, error) {
+	if len(cfg.Months) == 0 {
+		return nil, nil
+	}
+
+	c := &Calendar{cfg: cfg}
+	for _, month := range cfg.Months {
+		if month.Days < 1 {
+			return nil, fmt.Errorf("month %q must have at least one day", month.Name)
+		}
+		c.yearLength += month.Days
+	}
+	for _, moon := range cfg.Moons {
+		if moon.Cycle < 1 {
+			return nil, fmt.Errorf("moon %q must have a cycle of at least one day", moon.Name)
+		}
+	}
+	for _, holiday := range cfg.Holidays {
+		month, err := c.monthIndex(holiday.Month)
+		if err != nil {
+			return nil, fmt.Errorf("holiday %q: %w", holiday.Name, err)
+		}
+		if holiday.Day < 1 || holiday.Day > cfg.Months[month].Days {
+			return nil, fmt.Errorf("holiday %q: %s has no day %d", holiday.Name, cfg.Months[month].Name, holiday.Day)
+		}
+	}
+
+	startMonth, startDay := max(cfg.Start.Month, 1), max(cfg.Start.Day, 1)
+	if startMonth > len(cfg.Months) || startDay > cfg.Months[startMonth-1].Days {
+		return nil, fmt.Errorf("start date %d/%d is not on the calendar", startMonth, startDay)
+	}
+	c.startOffset = c.dayOfYear(startMonth-1, startDay)
+
+	return c, nil
+}
+
+// Date returns the calendar date of a day of play.
+func (c *Calendar) Date(gameDay int64) Date {
+	days := int64(c.startOffset) + gameDay - 1
+	year := floorDiv(days, int64(c.yearLength))
+	dayOfYear := int(days - year*int64(c.yearLength))
+
+	date := Date{Year: c.cfg.Start.Year + int(year)}
+	for i, month := range c.cfg.Months {
+		if dayOfYear < month.Days {
+			date.Month = i + 1
+			date.MonthName = month.Name
+			date.Day = dayOfYear + 1
+			break
+		}
+		dayOfYear -= month.Days
+	}
+
+	if len(c.cfg.Weekdays) > 0 {
+		weekday := floorDiv(gameDay-1, int64(len(c.cfg.Weekdays)))
+		date.Weekday = c.cfg.Weekdays[gameDay-1-weekday*int64(len(c.cfg.Weekdays))]
+	}
+	return date
+}
+
+// GameDay returns the day of play that falls on a calendar date.
+func (c *Calendar) GameDay(year, month, day int) int64 {
+	days := int64(year-c.cfg.Start.Year)*int64(c.yearLength) + int64(c.dayOfYear(month-1, day))
+	return days - int64(c.startOffset) + 1
+}
+
+// Moons returns the phase of every moon on a day of play.
+func (c *Calendar) Moons(gameDay int64) []MoonPhase {
+	phases := make([]MoonPhase, 0, len(c.cfg.Moons))
+	for _, moon := range c.cfg.Moons {
+		cycle := int64(moon.Cycle)
+		day := (gameDay - 1 + int64(moon.Offset)) % cycle
+		if day < 0 {
+			day += cycle
+		}
+
+		phase := int(math.Floor(float64(day)/float64(cycle)*8+0.5)) % len(moonPhases)
+		phases = append(phases, MoonPhase{
+			Moon:  moon.Name,
+			Phase: moonPhases[phase],
+			Day:   int(day),
+			Cycle: moon.Cycle,
+		})
+	}
+	return phases
+}
+
+// Holidays returns the holidays from a day of play up to and including
+// the given number of days later, in date order.
+func (c *Calendar) Holidays(gameDay int64, days int64) []HolidayDate {
+	var holidays []HolidayDate
+
+	first := c.Date(gameDay)
+	last := c.Date(gameDay + days)
+	for year := first.Year; year <= last.Year; year++ {
+		for _, holiday := range c.cfg.Holidays {
+			month, _ := c.monthIndex(holiday.Month)
+			day := c.GameDay(year, month+1, holiday.Day)
+			if day < gameDay || day > gameDay+days {
+				continue
+			}
+			holidays = append(holidays, HolidayDate{
+				Holiday: holiday,
+				Date:    c.Date(day),
+				GameDay: day,
+				InDays:  day - gameDay,
+			})
+		}
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].GameDay < holidays[j].GameDay
+	})
+	return holidays
+}
+
+// YearLength returns the number of days in a year.
+func (c *Calendar) YearLength() int {
+	return c.yearLength
+}
+
+func (c *Calendar) dayOfYear(month, day int) int {
+	offset := day - 1
+	for _, m := range c.cfg.Months[:month] {
+		offset += m.Days
+	}
+	return offset
+}
+
+// monthIndex finds a month by name, ignoring case, or by number from 1.
+func (c *Calendar) monthIndex(month string) (int, error) {
+	for i, m := range c.cfg.Months {
+		if strings.EqualFold(m.Name, month) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(month); err == nil && n >= 1 && n <= len(c.cfg.Months) {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("unknown month %q", month)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
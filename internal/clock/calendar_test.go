@@ -0,0 +1,153 @@
+package clock
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/config"
+)
+
+func testCalendar(t *testing.T) *Calendar {
+	t.Helper()
+
+	calendar, err := NewCalendar(config.CalendarConfig{
+		Months: []config.MonthConfig{
+			{Name: "Frostfall", Days: 30},
+			{Name: "Thaw", Days: 28},
+			{Name: "Bloom", Days: 30},
+		},
+		Weekdays: []string{"Moonday", "Fireday", "Starday"},
+		Moons: []config.MoonConfig{
+			{Name: "Selune", Cycle: 8},
+			{Name: "Grey", Cycle: 20, Offset: 10},
+		},
+		Holidays: []config.HolidayConfig{
+			{Name: "Greengrass", Month: "bloom", Day: 1},
+			{Name: "Midwinter", Month: "1", Day: 15, Description: "feast"},
+		},
+		Start: config.DateConfig{Year: 1023, Month: 1, Day: 10},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build calendar: %v", err)
+	}
+	return calendar
+}
+
+func TestCalendarDate(t *testing.T) {
+	calendar := testCalendar(t)
+
+	tests := []struct {
+		day      int64
+		expected string
+	}{
+		{1, "Moonday, 10 Frostfall 1023"},
+		{2, "Fireday, 11 Frostfall 1023"},
+		{4, "Moonday, 13 Frostfall 1023"},
+		{21, "Starday, 30 Frostfall 1023"},
+		{22, "Moonday, 1 Thaw 1023"},
+		{80, "Fireday, 1 Frostfall 1024"},
+		{0, "Starday, 9 Frostfall 1023"},
+		{-9, "Starday, 30 Bloom 1022"},
+	}
+
+	for _, tt := range tests {
+		date := calendar.Date(tt.day)
+		if got := date.String(); got != tt.expected {
+			t.Errorf("Date(%d) = %q, expected %q", tt.day, got, tt.expected)
+		}
+		if back := calendar.GameDay(date.Year, date.Month, date.Day); back != tt.day {
+			t.Errorf("GameDay(%+v) = %d, expected %d", date, back, tt.day)
+		}
+	}
+}
+
+func TestCalendarMoons(t *testing.T) {
+	calendar := testCalendar(t)
+
+	moons := calendar.Moons(1)
+	if len(moons) != 2 {
+		t.Fatalf("Expected 2 moons, got %d", len(moons))
+	}
+	if moons[0].Phase != "new" || moons[1].Phase != "full" {
+		t.Errorf("Expected new and full moons on day 1, got %+v", moons)
+	}
+
+	moons = calendar.Moons(3)
+	if moons[0].Phase != "first quarter" || moons[0].Day != 2 {
+		t.Errorf("Expected a first quarter moon on day 3, got %+v", moons[0])
+	}
+
+	moons = calendar.Moons(9)
+	if moons[0].Phase != "new" {
+		t.Errorf("Expected the cycle to repeat on day 9, got %+v", moons[0])
+	}
+}
+
+func TestCalendarHolidays(t *testing.T) {
+	calendar := testCalendar(t)
+
+	holidays := calendar.Holidays(1, 100)
+	if len(holidays) != 3 {
+		t.Fatalf("Expected 3 holidays, got %+v", holidays)
+	}
+
+	expected := []struct {
+		name   string
+		inDays int64
+		year   int
+	}{
+		{"Midwinter", 5, 1023},
+		{"Greengrass", 49, 1023},
+		{"Midwinter", 93, 1024},
+	}
+	for i, want := range expected {
+		got := holidays[i]
+		if got.Holiday.Name != want.name || got.InDays != want.inDays || got.Date.Year != want.year {
+			t.Errorf("Holiday %d = %s in %d days (%d), expected %s in %d days (%d)",
+				i, got.Holiday.Name, got.InDays, got.Date.Year, want.name, want.inDays, want.year)
+		}
+	}
+
+	today := calendar.Holidays(6, 0)
+	if len(today) != 1 || today[0].Holiday.Name != "Midwinter" || today[0].InDays != 0 {
+		t.Errorf("Expected Midwinter today, got %+v", today)
+	}
+}
+
+func TestNewCalendarErrors(t *testing.T) {
+	calendar, err := NewCalendar(config.CalendarConfig{})
+	if calendar != nil || err != nil {
+		t.Errorf("Expected no calendar without months, got %v, %v", calendar, err)
+	}
+
+	months := []config.MonthConfig{{Name: "Only", Days: 10}}
+	invalid := []config.CalendarConfig{
+		{Months: []config.MonthConfig{{Name: "Empty"}}},
+		{Months: months, Moons: []config.MoonConfig{{Name: "Still"}}},
+		{Months: months, Holidays: []config.HolidayConfig{{Name: "Lost", Month: "Nowhere", Day: 1}}},
+		{Months: months, Holidays: []config.HolidayConfig{{Name: "Late", Month: "Only", Day: 11}}},
+		{Months: months, Start: config.DateConfig{Month: 2, Day: 1}},
+	}
+	for _, cfg := range invalid {
+		if _, err := NewCalendar(cfg); err == nil {
+			t.Errorf("Expected an error for %+v", cfg)
+		}
+	}
+}
+
+func TestClockDayStart(t *testing.T) {
+	c := New(config.DefaultConfig().Clock)
+
+	tests := []struct {
+		day      int64
+		expected int64
+	}{
+		{1, -48},
+		{2, 96},
+		{3, 240},
+	}
+	for _, tt := range tests {
+		if got := c.DayStart(tt.day); got != tt.expected {
+			t.Errorf("DayStart(%d) = %d, expected %d", tt.day, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package clock maps session turns and rounds to in-world time.
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/script-wizards/spells/internal/config"
+)
+
+const (
+	// ModeExploration advances time a turn at a time.
+	ModeExploration = "exploration"
+	// ModeCombat advances time a round at a time.
+	ModeCombat = "combat"
+)
+
+// Clock converts between turns, rounds and in-world time. Turn 0, round 0
+// falls on day 1 at the configured start hour.
+type Clock struct {
+	Turn  time.Duration
+	Round time.Duration
+	Watch time.Duration
+	Day   time.Duration
+	Start time.Duration
+}
+
+// New returns a clock using the configured unit lengths. Units that are not
+// set fall back to the defaults.
+func New(cfg config.ClockConfig) Clock {
+	defaults := config.DefaultConfig().Clock
+	if cfg.TurnMinutes <= 0 {
+		cfg.TurnMinutes = defaults.TurnMinutes
+	}
+	if cfg.RoundSeconds <= 0 {
+		cfg.RoundSeconds = defaults.RoundSeconds
+	}
+	if cfg.WatchHours <= 0 {
+		cfg.WatchHours = defaults.WatchHours
+	}
+	if cfg.DayHours <= 0 {
+		cfg.DayHours = defaults.DayHours
+	}
+
+	return Clock{
+		Turn:  time.Duration(cfg.TurnMinutes) * time.Minute,
+		Round: time.Duration(cfg.RoundSeconds) * time.Second,
+		Watch: time.Duration(cfg.WatchHours) * time.Hour,
+		Day:   time.Duration(cfg.DayHours) * time.Hour,
+		Start: time.Duration(cfg.StartHour) * time.Hour,
+	}
+}
+
+// RoundsPerTurn returns how many whole rounds make up a turn.
+func (c Clock) RoundsPerTurn() int64 {
+	return max(int64(c.Turn/c.Round), 1)
+}
+
+// Elapsed returns the in-world time since turn 0.
+func (c Clock) Elapsed(turn, round int64) time.Duration {
+	return time.Duration(turn)*c.Turn + time.Duration(round)*c.Round
+}
+
+// Split converts a duration into whole turns and the rounds left over.
+// Anything shorter than a round is dropped.
+func (c Clock) Split(d time.Duration) (turns, rounds int64) {
+	turns = int64(d / c.Turn)
+	rounds = int64((d % c.Turn) / c.Round)
+	return turns, rounds
+}
+
+// Time is a moment of in-world time.
+type Time struct {
+	// Day is the day number, starting at 1.
+	Day int64
+	// SinceMidnight is the time elapsed since the start of Day.
+	SinceMidnight time.Duration
+	// Watch is the watch of the day, starting at 1.
+	Watch int
+	// DayFraction is how far through the day it is, from 0 to 1.
+	DayFraction float64
+}
+
+// At returns the in-world time at a turn and round.
+func (c Clock) At(turn, round int64) Time {
+	total := c.Start + c.Elapsed(turn, round)
+
+	day := int64(total / c.Day)
+	since := total % c.Day
+	if since < 0 {
+		day--
+		since += c.Day
+	}
+
+	return Time{
+		Day:           day + 1,
+		SinceMidnight: since,
+		Watch:         int(since/c.Watch) + 1,
+		DayFraction:   float64(since) / float64(c.Day),
+	}
+}
+
+// DayStart returns the first turn that falls on a day. It is negative for
+// days before the session started.
+func (c Clock) DayStart(day int64) int64 {
+	since := time.Duration(day-1)*c.Day - c.Start
+	turns := int64(since / c.Turn)
+	if since > 0 && since%c.Turn != 0 {
+		turns++
+	}
+	return turns
+}
+
+// Hour returns the hour of the day.
+func (t Time) Hour() int {
+	return int(t.SinceMidnight / time.Hour)
+}
+
+// Minute returns the minute of the hour.
+func (t Time) Minute() int {
+	return int(t.SinceMidnight % time.Hour / time.Minute)
+}
+
+// Second returns the second of the minute.
+func (t Time) Second() int {
+	return int(t.SinceMidnight % time.Minute / time.Second)
+}
+
+// TimeOfDay names the part of the day: night, dawn, morning, afternoon,
+// evening. It scales with the length of the day.
+func (t Time) TimeOfDay() string {
+	hour := t.DayFraction * 24
+	switch {
+	case hour < 5:
+		return "night"
+	case hour < 7:
+		return "dawn"
+	case hour < 12:
+		return "morning"
+	case hour < 17:
+		return "afternoon"
+	case hour < 21:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// Clock formats the time of day as 07:40, or 07:40:30 when it is not on a
+// whole minute.
+func (t Time) Clock() string {
+	if t.Second() != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	}
+	return fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute())
+}
+
+func (t Time) String() string {
+	return fmt.Sprintf("Day %d, %s", t.Day, t.Clock())
+}
+
+// ParseDuration parses an in-world duration such as "3 hours", "1 watch",
+// "2 turns 3 rounds" or "1d 4h". Turns, rounds, watches and days use the
+// clock's unit lengths. Plain Go durations such as "90m" also work.
+func (c Clock) ParseDuration(s string) (time.Duration, error) {
+	if strings.Contains(s, "-") {
+		return 0, fmt.Errorf("invalid duration %q: durations cannot be negative", s)
+	}
+
+	fields := splitDuration(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty duration")
+	}
+	if len(fields) == 1 {
+		if d, err := time.ParseDuration(fields[0]); err == nil {
+			return d, nil
+		}
+	}
+
+	var total time.Duration
+	for i := 0; i < len(fields); i++ {
+		amount, err := strconv.Atoi(fields[i])
+		if err != nil || amount < 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected a number, got %q", s, fields[i])
+		}
+
+		// A number on its own counts turns.
+		unit := "turns"
+		if i+1 < len(fields) {
+			if _, err := strconv.Atoi(fields[i+1]); err != nil {
+				unit = fields[i+1]
+				i++
+			}
+		}
+
+		length, err := c.unit(unit)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		total += time.Duration(amount) * length
+	}
+	return total, nil
+}
+
+func (c Clock) unit(name string) (time.Duration, error) {
+	switch strings.ToLower(name) {
+	case "r", "rd", "rds", "round", "rounds":
+		return c.Round, nil
+	case "t", "turn", "turns":
+		return c.Turn, nil
+	case "w", "watch", "watches":
+		return c.Watch, nil
+	case "d", "day", "days":
+		return c.Day, nil
+	case "h", "hr", "hrs", "hour", "hours":
+		return time.Hour, nil
+	case "m", "min", "mins", "minute", "minutes":
+		return time.Minute, nil
+	case "s", "sec", "secs", "second", "seconds":
+		return time.Second, nil
+	}
+	return 0, fmt.Errorf("unknown unit %q", name)
+}
+
+// splitDuration splits "1d4h" and "1 day, 4 hours" alike into numbers and
+// unit names.
+func splitDuration(s string) []string {
+	var fields []string
+	var current strings.Builder
+	var digits bool
+
+	flush := func() {
+		if current.Len() > 0 {
+			fields = append(fields, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			if !digits {
+				flush()
+			}
+			digits = true
+			current.WriteRune(r)
+		case unicode.IsLetter(r):
+			if digits {
+				flush()
+			}
+			digits = false
+			current.WriteRune(r)
+		default:
+			flush()
+			digits = false
+		}
+	}
+	flush()
+
+	// Keep a lone Go duration such as "1h30m" whole so ParseDuration can
+	// try it first.
+	if len(fields) > 2 && !strings.ContainsAny(s, " ,") {
+		if _, err := time.ParseDuration(s); err == nil {
+			return []string{s}
+		}
+	}
+	return fields
+}
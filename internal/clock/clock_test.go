@@ -0,0 +1,96 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/script-wizards/spells/internal/config"
+)
+
+func TestClockAt(t *testing.T) {
+	c := New(config.DefaultConfig().Clock)
+
+	tests := []struct {
+		turn      int64
+		round     int64
+		expected  string
+		watch     int
+		timeOfDay string
+	}{
+		{0, 0, "Day 1, 08:00", 3, "morning"},
+		{6, 0, "Day 1, 09:00", 3, "morning"},
+		{6, 3, "Day 1, 09:00:30", 3, "morning"},
+		{58, 0, "Day 1, 17:40", 5, "evening"},
+		{96, 0, "Day 2, 00:00", 1, "night"},
+		{138, 0, "Day 2, 07:00", 2, "morning"},
+		{-6, 0, "Day 1, 07:00", 2, "morning"},
+		{-54, 0, "Day 0, 23:00", 6, "night"},
+	}
+
+	for _, tt := range tests {
+		at := c.At(tt.turn, tt.round)
+		if got := at.String(); got != tt.expected {
+			t.Errorf("At(%d, %d) = %q, expected %q", tt.turn, tt.round, got, tt.expected)
+		}
+		if at.Watch != tt.watch {
+			t.Errorf("At(%d, %d) watch = %d, expected %d", tt.turn, tt.round, at.Watch, tt.watch)
+		}
+		if got := at.TimeOfDay(); got != tt.timeOfDay {
+			t.Errorf("At(%d, %d) time of day = %q, expected %q", tt.turn, tt.round, got, tt.timeOfDay)
+		}
+	}
+}
+
+func TestClockUnits(t *testing.T) {
+	c := New(config.ClockConfig{TurnMinutes: 10, RoundSeconds: 6})
+
+	if got := c.RoundsPerTurn(); got != 100 {
+		t.Errorf("expected 100 rounds per turn, got %d", got)
+	}
+	if c.Watch != 4*time.Hour || c.Day != 24*time.Hour {
+		t.Errorf("expected unset units to use the defaults, got %+v", c)
+	}
+
+	turns, rounds := c.Split(25*time.Minute + 15*time.Second)
+	if turns != 2 || rounds != 52 {
+		t.Errorf("expected 2 turns and 52 rounds, got %d and %d", turns, rounds)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	c := New(config.DefaultConfig().Clock)
+
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"3 hours", 3 * time.Hour},
+		{"1 watch", 4 * time.Hour},
+		{"2 turns", 20 * time.Minute},
+		{"2 turns 3 rounds", 20*time.Minute + 30*time.Second},
+		{"1 day, 2 watches", 32 * time.Hour},
+		{"1d4h", 28 * time.Hour},
+		{"90m", 90 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"6", time.Hour},
+		{"5 Rounds", 50 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got, err := c.ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseDuration(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "hours", "3 fortnights", "-1 turn"} {
+		if _, err := c.ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) expected an error", input)
+		}
+	}
+}
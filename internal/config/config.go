@@ -4,34 +4,188 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	TorchDuration int `yaml:"torch_duration_turns"`
+	// TorchDuration, LanternDuration and MagicLightDuration are how many
+	// turns each kind of light burns. A light warns that it is about to go
+	// out once LightWarning turns or fewer are left.
+	TorchDuration      int `yaml:"torch_duration_turns"`
+	LanternDuration    int `yaml:"lantern_duration_turns"`
+	MagicLightDuration int `yaml:"magic_light_duration_turns"`
+	LightWarning       int `yaml:"light_warning_turns"`
+	// PartySize is how many PCs share the party's XP. XPConversionRate is
+	// the XP each gold piece of treasure is worth, and every award of XP
+	// is multiplied by XPMultiplier.
+	PartySize        int     `yaml:"party_size"`
+	XPConversionRate float64 `yaml:"xp_conversion_rate"`
+	XPMultiplier     float64 `yaml:"xp_multiplier"`
+	// Macros maps roll names such as "reaction" to dice expressions.
+	Macros map[string]string `yaml:"macros,omitempty"`
+	Clock  ClockConfig       `yaml:"clock"`
+	// Calendar is the campaign's in-world calendar. Without months, dates
+	// are shown as plain day numbers.
+	Calendar CalendarConfig `yaml:"calendar,omitempty"`
+	Combat   CombatConfig   `yaml:"combat"`
+	// Encumbrance sets how a character's load slows them down.
+	Encumbrance EncumbranceConfig `yaml:"encumbrance"`
+}
+
+// EncumbranceConfig sets the encumbrance rules. Load is "slots", counting
+// the inventory slots carried items take up, or "weight", adding up their
+// weight in coins. A character falls in the first of the Tiers whose Max
+// their load does not exceed and moves at its Movement in feet per turn;
+// a character carrying more than the last tier's Max cannot move.
+type EncumbranceConfig struct {
+	Load  string            `yaml:"load"`
+	Tiers []EncumbranceTier `yaml:"tiers"`
+}
+
+type EncumbranceTier struct {
+	Name     string `yaml:"name"`
+	Max      int    `yaml:"max"`
+	Movement int    `yaml:"movement"`
+}
+
+// CombatConfig sets the combat rules. Initiative is "individual", where
+// each combatant keeps the initiative it was given, or "side", where each
+// side rolls InitiativeDie at the start of every round. Ties between sides
+// are "simultaneous", "reroll" until every side differs, or "dex", which
+// orders tied combatants by Dexterity. ArmorClass is "descending", where
+// attacks roll against THAC0, or "ascending", where attack bonuses are
+// added to the roll. DefaultDamage is rolled for attackers without damage
+// of their own.
+type CombatConfig struct {
+	Initiative    string `yaml:"initiative"`
+	InitiativeDie string `yaml:"initiative_die"`
+	Ties          string `yaml:"ties"`
+	ArmorClass    string `yaml:"armor_class"`
+	DefaultDamage string `yaml:"default_damage"`
+}
+
+// ClockConfig sets the length of the in-world time units. StartHour is the
+// hour of the first day at which turn 0 falls.
+type ClockConfig struct {
+	TurnMinutes  int `yaml:"turn_minutes"`
+	RoundSeconds int `yaml:"round_seconds"`
+	WatchHours   int `yaml:"watch_hours"`
+	DayHours     int `yaml:"day_hours"`
+	StartHour    int `yaml:"start_hour"`
+}
+
+// CalendarConfig describes a fantasy calendar. Start is the date of the
+// first day of play.
+type CalendarConfig struct {
+	Months   []MonthConfig   `yaml:"months,omitempty"`
+	Weekdays []string        `yaml:"weekdays,omitempty"`
+	Moons    []MoonConfig    `yaml:"moons,omitempty"`
+	Holidays []HolidayConfig `yaml:"holidays,omitempty"`
+	Start    DateConfig      `yaml:"start,omitempty"`
+}
+
+type MonthConfig struct {
+	Name string `yaml:"name"`
+	Days int    `yaml:"days"`
+}
+
+// MoonConfig is a moon with a cycle in days. Offset is how many days into
+// its cycle the moon is on the first day of play; day 0 is the new moon.
+type MoonConfig struct {
+	Name   string `yaml:"name"`
+	Cycle  int    `yaml:"cycle"`
+	Offset int    `yaml:"offset,omitempty"`
+}
+
+// HolidayConfig is a named day that recurs every year. Month is the month's
+// name or its number.
+type HolidayConfig struct {
+	Name        string `yaml:"name"`
+	Month       string `yaml:"month"`
+	Day         int    `yaml:"day"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type DateConfig struct {
+	Year  int `yaml:"year"`
+	Month int `yaml:"month"`
+	Day   int `yaml:"day"`
 }
 
 func DefaultConfig() Config {
 	return Config{
-		TorchDuration: 10,
+		TorchDuration:      10,
+		LanternDuration:    24,
+		MagicLightDuration: 12,
+		LightWarning:       1,
+		PartySize:          4,
+		XPConversionRate:   1.0,
+		XPMultiplier:       1.0,
+		Macros: map[string]string{
+			"reaction": "2d6",
+			"morale":   "2d6",
+		},
+		Clock: ClockConfig{
+			TurnMinutes:  10,
+			RoundSeconds: 10,
+			WatchHours:   4,
+			DayHours:     24,
+			StartHour:    8,
+		},
+		Combat: CombatConfig{
+			Initiative:    "individual",
+			InitiativeDie: "1d6",
+			Ties:          "simultaneous",
+			ArmorClass:    "descending",
+			DefaultDamage: "1d6",
+		},
+		Encumbrance: EncumbranceConfig{
+			Load: "slots",
+			Tiers: []EncumbranceTier{
+				{Name: "unencumbered", Max: 10, Movement: 120},
+				{Name: "lightly encumbered", Max: 12, Movement: 90},
+				{Name: "heavily encumbered", Max: 14, Movement: 60},
+				{Name: "severely encumbered", Max: 16, Movement: 30},
+			},
+		},
 	}
 }
 
+// Dir returns the global spells configuration directory,
+// $XDG_CONFIG_HOME/spells or ~/.config/spells.
+func Dir() (string, error) {
+	xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfigHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		xdgConfigHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(xdgConfigHome, "spells"), nil
+}
+
+// TablesDir returns the global oracle tables directory.
+func TablesDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tables"), nil
+}
+
 func Load(path string) (Config, error) {
 	config := DefaultConfig()
 
 	configPath := path
 	if configPath == "" {
-		xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
-		if xdgConfigHome == "" {
-			homeDir, err := os.UserHomeDir()
-			if err != nil {
-				return config, fmt.Errorf("failed to get user home directory: %w", err)
-			}
-			xdgConfigHome = filepath.Join(homeDir, ".config")
+		dir, err := Dir()
+		if err != nil {
+			return config, err
 		}
-		configPath = filepath.Join(xdgConfigHome, "spells", "config.yaml")
+		configPath = filepath.Join(dir, "config.yaml")
 	}
 
 	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
@@ -61,6 +215,28 @@ func Load(path string) (Config, error) {
 	return config, nil
 }
 
+// LoadFiles reads each config file that exists over the defaults, so later
+// files override earlier ones. Missing files are skipped and never created.
+func LoadFiles(paths ...string) (Config, error) {
+	config := DefaultConfig()
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return config, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return config, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	return config, nil
+}
+
 func Save(config Config, path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
@@ -77,3 +253,30 @@ func Save(config Config, path string) error {
 
 	return nil
 }
+
+// SaveTemplate writes the defaults to path as commented-out YAML. The file
+// documents every setting without setting any, so it does not override
+// settings made in files loaded before it.
+func SaveTemplate(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(DefaultConfig())
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var template strings.Builder
+	template.WriteString("# Campaign settings. These override spells.yaml; uncomment a line to\n")
+	template.WriteString("# change it from the default shown.\n")
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		template.WriteString("# " + line + "\n")
+	}
+
+	if err := os.WriteFile(path, []byte(template.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -11,6 +12,12 @@ func TestDefaultConfig(t *testing.T) {
 	if config.TorchDuration != 10 {
 		t.Errorf("expected TorchDuration to be 10, got %d", config.TorchDuration)
 	}
+	if config.LanternDuration != 24 || config.MagicLightDuration != 12 || config.LightWarning != 1 {
+		t.Errorf("expected lanterns to burn 24 turns and magic light 12, warning at 1, got %+v", config)
+	}
+	if config.PartySize != 4 || config.XPConversionRate != 1 || config.XPMultiplier != 1 {
+		t.Errorf("expected a party of 4 earning 1 XP per gp, got %+v", config)
+	}
 }
 
 func TestLoad_NoFile(t *testing.T) {
@@ -84,3 +91,172 @@ func TestLoad_EmptyPath(t *testing.T) {
 		t.Error("expected config file to be created in XDG_CONFIG_HOME/spells/")
 	}
 }
+
+func TestTablesDir(t *testing.T) {
+	originalXDG := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", originalXDG)
+
+	tempDir := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", tempDir)
+
+	dir, err := TablesDir()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := filepath.Join(tempDir, "spells", "tables")
+	if dir != expected {
+		t.Errorf("expected tables dir %q, got %q", expected, dir)
+	}
+}
+
+func TestLoad_Macros(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "campaign.yaml")
+	yamlContent := "macros:\n  reaction: 2d6+1\n  surprise: 1d6\n"
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := map[string]string{
+		"reaction": "2d6+1",
+		"morale":   "2d6",
+		"surprise": "1d6",
+	}
+	for name, expr := range expected {
+		if config.Macros[name] != expr {
+			t.Errorf("expected macro %s to be %q, got %q", name, expr, config.Macros[name])
+		}
+	}
+}
+
+func TestLoad_ClockPartialOverride(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "campaign.yaml")
+	yamlContent := "clock:\n  round_seconds: 6\n"
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if config.Clock.RoundSeconds != 6 {
+		t.Errorf("expected RoundSeconds to be 6, got %d", config.Clock.RoundSeconds)
+	}
+	if config.Clock.TurnMinutes != 10 || config.Clock.WatchHours != 4 || config.Clock.DayHours != 24 {
+		t.Errorf("expected the other clock units to keep their defaults, got %+v", config.Clock)
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	settingsPath := filepath.Join(dir, "spells.yaml")
+	campaignPath := filepath.Join(dir, "campaign.yaml")
+
+	settings := `torch_duration_turns: 6
+calendar:
+  months:
+    - name: Deepwinter
+      days: 30
+  weekdays: [Moonday, Fireday]
+  moons:
+    - name: Selune
+      cycle: 28
+      offset: 3
+  holidays:
+    - name: Midwinter
+      month: Deepwinter
+      day: 15
+  start:
+    year: 1023
+    month: 1
+    day: 1
+`
+	if err := os.WriteFile(settingsPath, []byte(settings), 0644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+	if err := os.WriteFile(campaignPath, []byte("torch_duration_turns: 4\n"), 0644); err != nil {
+		t.Fatalf("failed to write campaign config: %v", err)
+	}
+
+	config, err := LoadFiles(settingsPath, campaignPath, filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if config.TorchDuration != 4 {
+		t.Errorf("expected the later file to win, got TorchDuration %d", config.TorchDuration)
+	}
+	calendar := config.Calendar
+	if len(calendar.Months) != 1 || calendar.Months[0].Days != 30 || len(calendar.Weekdays) != 2 {
+		t.Errorf("unexpected calendar: %+v", calendar)
+	}
+	if len(calendar.Moons) != 1 || calendar.Moons[0].Offset != 3 || calendar.Holidays[0].Month != "Deepwinter" {
+		t.Errorf("unexpected moons or holidays: %+v", calendar)
+	}
+	if calendar.Start.Year != 1023 {
+		t.Errorf("expected start year 1023, got %d", calendar.Start.Year)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "missing.yaml")); !os.IsNotExist(err) {
+		t.Error("expected missing files not to be created")
+	}
+}
+
+func TestLoad_Encumbrance(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	yamlContent := `encumbrance:
+  load: weight
+  tiers:
+    - {name: unencumbered, max: 400, movement: 120}
+    - {name: encumbered, max: 1600, movement: 60}
+`
+	if err := os.WriteFile(configPath, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	encumbrance := config.Encumbrance
+	if encumbrance.Load != "weight" || len(encumbrance.Tiers) != 2 || encumbrance.Tiers[1].Movement != 60 {
+		t.Errorf("expected the configured tiers to replace the defaults, got %+v", encumbrance)
+	}
+	if tiers := DefaultConfig().Encumbrance.Tiers; len(tiers) != 4 || tiers[0].Movement != 120 || tiers[3].Movement != 30 {
+		t.Errorf("expected default tiers moving 120' down to 30', got %+v", tiers)
+	}
+}
+
+func TestSaveTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "campaign.yaml")
+	if err := SaveTemplate(path); err != nil {
+		t.Fatalf("failed to save template: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+	if !strings.Contains(string(data), "# torch_duration_turns: 10\n") {
+		t.Errorf("expected the defaults commented out, got %q", data)
+	}
+
+	settings := filepath.Join(t.TempDir(), "spells.yaml")
+	if err := os.WriteFile(settings, []byte("party_size: 6\n"), 0644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+	config, err := LoadFiles(settings, path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if config.PartySize != 6 {
+		t.Errorf("expected the template not to override party_size, got %d", config.PartySize)
+	}
+}
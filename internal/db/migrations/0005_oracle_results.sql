@@ -0,0 +1,13 @@
+CREATE TABLE oracle_results (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_name TEXT,
+    query_context TEXT,
+    result TEXT NOT NULL,
+    nested_results TEXT, -- JSON of resolved sub-tables
+    session_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_oracle_results_session ON oracle_results(session_id, created_at);
+CREATE INDEX idx_oracle_results_table ON oracle_results(table_name, created_at);
@@ -0,0 +1,12 @@
+CREATE TABLE dice_rolls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    expression TEXT NOT NULL,
+    macro TEXT,
+    total INTEGER NOT NULL,
+    breakdown TEXT, -- JSON of per-term rolls
+    session_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_dice_rolls_session ON dice_rolls(session_id, created_at);
@@ -0,0 +1,13 @@
+CREATE TABLE time_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    trigger_turn INTEGER NOT NULL,
+    event_type TEXT NOT NULL, -- 'torch_burnout', 'wandering_check', 'spell_end'
+    description TEXT,
+    repeat_every INTEGER, -- turns between occurrences of a recurring event
+    handled BOOLEAN DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_time_events_pending ON time_events(session_id, handled, trigger_turn);
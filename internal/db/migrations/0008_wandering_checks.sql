@@ -0,0 +1,17 @@
+CREATE TABLE wandering_checks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    location TEXT, -- NULL applies to the whole session
+    frequency_turns INTEGER NOT NULL,
+    chance INTEGER NOT NULL DEFAULT 1, -- encounter on this or lower ...
+    die INTEGER NOT NULL DEFAULT 6, -- ... on a roll of this die
+    table_name TEXT NOT NULL,
+    create_encounter BOOLEAN DEFAULT 0,
+    time_event_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (time_event_id) REFERENCES time_events(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_wandering_checks_session ON wandering_checks(session_id);
+CREATE INDEX idx_wandering_checks_time_event ON wandering_checks(time_event_id);
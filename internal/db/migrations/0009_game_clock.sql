@@ -0,0 +1,4 @@
+-- Rounds elapsed within the current turn, and whether time is moving a
+-- turn ('exploration') or a round ('combat') at a time.
+ALTER TABLE sessions ADD COLUMN current_round INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN time_mode TEXT NOT NULL DEFAULT 'exploration';
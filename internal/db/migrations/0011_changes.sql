@@ -0,0 +1,115 @@
+-- Every write to shared session state is logged here by the triggers below,
+-- so other processes on the same database can see what changed. Rows are
+-- only kept for a day.
+CREATE TABLE changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_name TEXT NOT NULL,
+    row_id INTEGER NOT NULL,
+    action TEXT NOT NULL, -- 'insert', 'update', 'delete'
+    session_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_changes_created_at ON changes(created_at);
+
+CREATE TRIGGER changes_sessions_insert AFTER INSERT ON sessions
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('sessions', NEW.id, 'insert', NEW.id);
+END;
+
+CREATE TRIGGER changes_sessions_update AFTER UPDATE ON sessions
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('sessions', NEW.id, 'update', NEW.id);
+END;
+
+CREATE TRIGGER changes_encounters_insert AFTER INSERT ON encounters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('encounters', NEW.id, 'insert', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_encounters_update AFTER UPDATE ON encounters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('encounters', NEW.id, 'update', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_encounters_delete AFTER DELETE ON encounters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('encounters', OLD.id, 'delete', OLD.session_id);
+END;
+
+CREATE TRIGGER changes_initiative_order_insert AFTER INSERT ON initiative_order
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('initiative_order', NEW.id, 'insert', (SELECT session_id FROM encounters WHERE id = NEW.encounter_id));
+END;
+
+CREATE TRIGGER changes_initiative_order_update AFTER UPDATE ON initiative_order
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('initiative_order', NEW.id, 'update', (SELECT session_id FROM encounters WHERE id = NEW.encounter_id));
+END;
+
+CREATE TRIGGER changes_initiative_order_delete AFTER DELETE ON initiative_order
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('initiative_order', OLD.id, 'delete', (SELECT session_id FROM encounters WHERE id = OLD.encounter_id));
+END;
+
+CREATE TRIGGER changes_npcs_insert AFTER INSERT ON npcs
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('npcs', NEW.id, 'insert', NULL);
+END;
+
+CREATE TRIGGER changes_npcs_update AFTER UPDATE ON npcs
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('npcs', NEW.id, 'update', NULL);
+END;
+
+CREATE TRIGGER changes_npcs_delete AFTER DELETE ON npcs
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('npcs', OLD.id, 'delete', NULL);
+END;
+
+CREATE TRIGGER changes_time_events_insert AFTER INSERT ON time_events
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('time_events', NEW.id, 'insert', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_time_events_update AFTER UPDATE ON time_events
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('time_events', NEW.id, 'update', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_time_events_delete AFTER DELETE ON time_events
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('time_events', OLD.id, 'delete', OLD.session_id);
+END;
+
+CREATE TRIGGER changes_timers_insert AFTER INSERT ON timers
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('timers', NEW.id, 'insert', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_timers_update AFTER UPDATE ON timers
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('timers', NEW.id, 'update', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_timers_delete AFTER DELETE ON timers
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('timers', OLD.id, 'delete', OLD.session_id);
+END;
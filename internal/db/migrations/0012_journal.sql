@@ -0,0 +1,24 @@
+-- Append-only log of state-changing engine actions. changes holds a JSON
+-- array of row snapshots ({table, id, before, after}); a null before means
+-- the row was created and a null after that it was deleted. Undo and redo
+-- are logged as entries of their own, with reverts_id pointing at the
+-- entry they reverted or reapplied.
+CREATE TABLE journal (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER,
+    action TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    changes TEXT NOT NULL DEFAULT '[]',
+    reverts_id INTEGER REFERENCES journal(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_journal_session ON journal(session_id, id);
+CREATE INDEX idx_journal_reverts ON journal(reverts_id, id);
+
+CREATE TRIGGER changes_journal_insert AFTER INSERT ON journal
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('journal', NEW.id, 'insert', NEW.session_id);
+END;
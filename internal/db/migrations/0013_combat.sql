@@ -0,0 +1,5 @@
+-- Combat tracking: the round an encounter is in, whose turn it is, and
+-- when the encounter ended. Round 0 means no one has acted yet.
+ALTER TABLE encounters ADD COLUMN round INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE encounters ADD COLUMN current_combatant_id INTEGER;
+ALTER TABLE encounters ADD COLUMN ended_at TIMESTAMP;
@@ -0,0 +1,4 @@
+-- Sides for side (group) initiative, e.g. 'party', 'monsters' or a custom
+-- faction, and Dexterity for breaking initiative ties.
+ALTER TABLE initiative_order ADD COLUMN side TEXT NOT NULL DEFAULT 'monsters';
+ALTER TABLE initiative_order ADD COLUMN dex INTEGER;
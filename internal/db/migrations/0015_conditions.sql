@@ -0,0 +1,4 @@
+-- Conditions on a combatant, such as poisoned or asleep, as a JSON array
+-- of {name, unit, remaining}. unit is 'round' or 'turn' for conditions
+-- that wear off, and empty for those that last until removed.
+ALTER TABLE initiative_order ADD COLUMN conditions TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,38 @@
+-- A catalog of monster stat blocks, and the monster each combatant was
+-- spawned from. hit_dice is in the usual OSR notation ('1-1', '2+1', '1/2'
+-- or a dice expression) and movement is in feet per turn.
+CREATE TABLE monsters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    armor_class INTEGER NOT NULL DEFAULT 9,
+    hit_dice TEXT NOT NULL DEFAULT '1',
+    attacks TEXT,
+    damage TEXT, -- dice expression per attack, e.g. '1d6'
+    movement INTEGER NOT NULL DEFAULT 120,
+    morale INTEGER NOT NULL DEFAULT 7,
+    xp INTEGER NOT NULL DEFAULT 0,
+    alignment TEXT,
+    description TEXT,
+    tags TEXT, -- JSON array
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE initiative_order ADD COLUMN monster_id INTEGER;
+
+CREATE TRIGGER changes_monsters_insert AFTER INSERT ON monsters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('monsters', NEW.id, 'insert', NULL);
+END;
+
+CREATE TRIGGER changes_monsters_update AFTER UPDATE ON monsters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('monsters', NEW.id, 'update', NULL);
+END;
+
+CREATE TRIGGER changes_monsters_delete AFTER DELETE ON monsters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('monsters', OLD.id, 'delete', NULL);
+END;
@@ -0,0 +1,30 @@
+-- What a combatant needs to attack and be attacked: its armor class, in
+-- the campaign's AC system, its attack bonus (THAC0 19 is +0) and the
+-- damage of each attack in its routine, e.g. '1d6' or '1d4/1d4/2d6'.
+ALTER TABLE initiative_order ADD COLUMN armor_class INTEGER;
+ALTER TABLE initiative_order ADD COLUMN attack_bonus INTEGER;
+ALTER TABLE initiative_order ADD COLUMN damage TEXT;
+
+-- The combat log: a line for each resolved attack, by round.
+CREATE TABLE combat_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    encounter_id INTEGER NOT NULL,
+    round INTEGER NOT NULL DEFAULT 0,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (encounter_id) REFERENCES encounters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_combat_log_encounter ON combat_log(encounter_id, id);
+
+CREATE TRIGGER changes_combat_log_insert AFTER INSERT ON combat_log
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('combat_log', NEW.id, 'insert', (SELECT session_id FROM encounters WHERE id = NEW.encounter_id));
+END;
+
+CREATE TRIGGER changes_combat_log_delete AFTER DELETE ON combat_log
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('combat_log', OLD.id, 'delete', (SELECT session_id FROM encounters WHERE id = OLD.encounter_id));
+END;
@@ -0,0 +1,62 @@
+-- Treasure the party has found. Its gold value becomes XP once the
+-- referee awards it at the end of the session.
+CREATE TABLE treasure_found (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    item_description TEXT NOT NULL,
+    gold_value INTEGER NOT NULL DEFAULT 0,
+    location TEXT,
+    date_found TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    xp_awarded BOOLEAN DEFAULT false,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_treasure_found_session ON treasure_found(session_id);
+
+-- The XP ledger: a line for every award of XP, from a defeated monster
+-- (combatant_id) or from treasure (treasure_id). xp is what the party
+-- earned as a whole, after the campaign's multiplier.
+CREATE TABLE xp_ledger (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    source TEXT NOT NULL, -- 'monster', 'treasure'
+    description TEXT NOT NULL,
+    xp INTEGER NOT NULL,
+    combatant_id INTEGER,
+    treasure_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_xp_ledger_session ON xp_ledger(session_id);
+CREATE INDEX idx_xp_ledger_combatant ON xp_ledger(combatant_id);
+
+CREATE TRIGGER changes_treasure_found_insert AFTER INSERT ON treasure_found
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('treasure_found', NEW.id, 'insert', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_treasure_found_update AFTER UPDATE ON treasure_found
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('treasure_found', NEW.id, 'update', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_treasure_found_delete AFTER DELETE ON treasure_found
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('treasure_found', OLD.id, 'delete', OLD.session_id);
+END;
+
+CREATE TRIGGER changes_xp_ledger_insert AFTER INSERT ON xp_ledger
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('xp_ledger', NEW.id, 'insert', NEW.session_id);
+END;
+
+CREATE TRIGGER changes_xp_ledger_delete AFTER DELETE ON xp_ledger
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('xp_ledger', OLD.id, 'delete', OLD.session_id);
+END;
@@ -0,0 +1,37 @@
+-- The party roster: a record for each player character, and the character
+-- each combatant stands for. Characters belong to the campaign rather than
+-- a session; inactive ones are retired or sitting the session out.
+CREATE TABLE characters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    player TEXT,
+    class TEXT,
+    level INTEGER NOT NULL DEFAULT 1,
+    hp_max INTEGER,
+    hp_current INTEGER,
+    armor_class INTEGER,
+    xp INTEGER NOT NULL DEFAULT 0,
+    light_source TEXT,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE initiative_order ADD COLUMN character_id INTEGER;
+
+CREATE TRIGGER changes_characters_insert AFTER INSERT ON characters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('characters', NEW.id, 'insert', NULL);
+END;
+
+CREATE TRIGGER changes_characters_update AFTER UPDATE ON characters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('characters', NEW.id, 'update', NULL);
+END;
+
+CREATE TRIGGER changes_characters_delete AFTER DELETE ON characters
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('characters', OLD.id, 'delete', NULL);
+END;
@@ -0,0 +1,37 @@
+-- Burning light sources and consumable supplies. A character's light has
+-- light_remaining of light_duration turns left; NULL means it is not lit
+-- and 0 that it has gone out.
+ALTER TABLE characters ADD COLUMN light_remaining INTEGER;
+ALTER TABLE characters ADD COLUMN light_duration INTEGER;
+
+-- Supplies a character carries, such as rations or arrows. Those used
+-- 'per_day' go down by one each day that passes; those used 'per_use'
+-- only when they are spent.
+CREATE TABLE consumables (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL COLLATE NOCASE,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    usage TEXT NOT NULL DEFAULT 'per_use', -- 'per_day', 'per_use'
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (character_id, name),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER changes_consumables_insert AFTER INSERT ON consumables
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('consumables', NEW.id, 'insert', NULL);
+END;
+
+CREATE TRIGGER changes_consumables_update AFTER UPDATE ON consumables
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('consumables', NEW.id, 'update', NULL);
+END;
+
+CREATE TRIGGER changes_consumables_delete AFTER DELETE ON consumables
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('consumables', OLD.id, 'delete', NULL);
+END;
@@ -0,0 +1,33 @@
+-- Characters' inventories. slots and weight are for the whole stack;
+-- stashed items are kept somewhere safe and do not count towards the
+-- character's encumbrance.
+CREATE TABLE items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    name TEXT NOT NULL COLLATE NOCASE,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    slots INTEGER NOT NULL DEFAULT 1,
+    weight INTEGER NOT NULL DEFAULT 0,
+    stashed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (character_id, name),
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER changes_items_insert AFTER INSERT ON items
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('items', NEW.id, 'insert', NULL);
+END;
+
+CREATE TRIGGER changes_items_update AFTER UPDATE ON items
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('items', NEW.id, 'update', NULL);
+END;
+
+CREATE TRIGGER changes_items_delete AFTER DELETE ON items
+BEGIN
+    INSERT INTO changes (table_name, row_id, action, session_id)
+    VALUES ('items', OLD.id, 'delete', NULL);
+END;
@@ -0,0 +1,3 @@
+-- Where the party is. A wandering check with a location only rolls while
+-- the party is there; NULL means nowhere in particular.
+ALTER TABLE sessions ADD COLUMN location TEXT;
@@ -3,53 +3,168 @@ package dice
 import (
 	"fmt"
 	"math/rand"
-	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 )
 
-var diceExpr = regexp.MustCompile(`^(\d+)d(\d+)(?:([+-])(\d+))?$`)
+// maxExplosions caps how many times a single exploding die rolls again.
+const maxExplosions = 100
 
+// Result is the outcome of rolling an expression, with one entry per term.
+type Result struct {
+	Expr  string       `json:"expr"`
+	Total int          `json:"total"`
+	Terms []TermResult `json:"terms"`
+}
+
+// TermResult is the outcome of one term. Rolls holds every die in the order
+// it was rolled, after rerolls and with explosions added to the die that
+// exploded. Kept holds the dice that count towards the total.
+type TermResult struct {
+	Term     string `json:"term"`
+	Sign     int    `json:"sign"`
+	Rolls    []int  `json:"rolls,omitempty"`
+	Kept     []int  `json:"kept,omitempty"`
+	Subtotal int    `json:"subtotal"`
+}
+
+// Roll evaluates a dice expression and returns the total together with every
+// die rolled. See Parse for the expression syntax.
 func Roll(expr string, rng *rand.Rand) (total int, breakdown []int, err error) {
-	matches := diceExpr.FindStringSubmatch(expr)
-	if matches == nil {
-		return 0, nil, fmt.Errorf("invalid dice expression: %s", expr)
+	result, err := Evaluate(expr, rng)
+	if err != nil {
+		return 0, nil, err
 	}
 
-	numDice, err := strconv.Atoi(matches[1])
-	if err != nil {
-		return 0, nil, fmt.Errorf("invalid number of dice: %s", matches[1])
+	for _, term := range result.Terms {
+		breakdown = append(breakdown, term.Rolls...)
 	}
+	return result.Total, breakdown, nil
+}
 
-	diceSides, err := strconv.Atoi(matches[2])
+// Evaluate parses and rolls a dice expression, returning the per-term
+// breakdown.
+func Evaluate(expr string, rng *rand.Rand) (*Result, error) {
+	parsed, err := Parse(expr)
 	if err != nil {
-		return 0, nil, fmt.Errorf("invalid dice sides: %s", matches[2])
+		return nil, err
+	}
+	return parsed.Roll(rng), nil
+}
+
+// Roll rolls every term of the expression.
+func (e *Expr) Roll(rng *rand.Rand) *Result {
+	result := &Result{Expr: e.Source}
+
+	for _, term := range e.Terms {
+		termResult := TermResult{Term: term.String(), Sign: term.Sign}
+
+		if !term.IsDice() {
+			termResult.Subtotal = term.Sign * term.Constant
+		} else {
+			rolls := make([]int, term.Count)
+			for i := range rolls {
+				rolls[i] = term.rollDie(rng)
+			}
+			termResult.Rolls = rolls
+			termResult.Kept = term.keep(rolls)
+
+			sum := 0
+			for _, roll := range termResult.Kept {
+				sum += roll
+			}
+			termResult.Subtotal = term.Sign * sum
+		}
+
+		result.Terms = append(result.Terms, termResult)
+		result.Total += termResult.Subtotal
 	}
 
-	if numDice <= 0 || diceSides <= 0 {
-		return 0, nil, fmt.Errorf("dice count and sides must be positive")
+	return result
+}
+
+// rollDie rolls a single die of the term, applying rerolls and explosions.
+func (t Term) rollDie(rng *rand.Rand) int {
+	roll := rng.Intn(t.Sides) + 1
+	for t.Reroll > 0 && roll <= t.Reroll {
+		roll = rng.Intn(t.Sides) + 1
 	}
 
-	breakdown = make([]int, numDice)
-	total = 0
+	value := roll
+	for i := 0; t.Explode && roll == t.Sides && i < maxExplosions; i++ {
+		roll = rng.Intn(t.Sides) + 1
+		value += roll
+	}
+	return value
+}
 
-	for i := 0; i < numDice; i++ {
-		roll := rng.Intn(diceSides) + 1
-		breakdown[i] = roll
-		total += roll
+// keep returns the dice that count towards the term's total, in the order
+// they were rolled.
+func (t Term) keep(rolls []int) []int {
+	if t.Keep == 0 || t.Keep >= len(rolls) {
+		return append([]int(nil), rolls...)
 	}
 
-	if matches[3] != "" && matches[4] != "" {
-		modifier, err := strconv.Atoi(matches[4])
-		if err != nil {
-			return 0, nil, fmt.Errorf("invalid modifier: %s", matches[4])
+	order := make([]int, len(rolls))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if t.KeepLow {
+			return rolls[order[i]] < rolls[order[j]]
 		}
+		return rolls[order[i]] > rolls[order[j]]
+	})
 
-		if matches[3] == "+" {
-			total += modifier
-		} else {
-			total -= modifier
+	keep := make([]bool, len(rolls))
+	for _, idx := range order[:t.Keep] {
+		keep[idx] = true
+	}
+
+	kept := make([]int, 0, t.Keep)
+	for i, roll := range rolls {
+		if keep[i] {
+			kept = append(kept, roll)
 		}
 	}
+	return kept
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("%s = %d", r.Expr, r.Total)
+}
+
+// Breakdown formats the result with the dice rolled for each term, such as
+// "4d6kh3 [6 (1) 5 2] + 2 = 15". Dropped dice are shown in parentheses.
+func (r *Result) Breakdown() string {
+	var b strings.Builder
+	for i, term := range r.Terms {
+		switch {
+		case i > 0 && term.Sign < 0:
+			b.WriteString(" - ")
+		case i > 0:
+			b.WriteString(" + ")
+		case term.Sign < 0:
+			b.WriteString("-")
+		}
+		b.WriteString(term.Term)
 
-	return total, breakdown, nil
+		if len(term.Rolls) == 0 {
+			continue
+		}
+		kept := term.Kept
+		dice := make([]string, len(term.Rolls))
+		for j, roll := range term.Rolls {
+			if len(kept) > 0 && kept[0] == roll {
+				dice[j] = strconv.Itoa(roll)
+				kept = kept[1:]
+			} else {
+				dice[j] = fmt.Sprintf("(%d)", roll)
+			}
+		}
+		fmt.Fprintf(&b, " [%s]", strings.Join(dice, " "))
+	}
+	fmt.Fprintf(&b, " = %d", r.Total)
+	return b.String()
 }
@@ -84,3 +84,110 @@ func TestRoll(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		result, err := Evaluate("4d6kh3 + 1d4 - 1", rng)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+
+		if len(result.Terms) != 3 {
+			t.Fatalf("Evaluate() returned %d terms, expected 3", len(result.Terms))
+		}
+
+		abilities := result.Terms[0]
+		if len(abilities.Rolls) != 4 || len(abilities.Kept) != 3 {
+			t.Fatalf("Expected 4 rolls with 3 kept, got %+v", abilities)
+		}
+		lowest := abilities.Rolls[0]
+		sum := 0
+		for _, roll := range abilities.Rolls {
+			sum += roll
+			if roll < lowest {
+				lowest = roll
+			}
+		}
+		if abilities.Subtotal != sum-lowest {
+			t.Fatalf("Expected kept subtotal %d, got %d (%+v)", sum-lowest, abilities.Subtotal, abilities)
+		}
+
+		if result.Terms[2].Subtotal != -1 {
+			t.Fatalf("Expected constant subtotal -1, got %d", result.Terms[2].Subtotal)
+		}
+
+		total := 0
+		for _, term := range result.Terms {
+			total += term.Subtotal
+		}
+		if result.Total != total {
+			t.Fatalf("Expected total %d to match term subtotals %d", result.Total, total)
+		}
+	}
+}
+
+func TestEvaluateModifiers(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	sawExplosion := false
+	for i := 0; i < 500; i++ {
+		result, err := Evaluate("1d6!", rng)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		if result.Total < 1 || result.Total == 6 {
+			t.Fatalf("Exploding d6 cannot total %d", result.Total)
+		}
+		if result.Total > 6 {
+			sawExplosion = true
+		}
+
+		result, err = Evaluate("1d8r2", rng)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		if result.Total < 3 || result.Total > 8 {
+			t.Fatalf("Reroll 1d8r2 cannot total %d", result.Total)
+		}
+
+		result, err = Evaluate("2d20kl1", rng)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		rolls := result.Terms[0].Rolls
+		if result.Total != min(rolls[0], rolls[1]) {
+			t.Fatalf("Disadvantage %v should total %d, got %d", rolls, min(rolls[0], rolls[1]), result.Total)
+		}
+
+		result, err = Evaluate("d%", rng)
+		if err != nil {
+			t.Fatalf("Evaluate() unexpected error: %v", err)
+		}
+		if result.Total < 1 || result.Total > 100 {
+			t.Fatalf("Percentile roll out of range: %d", result.Total)
+		}
+	}
+
+	if !sawExplosion {
+		t.Error("Expected at least one exploding d6 to explode")
+	}
+}
+
+func TestResultBreakdown(t *testing.T) {
+	result := &Result{
+		Expr:  "4d6kh3-1d4+2",
+		Total: 14,
+		Terms: []TermResult{
+			{Term: "4d6kh3", Sign: 1, Rolls: []int{6, 1, 5, 2}, Kept: []int{6, 5, 2}, Subtotal: 13},
+			{Term: "1d4", Sign: -1, Rolls: []int{1}, Kept: []int{1}, Subtotal: -1},
+			{Term: "2", Sign: 1, Subtotal: 2},
+		},
+	}
+
+	expected := "4d6kh3 [6 (1) 5 2] - 1d4 [1] + 2 = 14"
+	if got := result.Breakdown(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
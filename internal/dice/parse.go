@@ -0,0 +1,301 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxDice is the most dice a single term may roll.
+	MaxDice = 1000
+	// MaxSides is the most sides a die may have.
+	MaxSides = 10000
+)
+
+// Expr is a parsed dice expression: a sum of dice and constant terms.
+type Expr struct {
+	Source string
+	Terms  []Term
+}
+
+// Term is one signed part of an expression. Dice terms have Sides > 0;
+// constant terms have Sides == 0 and use Constant. Keep is the number of dice
+// kept, highest unless KeepLow is set; zero keeps them all. Dice showing
+// Reroll or lower are rerolled, and exploding dice that roll their maximum
+// roll again and add the result to the same die.
+type Term struct {
+	Sign     int
+	Count    int
+	Sides    int
+	Constant int
+	Keep     int
+	KeepLow  bool
+	Explode  bool
+	Reroll   int
+}
+
+// IsDice reports whether the term rolls dice.
+func (t Term) IsDice() bool {
+	return t.Sides > 0
+}
+
+func (t Term) String() string {
+	if !t.IsDice() {
+		return strconv.Itoa(t.Constant)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%dd%d", t.Count, t.Sides)
+	if t.Explode {
+		b.WriteString("!")
+	}
+	if t.Reroll > 0 {
+		fmt.Fprintf(&b, "r%d", t.Reroll)
+	}
+	if t.Keep > 0 {
+		if t.KeepLow {
+			fmt.Fprintf(&b, "kl%d", t.Keep)
+		} else {
+			fmt.Fprintf(&b, "kh%d", t.Keep)
+		}
+	}
+	return b.String()
+}
+
+// Parse parses a dice expression. Terms are joined with + and -, and each
+// term is a constant or a dice roll:
+//
+//	NdS     N dice with S sides; N defaults to 1 (d20)
+//	Nd%     percentile dice, d100
+//	khK     keep the highest K dice (also kK)
+//	klK     keep the lowest K dice
+//	dhK     drop the highest K dice
+//	dlK     drop the lowest K dice
+//	!       exploding dice: a maximum roll rolls again and adds on
+//	rK      reroll dice showing K or less
+//
+// For example 2d6+1d4-1, 4d6kh3, 2d20kl1, 1d6! and 1d8r1.
+func Parse(expr string) (*Expr, error) {
+	p := &exprParser{src: expr}
+
+	parsed, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid dice expression %q: %w", expr, err)
+	}
+	return parsed, nil
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) parse() (*Expr, error) {
+	expr := &Expr{Source: strings.TrimSpace(p.src)}
+
+	sign := 1
+	p.skipSpace()
+	if p.peek() == '-' || p.peek() == '+' {
+		if p.next() == '-' {
+			sign = -1
+		}
+	}
+
+	for {
+		term, err := p.parseTerm(sign)
+		if err != nil {
+			return nil, err
+		}
+		expr.Terms = append(expr.Terms, term)
+
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+
+		switch p.next() {
+		case '+':
+			sign = 1
+		case '-':
+			sign = -1
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", p.src[p.pos-1], p.pos)
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *exprParser) parseTerm(sign int) (Term, error) {
+	term := Term{Sign: sign}
+
+	p.skipSpace()
+	count, hasCount, err := p.number()
+	if err != nil {
+		return term, err
+	}
+
+	if p.peek() != 'd' && p.peek() != 'D' {
+		if !hasCount {
+			return term, p.unexpected()
+		}
+		term.Constant = count
+		return term, nil
+	}
+	p.pos++
+
+	if !hasCount {
+		count = 1
+	}
+	if count < 1 || count > MaxDice {
+		return term, fmt.Errorf("dice count must be between 1 and %d", MaxDice)
+	}
+	term.Count = count
+
+	if p.peek() == '%' {
+		p.pos++
+		term.Sides = 100
+	} else {
+		sides, ok, err := p.number()
+		if err != nil {
+			return term, err
+		}
+		if !ok {
+			return term, fmt.Errorf("missing dice sides at position %d", p.pos)
+		}
+		if sides < 1 || sides > MaxSides {
+			return term, fmt.Errorf("dice sides must be between 1 and %d", MaxSides)
+		}
+		term.Sides = sides
+	}
+
+	if err := p.parseModifiers(&term); err != nil {
+		return term, err
+	}
+	return term, nil
+}
+
+func (p *exprParser) parseModifiers(term *Term) error {
+	hasKeep := false
+
+	for !p.done() {
+		rest := strings.ToLower(p.src[p.pos:])
+
+		switch {
+		case rest[0] == '!':
+			if term.Explode {
+				return fmt.Errorf("duplicate explode modifier")
+			}
+			if term.Sides < 2 {
+				return fmt.Errorf("cannot explode a d%d", term.Sides)
+			}
+			p.pos++
+			term.Explode = true
+
+		case rest[0] == 'r':
+			if term.Reroll > 0 {
+				return fmt.Errorf("duplicate reroll modifier")
+			}
+			p.pos++
+			n, ok, err := p.number()
+			if err != nil {
+				return err
+			}
+			if !ok || n < 1 || n >= term.Sides {
+				return fmt.Errorf("reroll must be between 1 and %d", term.Sides-1)
+			}
+			term.Reroll = n
+
+		case rest[0] == 'k', strings.HasPrefix(rest, "dh"), strings.HasPrefix(rest, "dl"):
+			if hasKeep {
+				return fmt.Errorf("only one keep or drop modifier is allowed")
+			}
+			hasKeep = true
+
+			op := "kh"
+			if len(rest) >= 2 && (rest[:2] == "kh" || rest[:2] == "kl" || rest[:2] == "dh" || rest[:2] == "dl") {
+				op = rest[:2]
+				p.pos += 2
+			} else {
+				p.pos++
+			}
+
+			n, ok, err := p.number()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("missing count for %s at position %d", op, p.pos)
+			}
+
+			switch op {
+			case "kh", "kl":
+				if n < 1 || n > term.Count {
+					return fmt.Errorf("can only keep between 1 and %d dice", term.Count)
+				}
+				term.Keep = n
+				term.KeepLow = op == "kl"
+			case "dh", "dl":
+				if n < 1 || n >= term.Count {
+					return fmt.Errorf("can only drop between 1 and %d dice", term.Count-1)
+				}
+				term.Keep = term.Count - n
+				term.KeepLow = op == "dh"
+			}
+
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// number reads the digits at the current position, if there are any. A
+// number too large for an int is an error rather than no number, so that
+// 99999999999999999999d6 is not read as d6.
+func (p *exprParser) number() (int, bool, error) {
+	start := p.pos
+	for !p.done() && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		return 0, false, fmt.Errorf("number %s at position %d is too large", p.src[start:p.pos], start+1)
+	}
+	return n, true, nil
+}
+
+func (p *exprParser) unexpected() error {
+	if p.done() {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", p.src[p.pos], p.pos+1)
+}
+
+func (p *exprParser) skipSpace() {
+	for !p.done() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *exprParser) next() byte {
+	ch := p.peek()
+	p.pos++
+	return ch
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.src)
+}
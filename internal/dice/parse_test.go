@@ -0,0 +1,84 @@
+package dice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []Term
+	}{
+		{"2d6+1", []Term{{Sign: 1, Count: 2, Sides: 6}, {Sign: 1, Constant: 1}}},
+		{"2d6 + 1d4 - 1", []Term{{Sign: 1, Count: 2, Sides: 6}, {Sign: 1, Count: 1, Sides: 4}, {Sign: -1, Constant: 1}}},
+		{"d20", []Term{{Sign: 1, Count: 1, Sides: 20}}},
+		{"d%", []Term{{Sign: 1, Count: 1, Sides: 100}}},
+		{"4d6kh3", []Term{{Sign: 1, Count: 4, Sides: 6, Keep: 3}}},
+		{"4d6k3", []Term{{Sign: 1, Count: 4, Sides: 6, Keep: 3}}},
+		{"2d20kl1", []Term{{Sign: 1, Count: 2, Sides: 20, Keep: 1, KeepLow: true}}},
+		{"4d6dl1", []Term{{Sign: 1, Count: 4, Sides: 6, Keep: 3}}},
+		{"3d6dh1", []Term{{Sign: 1, Count: 3, Sides: 6, Keep: 2, KeepLow: true}}},
+		{"1d6!", []Term{{Sign: 1, Count: 1, Sides: 6, Explode: true}}},
+		{"1d8r1", []Term{{Sign: 1, Count: 1, Sides: 8, Reroll: 1}}},
+		{"3D6!r2kh2", []Term{{Sign: 1, Count: 3, Sides: 6, Explode: true, Reroll: 2, Keep: 2}}},
+		{"-1d4+5", []Term{{Sign: -1, Count: 1, Sides: 4}, {Sign: 1, Constant: 5}}},
+		{"7", []Term{{Sign: 1, Constant: 7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			parsed, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(parsed.Terms, tt.expected) {
+				t.Errorf("Parse(%q) = %+v, expected %+v", tt.expr, parsed.Terms, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"", "invalid", "0d6", "2d0", "1d", "2d6+", "2d6 goblins", "1d6kh", "2d6kh3",
+		"2d6dl2", "1d1!", "1d6r6", "1d6!!", "4d6kh3kl1", "1001d6", "1d10001",
+		"1000000000d6", "1d1000000000", "99999999999999999999d6", "1d99999999999999999999",
+		"99999999999999999999", "4d6kh99999999999999999999",
+	}
+
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) expected error but got none", expr)
+		}
+	}
+}
+
+func TestParseTooLarge(t *testing.T) {
+	_, err := Parse("99999999999999999999d6")
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected an overflowing dice count to be too large, got %v", err)
+	}
+
+	_, err = Parse("1000000000d6")
+	if err == nil || !strings.Contains(err.Error(), "between 1 and 1000") {
+		t.Errorf("expected a billion dice to be over the limit, got %v", err)
+	}
+}
+
+func TestTermString(t *testing.T) {
+	parsed, err := Parse("4d6dl1 + 1d6!r1 - 2")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	var got []string
+	for _, term := range parsed.Terms {
+		got = append(got, term.String())
+	}
+	expected := []string{"4d6kh3", "1d6!r1", "2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Term strings = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,351 @@
+package dice
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// explodeEpsilon is the probability below which further explosions are
+	// left out of a distribution. It keeps exploding dice finite without
+	// moving any reported probability by a visible amount.
+	explodeEpsilon = 1e-15
+	// maxStatsWork caps the arithmetic spent on one distribution, so huge
+	// expressions such as 1000d10000kh500 fail fast instead of hanging.
+	maxStatsWork = 200_000_000
+)
+
+// Stats is the exact probability distribution of a dice expression.
+type Stats struct {
+	Expr     string    `json:"expr"`
+	Min      int       `json:"min"`
+	Max      int       `json:"max"`
+	Mean     float64   `json:"mean"`
+	StdDev   float64   `json:"stddev"`
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+// Outcome is the chance of rolling exactly Value and of rolling Value or
+// more.
+type Outcome struct {
+	Value       int     `json:"value"`
+	Probability float64 `json:"probability"`
+	AtLeast     float64 `json:"at_least"`
+}
+
+// Distribution parses a dice expression and computes its exact
+// distribution.
+func Distribution(expr string) (*Stats, error) {
+	parsed, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Distribution()
+}
+
+// Distribution computes the exact distribution of the expression by
+// convolving the distributions of its terms. Keep and drop modifiers are
+// handled exactly; exploding dice are followed until further explosions are
+// vanishingly unlikely.
+func (e *Expr) Distribution() (*Stats, error) {
+	total := pointDist(0)
+	for _, term := range e.Terms {
+		d, err := term.distribution()
+		if err != nil {
+			return nil, fmt.Errorf("cannot compute distribution of %s: %w", e.Source, err)
+		}
+		if term.Sign < 0 {
+			d = d.negate()
+		}
+		if err := checkWork(len(total.p) * len(d.p)); err != nil {
+			return nil, fmt.Errorf("cannot compute distribution of %s: %w", e.Source, err)
+		}
+		total = convolve(total, d)
+	}
+	return total.stats(e.Source), nil
+}
+
+// P returns the probability of rolling exactly value.
+func (s *Stats) P(value int) float64 {
+	if value < s.Min || value > s.Max {
+		return 0
+	}
+	return s.Outcomes[value-s.Min].Probability
+}
+
+// AtLeast returns the probability of rolling value or more.
+func (s *Stats) AtLeast(value int) float64 {
+	switch {
+	case value <= s.Min:
+		return 1
+	case value > s.Max:
+		return 0
+	}
+	return s.Outcomes[value-s.Min].AtLeast
+}
+
+// AtMost returns the probability of rolling value or less.
+func (s *Stats) AtMost(value int) float64 {
+	return 1 - s.AtLeast(value+1)
+}
+
+// Histogram draws the distribution as one bar per value, scaled so the
+// most likely value fills width characters. Values too unlikely to show
+// at two decimal places are left off either end.
+func (s *Stats) Histogram(width int) string {
+	peak := 0.0
+	for _, outcome := range s.Outcomes {
+		peak = math.Max(peak, outcome.Probability)
+	}
+
+	first, last := 0, len(s.Outcomes)-1
+	for first < last && s.Outcomes[first].Probability < 0.00005 {
+		first++
+	}
+	for last > first && s.Outcomes[last].Probability < 0.00005 {
+		last--
+	}
+
+	valueWidth := len(fmt.Sprint(s.Outcomes[first].Value))
+	if w := len(fmt.Sprint(s.Outcomes[last].Value)); w > valueWidth {
+		valueWidth = w
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%*s  %7s  %7s\n", valueWidth, "", "=", ">=")
+	for _, outcome := range s.Outcomes[first : last+1] {
+		bar := 0
+		if peak > 0 {
+			bar = int(math.Round(outcome.Probability / peak * float64(width)))
+		}
+		line := fmt.Sprintf("%*d  %6.2f%%  %6.2f%%  %s", valueWidth, outcome.Value,
+			outcome.Probability*100, outcome.AtLeast*100, strings.Repeat("#", bar))
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// dist is a discrete distribution: p[i] is the chance of rolling min+i.
+type dist struct {
+	min int
+	p   []float64
+}
+
+func pointDist(value int) dist {
+	return dist{min: value, p: []float64{1}}
+}
+
+func (d dist) max() int {
+	return d.min + len(d.p) - 1
+}
+
+func (d dist) negate() dist {
+	p := make([]float64, len(d.p))
+	for i, prob := range d.p {
+		p[len(p)-1-i] = prob
+	}
+	return dist{min: -d.max(), p: p}
+}
+
+func convolve(a, b dist) dist {
+	p := make([]float64, len(a.p)+len(b.p)-1)
+	for i, pa := range a.p {
+		if pa == 0 {
+			continue
+		}
+		for j, pb := range b.p {
+			p[i+j] += pa * pb
+		}
+	}
+	return dist{min: a.min + b.min, p: p}
+}
+
+func (d dist) stats(expr string) *Stats {
+	// Trim impossible values left at either end, e.g. by rerolls.
+	first, last := 0, len(d.p)-1
+	for first < last && d.p[first] == 0 {
+		first++
+	}
+	for last > first && d.p[last] == 0 {
+		last--
+	}
+
+	s := &Stats{Expr: expr, Min: d.min + first, Max: d.min + last}
+	for i := first; i <= last; i++ {
+		value := float64(d.min + i)
+		s.Mean += value * d.p[i]
+	}
+
+	variance := 0.0
+	atLeast := 0.0
+	s.Outcomes = make([]Outcome, last-first+1)
+	for i := last; i >= first; i-- {
+		value := d.min + i
+		diff := float64(value) - s.Mean
+		variance += diff * diff * d.p[i]
+
+		atLeast += d.p[i]
+		s.Outcomes[i-first] = Outcome{
+			Value:       value,
+			Probability: d.p[i],
+			AtLeast:     math.Min(atLeast, 1),
+		}
+	}
+	s.StdDev = math.Sqrt(variance)
+	return s
+}
+
+func checkWork(work int) error {
+	if work > maxStatsWork {
+		return fmt.Errorf("expression is too large to analyze")
+	}
+	return nil
+}
+
+// distribution returns the distribution of the term's unsigned value.
+func (t Term) distribution() (dist, error) {
+	if !t.IsDice() {
+		return pointDist(t.Constant), nil
+	}
+
+	die := t.dieDistribution()
+	if t.Keep == 0 || t.Keep >= t.Count {
+		total := pointDist(0)
+		for i := 0; i < t.Count; i++ {
+			if err := checkWork(len(total.p) * len(die.p)); err != nil {
+				return dist{}, err
+			}
+			total = convolve(total, die)
+		}
+		return total, nil
+	}
+	return t.keepDistribution(die)
+}
+
+// dieDistribution returns the distribution of one die after rerolls and
+// explosions, matching rollDie.
+func (t Term) dieDistribution() dist {
+	faces := t.Sides - t.Reroll
+	d := dist{min: 1, p: make([]float64, t.Sides)}
+	for v := t.Reroll + 1; v <= t.Sides; v++ {
+		d.p[v-1] = 1 / float64(faces)
+	}
+	if !t.Explode {
+		return d
+	}
+
+	// An explosion rolls a plain die with no reroll. Build the chain of
+	// further explosions from the deepest one outwards.
+	depth := 0
+	for reach := 1 / float64(faces); depth < maxExplosions && reach > explodeEpsilon; depth++ {
+		reach /= float64(t.Sides)
+	}
+
+	chain := pointDist(0)
+	for i := 0; i < depth; i++ {
+		next := dist{min: 1, p: make([]float64, t.Sides+chain.max())}
+		for v := 1; v < t.Sides; v++ {
+			next.p[v-1] += 1 / float64(t.Sides)
+		}
+		for j, prob := range chain.p {
+			next.p[t.Sides-1+chain.min+j] += prob / float64(t.Sides)
+		}
+		chain = next
+	}
+
+	exploded := dist{min: 1, p: make([]float64, t.Sides+chain.max())}
+	copy(exploded.p, d.p[:t.Sides-1])
+	for j, prob := range chain.p {
+		exploded.p[t.Sides-1+chain.min+j] += d.p[t.Sides-1] * prob
+	}
+	return exploded
+}
+
+// keepDistribution returns the distribution of the sum of the Keep highest
+// (or lowest) of Count dice. It walks the die's values from the best kept
+// value down, tracking how many dice have been placed and the kept sum:
+// given that the remaining dice all fall at or below the current value, the
+// number showing exactly that value is binomial.
+func (t Term) keepDistribution(die dist) (dist, error) {
+	values := make([]int, 0, len(die.p))
+	for i := range die.p {
+		if die.p[i] > 0 {
+			values = append(values, die.min+i)
+		}
+	}
+	if !t.KeepLow {
+		for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	maxSum := t.Keep * die.max()
+	if err := checkWork(len(values) * (t.Count + 1) * (t.Count + 1) * (maxSum + 1)); err != nil {
+		return dist{}, err
+	}
+
+	// state[placed][sum] is the chance that exactly placed dice rolled the
+	// values seen so far, with the kept ones summing to sum.
+	state := make([][]float64, t.Count+1)
+	for i := range state {
+		state[i] = make([]float64, maxSum+1)
+	}
+	state[0][0] = 1
+
+	remaining := 1.0
+	binom := binomialTable(t.Count)
+	for _, value := range values {
+		p := die.p[value-die.min]
+		q := p / remaining
+		if q > 1 {
+			q = 1
+		}
+		remaining -= p
+
+		// chances[left][c] is the chance that c of left dice show value.
+		chances := make([][]float64, t.Count+1)
+		for left := range chances {
+			chances[left] = make([]float64, left+1)
+			for c := range chances[left] {
+				chances[left][c] = binom[left][c] * math.Pow(q, float64(c)) * math.Pow(1-q, float64(left-c))
+			}
+		}
+
+		next := make([][]float64, t.Count+1)
+		for i := range next {
+			next[i] = make([]float64, maxSum+1)
+		}
+		for placed := 0; placed <= t.Count; placed++ {
+			left := t.Count - placed
+			for sum, prob := range state[placed] {
+				if prob == 0 {
+					continue
+				}
+				for c, chance := range chances[left] {
+					if chance == 0 {
+						continue
+					}
+					kept := min(c, max(t.Keep-placed, 0))
+					next[placed+c][sum+kept*value] += prob * chance
+				}
+			}
+		}
+		state = next
+	}
+
+	return dist{min: 0, p: state[t.Count]}, nil
+}
+
+// binomialTable returns Pascal's triangle up to n as floats.
+func binomialTable(n int) [][]float64 {
+	table := make([][]float64, n+1)
+	for i := range table {
+		table[i] = make([]float64, i+1)
+		table[i][0], table[i][i] = 1, 1
+		for j := 1; j < i; j++ {
+			table[i][j] = table[i-1][j-1] + table[i-1][j]
+		}
+	}
+	return table
+}
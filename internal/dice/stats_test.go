@@ -0,0 +1,134 @@
+package dice
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestDistribution(t *testing.T) {
+	tests := []struct {
+		expr   string
+		min    int
+		max    int
+		mean   float64
+		stddev float64
+	}{
+		{"1d6", 1, 6, 3.5, math.Sqrt(35.0 / 12)},
+		{"2d6", 2, 12, 7, math.Sqrt(35.0 / 6)},
+		{"1d6+1", 2, 7, 4.5, math.Sqrt(35.0 / 12)},
+		{"1d6-1d6", -5, 5, 0, math.Sqrt(35.0 / 6)},
+		{"-1d4", -4, -1, -2.5, math.Sqrt(15.0 / 12)},
+		{"1d6r1", 2, 6, 4, math.Sqrt(2)},
+		{"4d6kh3", 3, 18, 15869.0 / 1296, 2.846844894},
+		{"4d6dl1", 3, 18, 15869.0 / 1296, 2.846844894},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			stats, err := Distribution(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stats.Min != tt.min || stats.Max != tt.max {
+				t.Errorf("expected range %d..%d, got %d..%d", tt.min, tt.max, stats.Min, stats.Max)
+			}
+			if !approxEqual(stats.Mean, tt.mean) {
+				t.Errorf("expected mean %v, got %v", tt.mean, stats.Mean)
+			}
+			if math.Abs(stats.StdDev-tt.stddev) > 1e-6 {
+				t.Errorf("expected stddev %v, got %v", tt.stddev, stats.StdDev)
+			}
+
+			total := 0.0
+			for _, outcome := range stats.Outcomes {
+				total += outcome.Probability
+			}
+			if !approxEqual(total, 1) {
+				t.Errorf("expected probabilities to sum to 1, got %v", total)
+			}
+		})
+	}
+}
+
+func TestDistributionProbabilities(t *testing.T) {
+	stats, err := Distribution("2d6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !approxEqual(stats.AtLeast(9), 10.0/36) {
+		t.Errorf("expected P(2d6 >= 9) = 10/36, got %v", stats.AtLeast(9))
+	}
+	if !approxEqual(stats.P(7), 6.0/36) {
+		t.Errorf("expected P(2d6 = 7) = 6/36, got %v", stats.P(7))
+	}
+	if !approxEqual(stats.AtMost(4), 6.0/36) {
+		t.Errorf("expected P(2d6 <= 4) = 6/36, got %v", stats.AtMost(4))
+	}
+	if stats.AtLeast(2) != 1 || stats.AtLeast(13) != 0 || stats.P(1) != 0 {
+		t.Errorf("unexpected probabilities outside the range")
+	}
+
+	advantage, err := Distribution("2d20kh1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !approxEqual(advantage.P(20), 39.0/400) || !approxEqual(advantage.P(1), 1.0/400) {
+		t.Errorf("unexpected advantage odds: P(20)=%v P(1)=%v", advantage.P(20), advantage.P(1))
+	}
+
+	disadvantage, err := Distribution("2d20kl1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !approxEqual(disadvantage.P(1), 39.0/400) {
+		t.Errorf("expected P(2d20kl1 = 1) = 39/400, got %v", disadvantage.P(1))
+	}
+}
+
+func TestDistributionExploding(t *testing.T) {
+	stats, err := Distribution("1d6!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Min != 1 || stats.P(6) != 0 {
+		t.Errorf("expected an exploding d6 never to total exactly 6, got P(6)=%v", stats.P(6))
+	}
+	if !approxEqual(stats.P(7), 1.0/36) {
+		t.Errorf("expected P(7) = 1/36, got %v", stats.P(7))
+	}
+	if math.Abs(stats.Mean-4.2) > 1e-9 {
+		t.Errorf("expected mean 4.2, got %v", stats.Mean)
+	}
+}
+
+func TestDistributionTooLarge(t *testing.T) {
+	if _, err := Distribution("1000d10000kh500"); err == nil {
+		t.Error("expected an error for an expression too large to analyze")
+	}
+	if _, err := Distribution("2d"); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	stats, err := Distribution("2d6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(stats.Histogram(12), "\n"), "\n")
+	if len(lines) != 12 {
+		t.Fatalf("expected a header and 11 rows, got %d lines", len(lines))
+	}
+	if lines[6] != " 7   16.67%   58.33%  ############" {
+		t.Errorf("unexpected row for 7: %q", lines[6])
+	}
+	if lines[1] != " 2    2.78%  100.00%  ##" {
+		t.Errorf("unexpected row for 2: %q", lines[1])
+	}
+}
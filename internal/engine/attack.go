@@ -0,0 +1,201 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Armor class systems.
+const (
+	ArmorClassDescending = "descending"
+	ArmorClassAscending  = "ascending"
+)
+
+// AttackRoll is one attack's d20 roll against the number it needed to
+// hit, and the damage it did.
+type AttackRoll struct {
+	Roll   int
+	Needed int
+	Hit    bool
+	Dice   string
+	Damage int
+}
+
+func (r AttackRoll) String() string {
+	if !r.Hit {
+		return fmt.Sprintf("miss (%d vs %d)", r.Roll, r.Needed)
+	}
+	return fmt.Sprintf("hit for %d (%d vs %d)", r.Damage, r.Roll, r.Needed)
+}
+
+// Attack resolves an attacker's attacks on a target: a d20 for each attack
+// in the attacker's routine against the number needed to hit the target's
+// AC, then damage for each hit, which comes off the target's HP. damage
+// overrides the attacker's own damage dice, such as "1d8" or "1d4/1d4".
+// A natural 20 always hits and a natural 1 always misses, every hit does
+// at least 1 damage, and the outcome goes to the combat log.
+//
+// To-hit follows the campaign's armor class system. With descending AC
+// the attacker needs THAC0 minus the target's AC, where THAC0 is 19 minus
+// the attack bonus; with ascending AC it needs the target's AC minus the
+// bonus. A target without an AC is unarmored: AC 9 descending, or 10
+// ascending.
+func (e *Engine) Attack(attackerID, targetID int64, damage string) (*AttackResolved, error) {
+	rules := e.config().Combat
+	system := rules.ArmorClass
+	switch system {
+	case "":
+		system = ArmorClassDescending
+	case ArmorClassDescending, ArmorClassAscending:
+	default:
+		return nil, fmt.Errorf("unknown armor class system %q, expected %s or %s", system, ArmorClassDescending, ArmorClassAscending)
+	}
+
+	attacker, encounter, err := e.combatant(attackerID)
+	if err != nil {
+		return nil, err
+	}
+	target, _, err := e.combatant(targetID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !encounter.IsActive:
+		return nil, fmt.Errorf("encounter %d has ended", encounter.ID)
+	case target.EncounterID != attacker.EncounterID:
+		return nil, fmt.Errorf("%s and %s are not in the same encounter", attacker.Name, target.Name)
+	case attackerID == targetID:
+		return nil, fmt.Errorf("%s cannot attack itself", attacker.Name)
+	case attacker.Dead():
+		return nil, fmt.Errorf("%s is defeated", attacker.Name)
+	case target.Dead():
+		return nil, fmt.Errorf("%s is already defeated", target.Name)
+	}
+
+	if target.HPCurrent == nil && target.HPMax == nil {
+		return nil, fmt.Errorf("%s has no hit points set", target.Name)
+	}
+
+	if damage == "" && attacker.Damage != nil {
+		damage = *attacker.Damage
+	}
+	if damage == "" {
+		damage = rules.DefaultDamage
+	}
+	if damage == "" {
+		damage = "1d6"
+	}
+
+	bonus := 0
+	if attacker.AttackBonus != nil {
+		bonus = *attacker.AttackBonus
+	}
+	ac := 9
+	if system == ArmorClassAscending {
+		ac = 10
+	}
+	if target.ArmorClass != nil {
+		ac = *target.ArmorClass
+	}
+	needed := ac - bonus
+	if system == ArmorClassDescending {
+		needed = 19 - bonus - ac
+	}
+
+	rng := e.rng()
+	resolved := &AttackResolved{
+		SessionID:   encounter.SessionID,
+		EncounterID: encounter.ID,
+		AttackerID:  attacker.ID,
+		Attacker:    attacker.Name,
+		TargetID:    target.ID,
+		Target:      target.Name,
+	}
+	for _, attackDice := range strings.Split(damage, "/") {
+		attackDice = strings.TrimSpace(attackDice)
+		roll, _, err := dice.Roll("1d20", rng)
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll to hit: %w", err)
+		}
+		attack := AttackRoll{Roll: roll, Needed: needed, Dice: attackDice}
+		attack.Hit = roll == 20 || (roll != 1 && roll >= needed)
+		if attack.Hit {
+			amount, _, err := dice.Roll(attackDice, rng)
+			if err != nil {
+				return nil, fmt.Errorf("failed to roll damage %q: %w", attackDice, err)
+			}
+			attack.Damage = max(amount, 1)
+			resolved.Damage += attack.Damage
+		}
+		resolved.Rolls = append(resolved.Rolls, attack)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "attack")
+	if err != nil {
+		return nil, err
+	}
+	// The target's HP is read again under the journal's write lock, so
+	// damage dealt by another process since is not lost.
+	if target, err = j.combatant(targetID); err != nil {
+		return nil, err
+	}
+	if target.Dead() {
+		return nil, fmt.Errorf("%s is already defeated", target.Name)
+	}
+	var current int
+	switch {
+	case target.HPCurrent != nil:
+		current = *target.HPCurrent
+	case target.HPMax != nil:
+		current = *target.HPMax
+	default:
+		return nil, fmt.Errorf("%s has no hit points set", target.Name)
+	}
+	resolved.HPCurrent = max(current-resolved.Damage, 0)
+
+	var update *hpChange
+	if resolved.Damage > 0 {
+		if update, err = e.planHP(j, target, encounter, current, resolved.HPCurrent); err != nil {
+			return nil, err
+		}
+	}
+
+	message := resolved.Summary()
+	if update != nil {
+		if err := update.apply(j, rng); err != nil {
+			return nil, err
+		}
+		message += update.summary()
+	}
+	entry := &model.CombatLogEntry{EncounterID: encounter.ID, Round: encounter.Round, Message: message}
+	if err := model.CreateCombatLogEntry(tx, entry); err != nil {
+		return nil, err
+	}
+	j.created("combat_log", entry.ID)
+
+	recorded, err := j.commit(message)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(recorded)
+	if e.EventBus != nil {
+		e.EventBus.Emit(*resolved)
+	}
+	if update != nil {
+		e.emitHP(update)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Attack(t *testing.T) {
+	engine, session := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(1))
+	damage := "1d6"
+	goblin := &model.Monster{Name: "Goblin", ArmorClass: 6, HitDice: "1-1", Damage: &damage, Movement: 60, Morale: 12, XP: 5}
+	createTestMonster(t, engine, goblin)
+
+	var events []Event
+	for _, eventType := range []string{"AttackResolved", "DamageApplied", "CombatantDefeated"} {
+		engine.EventBus.Subscribe(eventType, func(event Event) {
+			events = append(events, event)
+		})
+	}
+
+	encounter, err := engine.StartEncounter(session.ID, "Ambush")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	goblins, err := engine.AddMonsters(encounter.ID, goblin, 1, 0, "")
+	if err != nil {
+		t.Fatalf("Failed to add goblin: %v", err)
+	}
+	if *goblins[0].ArmorClass != 6 || *goblins[0].AttackBonus != 0 || *goblins[0].Damage != "1d6" {
+		t.Fatalf("Expected the goblin to take its stat block, got %+v", goblins[0])
+	}
+	name, hp, ac, bonus := "Fighter", 30, 4, 1
+	fighter := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, HPCurrent: &hp, HPMax: &hp,
+		Side: model.SideParty, ArmorClass: &ac, AttackBonus: &bonus}
+	if err := engine.AddCombatant(fighter); err != nil {
+		t.Fatalf("Failed to add fighter: %v", err)
+	}
+
+	// Descending AC: THAC0 19 against AC 4 needs 15.
+	resolved, err := engine.Attack(goblins[0].ID, fighter.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to attack: %v", err)
+	}
+	if len(resolved.Rolls) != 1 || resolved.Rolls[0].Needed != 15 || resolved.Rolls[0].Dice != "1d6" {
+		t.Fatalf("Unexpected attack: %+v", resolved)
+	}
+	resolved, err = engine.Attack(goblins[0].ID, fighter.ID, "1d4/1d4/2d6")
+	if err != nil {
+		t.Fatalf("Failed to attack: %v", err)
+	}
+	if len(resolved.Rolls) != 3 {
+		t.Fatalf("Expected three attacks in the routine, got %+v", resolved)
+	}
+	total := 0
+	for _, roll := range resolved.Rolls {
+		if roll.Hit != (roll.Roll == 20 || (roll.Roll != 1 && roll.Roll >= roll.Needed)) || (roll.Hit && roll.Damage < 1) {
+			t.Errorf("Unexpected attack roll: %+v", roll)
+		}
+		total += roll.Damage
+	}
+	if resolved.Damage != total {
+		t.Errorf("Expected %d damage in total, got %d", total, resolved.Damage)
+	}
+
+	stored, err := model.FindCombatant(engine.DB, fighter.ID)
+	if err != nil {
+		t.Fatalf("Failed to find fighter: %v", err)
+	}
+	if *stored.HPCurrent != resolved.HPCurrent {
+		t.Errorf("Expected the fighter at %d HP, got %d", resolved.HPCurrent, *stored.HPCurrent)
+	}
+	log, err := model.ListCombatLog(engine.DB, encounter.ID, 0)
+	if err != nil || len(log) != 2 || log[1].Message != resolved.Summary() {
+		t.Fatalf("Expected both attacks in the combat log, got %+v, %v", log, err)
+	}
+
+	// Undo takes back the damage and the log line.
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if log, _ := model.ListCombatLog(engine.DB, encounter.ID, 0); len(log) != 1 {
+		t.Errorf("Expected undo to remove the log line, got %+v", log)
+	}
+
+	// Ascending AC: +1 against AC 6 needs 5. Keep swinging until the
+	// goblin falls.
+	engine.Config = &config.Config{Combat: config.CombatConfig{ArmorClass: ArmorClassAscending, DefaultDamage: "20"}}
+	events = nil
+	for i := 0; i < 50; i++ {
+		resolved, err = engine.Attack(fighter.ID, goblins[0].ID, "")
+		if err != nil {
+			t.Fatalf("Failed to attack: %v", err)
+		}
+		if resolved.Rolls[0].Needed != 5 {
+			t.Fatalf("Expected the fighter to need 5, got %+v", resolved.Rolls[0])
+		}
+		if resolved.HPCurrent == 0 {
+			break
+		}
+	}
+	if resolved.HPCurrent != 0 {
+		t.Fatal("Expected the goblin to fall")
+	}
+	last := events[len(events)-3:]
+	if _, ok := last[0].(AttackResolved); !ok {
+		t.Errorf("Expected the attack first, got %+v", last)
+	}
+	if _, ok := last[1].(DamageApplied); !ok {
+		t.Errorf("Expected damage after the attack, got %+v", last)
+	}
+	if defeated, ok := last[2].(CombatantDefeated); !ok || defeated.CombatantID != goblins[0].ID {
+		t.Errorf("Expected the goblin to be defeated, got %+v", last)
+	}
+
+	if _, err := engine.Attack(fighter.ID, goblins[0].ID, ""); err == nil {
+		t.Error("Expected an error attacking a defeated goblin")
+	}
+	if _, err := engine.Attack(fighter.ID, fighter.ID, ""); err == nil {
+		t.Error("Expected an error attacking oneself")
+	}
+	engine.Config.Combat.ArmorClass = "sideways"
+	if _, err := engine.Attack(fighter.ID, goblins[0].ID, ""); err == nil {
+		t.Error("Expected an error for an unknown armor class system")
+	}
+}
+
+func TestMonsterAttackBonus(t *testing.T) {
+	tests := map[string]int{
+		"1/2": 0, "1-1": 0, "1": 0, "1+1": 1, "2": 1, "2+1": 2, "4**": 3,
+		"7": 6, "7+1": 7, "9": 7, "9+2": 8, "12": 9, "40": 14, "3d8+3": 2,
+	}
+	for hitDice, expected := range tests {
+		if got := MonsterAttackBonus(hitDice); got != expected {
+			t.Errorf("MonsterAttackBonus(%q) = %d, expected %d", hitDice, got, expected)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Calendar returns the campaign's calendar, or nil when none is configured.
+func (e *Engine) Calendar() (*clock.Calendar, error) {
+	calendar, err := clock.NewCalendar(e.config().Calendar)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar: %w", err)
+	}
+	return calendar, nil
+}
+
+// ScheduleHolidays queues a holiday event at the first turn of each holiday
+// that starts within the given number of days. Holidays already in the
+// queue are skipped, so it is safe to call every time a session is opened.
+func (e *Engine) ScheduleHolidays(sessionID int64, withinDays int64) ([]model.TimeEvent, error) {
+	calendar, err := e.Calendar()
+	if err != nil || calendar == nil {
+		return nil, err
+	}
+
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionID)
+	}
+
+	pending, err := model.ListPendingTimeEvents(e.DB, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	queued := make(map[string]bool)
+	for _, event := range pending {
+		if event.EventType == model.TimeEventHoliday && event.Description != nil {
+			queued[fmt.Sprintf("%d/%s", event.TriggerTurn, *event.Description)] = true
+		}
+	}
+
+	gameClock := e.Clock()
+	today := gameClock.At(session.CurrentTurn, session.CurrentRound).Day
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduled []model.TimeEvent
+	for _, holiday := range calendar.Holidays(today, withinDays) {
+		turn := gameClock.DayStart(holiday.GameDay)
+		if turn <= session.CurrentTurn {
+			continue
+		}
+
+		description := holiday.Holiday.Name
+		if holiday.Holiday.Description != "" {
+			description += ": " + holiday.Holiday.Description
+		}
+		if queued[fmt.Sprintf("%d/%s", turn, description)] {
+			continue
+		}
+
+		event := model.TimeEvent{
+			SessionID:   sessionID,
+			TriggerTurn: turn,
+			EventType:   model.TimeEventHoliday,
+			Description: &description,
+		}
+		if err := model.CreateTimeEvent(tx, &event); err != nil {
+			return nil, fmt.Errorf("failed to schedule holiday: %w", err)
+		}
+		j.created("time_events", event.ID)
+		scheduled = append(scheduled, event)
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Scheduled %d holidays", len(scheduled)))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return scheduled, nil
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_ScheduleHolidays(t *testing.T) {
+	engine, session := newTestEngine(t)
+	cfg := config.DefaultConfig()
+	cfg.Calendar = config.CalendarConfig{
+		Months: []config.MonthConfig{{Name: "Frostfall", Days: 30}},
+		Holidays: []config.HolidayConfig{
+			{Name: "Start", Month: "Frostfall", Day: 1},
+			{Name: "Midwinter", Month: "Frostfall", Day: 3, Description: "feast"},
+		},
+	}
+	engine.Config = &cfg
+
+	scheduled, err := engine.ScheduleHolidays(session.ID, 7)
+	if err != nil {
+		t.Fatalf("Failed to schedule holidays: %v", err)
+	}
+	// Start is today, so only Midwinter is still ahead.
+	if len(scheduled) != 1 {
+		t.Fatalf("Expected 1 holiday event, got %+v", scheduled)
+	}
+	midwinter := scheduled[0]
+	if midwinter.EventType != model.TimeEventHoliday || midwinter.TriggerTurn != 240 ||
+		*midwinter.Description != "Midwinter: feast" {
+		t.Errorf("Unexpected holiday event: %+v", midwinter)
+	}
+
+	again, err := engine.ScheduleHolidays(session.ID, 7)
+	if err != nil {
+		t.Fatalf("Failed to schedule holidays: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("Expected queued holidays to be skipped, got %+v", again)
+	}
+
+	var triggered []EventTriggered
+	engine.EventBus.Subscribe("EventTriggered", func(event Event) {
+		triggered = append(triggered, event.(EventTriggered))
+	})
+	if err := engine.Advance(session.ID, 240); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(triggered) != 1 || triggered[0].EventType != model.TimeEventHoliday {
+		t.Errorf("Expected the holiday to trigger, got %+v", triggered)
+	}
+}
+
+func TestEngine_ScheduleHolidaysWithoutCalendar(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	scheduled, err := engine.ScheduleHolidays(session.ID, 30)
+	if err != nil || scheduled != nil {
+		t.Errorf("Expected nothing to schedule without a calendar, got %+v, %v", scheduled, err)
+	}
+}
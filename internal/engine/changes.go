@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+const (
+	// changeBatchSize caps how many changes one poll reads.
+	changeBatchSize = 500
+	// changeRetention is how long the change log keeps rows. Processes
+	// that fall further behind than this just refresh what they show.
+	changeRetention = 24 * time.Hour
+)
+
+// ChangeWatcher follows the database change log so that a process sees
+// writes made by every other process on the same campaign, such as a turn
+// advanced from another tmux pane. Each change is re-emitted on the
+// engine's EventBus as a DataChanged event.
+//
+// The watcher holds its own connection and polls PRAGMA data_version,
+// which only changes when another connection commits, so an idle poll
+// costs a single pragma.
+type ChangeWatcher struct {
+	engine      *Engine
+	conn        *sqlx.Conn
+	dataVersion int64
+	lastID      int64
+	done        chan bool
+	stopped     chan bool
+}
+
+// NewChangeWatcher starts following changes made from now on.
+func NewChangeWatcher(engine *Engine) (*ChangeWatcher, error) {
+	if _, err := model.PruneChanges(engine.DB, changeRetention); err != nil {
+		log.Printf("Failed to prune change log: %v", err)
+	}
+
+	conn, err := engine.DB.Connx(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open change watcher connection: %w", err)
+	}
+
+	w := &ChangeWatcher{engine: engine, conn: conn, done: make(chan bool)}
+	if w.dataVersion, err = w.readDataVersion(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if w.lastID, err = model.LatestChangeID(engine.DB); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *ChangeWatcher) readDataVersion() (int64, error) {
+	var version int64
+	if err := w.conn.GetContext(context.Background(), &version, "PRAGMA data_version"); err != nil {
+		return 0, fmt.Errorf("failed to read data version: %w", err)
+	}
+	return version, nil
+}
+
+// Poll emits a DataChanged event for every change logged since the last
+// poll. It does nothing when no other connection has committed since.
+func (w *ChangeWatcher) Poll() ([]DataChanged, error) {
+	version, err := w.readDataVersion()
+	if err != nil {
+		return nil, err
+	}
+	if version == w.dataVersion {
+		return nil, nil
+	}
+	w.dataVersion = version
+
+	var changed []DataChanged
+	for {
+		changes, err := model.ListChangesSince(w.engine.DB, w.lastID, changeBatchSize)
+		if err != nil {
+			return changed, err
+		}
+		for _, change := range changes {
+			w.lastID = change.ID
+			event := DataChanged{
+				ChangeID:  change.ID,
+				Table:     change.TableName,
+				RowID:     change.RowID,
+				Action:    change.Action,
+				SessionID: change.SessionID,
+			}
+			changed = append(changed, event)
+			if w.engine.EventBus != nil {
+				w.engine.EventBus.Emit(event)
+			}
+		}
+		if len(changes) < changeBatchSize {
+			return changed, nil
+		}
+	}
+}
+
+// Start polls for changes every interval until Stop is called.
+func (w *ChangeWatcher) Start(interval time.Duration) {
+	w.stopped = make(chan bool)
+	go func() {
+		defer close(w.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := w.Poll(); err != nil {
+					log.Printf("Change watcher error: %v", err)
+				}
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends polling and releases the watcher's connection.
+func (w *ChangeWatcher) Stop() {
+	close(w.done)
+	if w.stopped != nil {
+		<-w.stopped
+	}
+	w.conn.Close()
+}
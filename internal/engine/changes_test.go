@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestChangeWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "campaign.db")
+
+	// Two handles on one database stand in for two spells processes.
+	openEngine := func() *Engine {
+		database, err := db.Open(path)
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		t.Cleanup(func() { database.Close() })
+		return &Engine{DB: database, EventBus: NewEventBus()}
+	}
+	writer := openEngine()
+	reader := openEngine()
+
+	tx, err := writer.DB.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	watcher, err := NewChangeWatcher(reader)
+	if err != nil {
+		t.Fatalf("Failed to create change watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	var received []DataChanged
+	reader.EventBus.Subscribe("DataChanged", func(event Event) {
+		received = append(received, event.(DataChanged))
+	})
+
+	if changed, err := watcher.Poll(); err != nil || len(changed) != 0 {
+		t.Fatalf("Expected no changes before any writes, got %+v, %v", changed, err)
+	}
+
+	if err := writer.Advance(session.ID, 2); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if _, err := writer.StartTimer("pizza", 10*time.Minute, &session.ID); err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+
+	changed, err := watcher.Poll()
+	if err != nil {
+		t.Fatalf("Failed to poll: %v", err)
+	}
+	if len(received) != len(changed) {
+		t.Errorf("Expected every change on the EventBus, got %d of %d", len(received), len(changed))
+	}
+
+	// The advance also writes its journal entry first.
+	if len(changed) != 3 {
+		t.Fatalf("Expected three changes, got %+v", changed)
+	}
+	if changed[0].Table != "journal" || changed[0].Action != model.ChangeInsert {
+		t.Errorf("Expected the journal entry first, got %+v", changed[0])
+	}
+	if changed[1].Table != "sessions" || changed[1].Action != model.ChangeUpdate || changed[1].RowID != session.ID {
+		t.Errorf("Expected the session update second, got %+v", changed[1])
+	}
+	if changed[2].Table != "timers" || changed[2].Action != model.ChangeInsert ||
+		changed[2].SessionID == nil || *changed[2].SessionID != session.ID {
+		t.Errorf("Expected the new timer last, got %+v", changed[2])
+	}
+
+	if changed, err := watcher.Poll(); err != nil || len(changed) != 0 {
+		t.Errorf("Expected changes to be reported once, got %+v, %v", changed, err)
+	}
+}
+
+func TestChangeWatcherStart(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	received := make(chan DataChanged, 8)
+	engine.EventBus.Subscribe("DataChanged", func(event Event) {
+		received <- event.(DataChanged)
+	})
+
+	watcher, err := NewChangeWatcher(engine)
+	if err != nil {
+		t.Fatalf("Failed to create change watcher: %v", err)
+	}
+	watcher.Start(10 * time.Millisecond)
+	defer watcher.Stop()
+
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case change := <-received:
+			if change.Table == "sessions" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for the session change")
+		}
+	}
+}
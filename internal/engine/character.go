@@ -0,0 +1,270 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// AddCharacter puts a new player character on the roster. A character
+// with maximum HP but no current HP starts unhurt.
+func (e *Engine) AddCharacter(character *model.Character) error {
+	if character.Name == "" {
+		return fmt.Errorf("characters need a name")
+	}
+	existing, err := model.GetCharacterByName(e.DB, character.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("character %q already exists", existing.Name)
+	}
+	if character.HPCurrent == nil && character.HPMax != nil {
+		hp := *character.HPMax
+		character.HPCurrent = &hp
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "character")
+	if err != nil {
+		return err
+	}
+	if err := model.CreateCharacter(tx, character); err != nil {
+		return err
+	}
+	j.created("characters", character.ID)
+
+	entry, err := j.commit("Added " + character.Name + " to the roster")
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// UpdateCharacter saves an edited character. Like NPCs, characters belong
+// to the campaign, so the edit can be undone from any session.
+func (e *Engine) UpdateCharacter(character *model.Character) error {
+	return e.saveCharacter(character, "Edited "+character.Name)
+}
+
+// RemoveCharacter takes a character off the roster, with their
+// consumables and inventory. Combatants that stood for the character stay
+// in their encounters.
+func (e *Engine) RemoveCharacter(characterID int64) error {
+	character, err := e.character(characterID)
+	if err != nil {
+		return err
+	}
+	consumables, err := model.ListConsumables(e.DB, characterID)
+	if err != nil {
+		return err
+	}
+	items, err := model.ListItems(e.DB, characterID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "character")
+	if err != nil {
+		return err
+	}
+	if err := j.track("characters", character.ID); err != nil {
+		return err
+	}
+	for _, consumable := range consumables {
+		if err := j.track("consumables", consumable.ID); err != nil {
+			return err
+		}
+		if err := model.DeleteConsumable(tx, consumable.ID); err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		if err := j.track("items", item.ID); err != nil {
+			return err
+		}
+		if err := model.DeleteItem(tx, item.ID); err != nil {
+			return err
+		}
+	}
+	if err := model.DeleteCharacter(tx, character.ID); err != nil {
+		return err
+	}
+
+	entry, err := j.commit("Removed " + character.Name + " from the roster")
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// AddParty adds every active character on the roster to a running
+// encounter on the party side at initiative, with their current HP and
+// AC. Characters already in the encounter are skipped. With side
+// initiative, they act on the party's roll once it has been made.
+func (e *Engine) AddParty(encounterID int64, initiative int) ([]model.InitiativeOrder, error) {
+	encounter, err := e.runningEncounter(encounterID)
+	if err != nil {
+		return nil, err
+	}
+	party, err := model.ListCharacters(e.DB, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(party) == 0 {
+		return nil, fmt.Errorf("no active characters on the roster")
+	}
+
+	combatants, err := e.listCombatants(e.DB, encounter.ID)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[int64]bool)
+	for _, combatant := range combatants {
+		if combatant.CharacterID != nil {
+			present[*combatant.CharacterID] = true
+		}
+	}
+
+	if roll, rolled, err := e.sideInitiative(encounter, model.SideParty); err != nil {
+		return nil, err
+	} else if rolled {
+		initiative = roll
+	}
+
+	var added []model.InitiativeOrder
+	for _, character := range party {
+		if present[character.ID] {
+			continue
+		}
+		added = append(added, characterCombatant(encounter.ID, character, initiative))
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "add_combatant")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(added))
+	for i := range added {
+		if err := model.CreateCombatant(tx, &added[i]); err != nil {
+			return nil, err
+		}
+		j.created("initiative_order", added[i].ID)
+		names[i] = *added[i].CharacterName
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Added %s to the encounter", strings.Join(names, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		for _, combatant := range added {
+			e.EventBus.Emit(CombatantAdded{
+				SessionID:   encounter.SessionID,
+				EncounterID: encounter.ID,
+				CombatantID: combatant.ID,
+				Name:        *combatant.CharacterName,
+			})
+		}
+	}
+	return added, nil
+}
+
+// characterCombatant builds the combatant for a character on the roster.
+func characterCombatant(encounterID int64, character model.Character, initiative int) model.InitiativeOrder {
+	name := character.Name
+	combatant := model.InitiativeOrder{
+		EncounterID:   encounterID,
+		CharacterName: &name,
+		Initiative:    initiative,
+		HPCurrent:     character.HPCurrent,
+		HPMax:         character.HPMax,
+		IsActive:      true,
+		Side:          model.SideParty,
+		CharacterID:   &character.ID,
+		ArmorClass:    character.ArmorClass,
+	}
+	if combatant.HPCurrent == nil {
+		combatant.HPCurrent = character.HPMax
+	}
+	return combatant
+}
+
+// character gets a character on the roster, which must exist.
+func (e *Engine) character(characterID int64) (*model.Character, error) {
+	character, err := model.GetCharacter(e.DB, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		return nil, fmt.Errorf("character %d not found", characterID)
+	}
+	return character, nil
+}
+
+func (e *Engine) saveCharacter(character *model.Character, description string) error {
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "character")
+	if err != nil {
+		return err
+	}
+	if err := j.track("characters", character.ID); err != nil {
+		return err
+	}
+	if err := model.UpdateCharacter(tx, character); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_AddParty(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	hp, ac := 12, 4
+	theron := &model.Character{Name: "Theron", HPMax: &hp, ArmorClass: &ac, IsActive: true}
+	gareth := &model.Character{Name: "Gareth", IsActive: true}
+	absent := &model.Character{Name: "Mira", IsActive: false}
+	for _, character := range []*model.Character{theron, gareth, absent} {
+		if err := engine.AddCharacter(character); err != nil {
+			t.Fatalf("Failed to add character: %v", err)
+		}
+	}
+	if theron.HPCurrent == nil || *theron.HPCurrent != 12 {
+		t.Errorf("Expected Theron to start unhurt, got %v", theron.HPCurrent)
+	}
+	if err := engine.AddCharacter(&model.Character{Name: "theron"}); err == nil {
+		t.Error("Expected an error adding a character twice")
+	}
+
+	encounter, err := engine.StartEncounter(session.ID, "Ambush")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	added, err := engine.AddParty(encounter.ID, 3)
+	if err != nil {
+		t.Fatalf("Failed to add party: %v", err)
+	}
+	if len(added) != 2 || *added[0].CharacterName != "Gareth" || *added[1].CharacterName != "Theron" {
+		t.Fatalf("Expected the active characters, got %+v", added)
+	}
+	if added[1].Side != model.SideParty || added[1].Initiative != 3 || *added[1].ArmorClass != 4 || *added[1].HPCurrent != 12 {
+		t.Errorf("Unexpected combatant for Theron: %+v", added[1])
+	}
+	if again, err := engine.AddParty(encounter.ID, 3); err != nil || len(again) != 0 {
+		t.Errorf("Expected the party to be added only once, got %+v, %v", again, err)
+	}
+
+	// Damage in combat carries over to the roster, and so does its undo.
+	if err := engine.Damage(added[1].ID, 5); err != nil {
+		t.Fatalf("Failed to damage Theron: %v", err)
+	}
+	character, err := model.GetCharacter(engine.DB, theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to get character: %v", err)
+	}
+	if *character.HPCurrent != 7 {
+		t.Errorf("Expected Theron at 7 HP on the roster, got %d", *character.HPCurrent)
+	}
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	character, err = model.GetCharacter(engine.DB, theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to get character: %v", err)
+	}
+	if *character.HPCurrent != 12 {
+		t.Errorf("Expected Theron back at 12 HP on the roster, got %d", *character.HPCurrent)
+	}
+
+	summary, err := engine.XP(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to total XP: %v", err)
+	}
+	if summary.PartySize != 2 {
+		t.Errorf("Expected shares for the 2 active characters, got %d", summary.PartySize)
+	}
+
+	if err := engine.RemoveCharacter(gareth.ID); err != nil {
+		t.Fatalf("Failed to remove character: %v", err)
+	}
+	if found, err := model.GetCharacter(engine.DB, gareth.ID); err != nil || found != nil {
+		t.Errorf("Expected Gareth off the roster, got %+v, %v", found, err)
+	}
+}
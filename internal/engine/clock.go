@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Clock returns the game clock for the campaign's time units.
+func (e *Engine) Clock() clock.Clock {
+	return clock.New(e.config().Clock)
+}
+
+// Now returns the in-world time of a session.
+func (e *Engine) Now(sessionID int64) (clock.Time, error) {
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return clock.Time{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return clock.Time{}, fmt.Errorf("session %d not found", sessionID)
+	}
+	return e.Clock().At(session.CurrentTurn, session.CurrentRound), nil
+}
+
+// AdvanceRounds moves the session forward by combat rounds, carrying whole
+// turns into the turn count.
+func (e *Engine) AdvanceRounds(sessionID int64, rounds int64) error {
+	return e.advance(sessionID, 0, rounds)
+}
+
+// AdvanceDuration moves the session forward by an in-world duration such
+// as three hours or one watch. Time shorter than a round is dropped.
+func (e *Engine) AdvanceDuration(sessionID int64, d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("cannot advance by a negative duration %v", d)
+	}
+	turns, rounds := e.Clock().Split(d)
+	return e.advance(sessionID, turns, rounds)
+}
+
+// Step advances the session by one unit of its time mode: a turn while
+// exploring, a round in combat.
+func (e *Engine) Step(sessionID int64) error {
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return fmt.Errorf("session %d not found", sessionID)
+	}
+
+	if session.TimeMode == clock.ModeCombat {
+		return e.AdvanceRounds(sessionID, 1)
+	}
+	return e.Advance(sessionID, 1)
+}
+
+// SetTimeMode switches a session between exploration and combat time.
+func (e *Engine) SetTimeMode(sessionID int64, mode string) error {
+	if mode != clock.ModeExploration && mode != clock.ModeCombat {
+		return fmt.Errorf("unknown time mode %q, expected %s or %s", mode, clock.ModeExploration, clock.ModeCombat)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "time_mode")
+	if err != nil {
+		return err
+	}
+	if err := j.track("sessions", sessionID); err != nil {
+		return err
+	}
+
+	session := &model.Session{ID: sessionID}
+	if err := session.SetTimeMode(tx, mode); err != nil {
+		return err
+	}
+	entry, err := j.commit(fmt.Sprintf("Switched to %s time", mode))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(TimeModeChanged{SessionID: sessionID, Mode: mode})
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_AdvanceRounds(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var turnEvents []TurnAdvanced
+	var roundEvents []RoundAdvanced
+	engine.EventBus.Subscribe("TurnAdvanced", func(event Event) {
+		turnEvents = append(turnEvents, event.(TurnAdvanced))
+	})
+	engine.EventBus.Subscribe("RoundAdvanced", func(event Event) {
+		roundEvents = append(roundEvents, event.(RoundAdvanced))
+	})
+
+	if err := engine.AdvanceRounds(session.ID, 59); err != nil {
+		t.Fatalf("Failed to advance rounds: %v", err)
+	}
+	if len(turnEvents) != 0 || len(roundEvents) != 1 {
+		t.Fatalf("Expected one round event and no turn events, got %+v %+v", roundEvents, turnEvents)
+	}
+
+	if err := engine.AdvanceRounds(session.ID, 3); err != nil {
+		t.Fatalf("Failed to advance rounds: %v", err)
+	}
+	if len(turnEvents) != 1 || turnEvents[0].OldTurn != 0 || turnEvents[0].NewTurn != 1 || turnEvents[0].Delta != 1 {
+		t.Fatalf("Expected rounds to carry into turn 1, got %+v", turnEvents)
+	}
+	last := roundEvents[len(roundEvents)-1]
+	if last.OldRound != 59 || last.NewTurn != 1 || last.NewRound != 2 {
+		t.Errorf("Unexpected round event: %+v", last)
+	}
+
+	updated, err := model.GetSession(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if updated.CurrentTurn != 1 || updated.CurrentRound != 2 {
+		t.Errorf("Expected turn 1 round 2, got turn %d round %d", updated.CurrentTurn, updated.CurrentRound)
+	}
+
+	if err := engine.AdvanceRounds(session.ID, -1); err == nil {
+		t.Error("Expected an error for negative rounds")
+	}
+}
+
+func TestEngine_AdvanceDuration(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	reminder, err := engine.Schedule(session.ID, model.TimeEventReminder, "Guards change", 10)
+	if err != nil {
+		t.Fatalf("Failed to schedule reminder: %v", err)
+	}
+
+	var triggered []EventTriggered
+	engine.EventBus.Subscribe("EventTriggered", func(event Event) {
+		triggered = append(triggered, event.(EventTriggered))
+	})
+
+	duration, err := engine.Clock().ParseDuration("1 watch")
+	if err != nil {
+		t.Fatalf("Failed to parse duration: %v", err)
+	}
+	if err := engine.AdvanceDuration(session.ID, duration); err != nil {
+		t.Fatalf("Failed to advance duration: %v", err)
+	}
+	if err := engine.AdvanceDuration(session.ID, 3*time.Hour+45*time.Second); err != nil {
+		t.Fatalf("Failed to advance duration: %v", err)
+	}
+
+	updated, err := model.GetSession(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if updated.CurrentTurn != 42 || updated.CurrentRound != 4 {
+		t.Errorf("Expected turn 42 round 4, got turn %d round %d", updated.CurrentTurn, updated.CurrentRound)
+	}
+	if len(triggered) != 1 || triggered[0].EventID != reminder.ID {
+		t.Errorf("Expected the reminder to trigger, got %+v", triggered)
+	}
+
+	now, err := engine.Now(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get time: %v", err)
+	}
+	if now.String() != "Day 1, 15:00:40" || now.TimeOfDay() != "afternoon" {
+		t.Errorf("Expected Day 1, 15:00:40 in the afternoon, got %s in the %s", now, now.TimeOfDay())
+	}
+}
+
+func TestEngine_TimeMode(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var modes []string
+	engine.EventBus.Subscribe("TimeModeChanged", func(event Event) {
+		modes = append(modes, event.(TimeModeChanged).Mode)
+	})
+
+	if err := engine.Step(session.ID); err != nil {
+		t.Fatalf("Failed to step: %v", err)
+	}
+	if err := engine.SetTimeMode(session.ID, clock.ModeCombat); err != nil {
+		t.Fatalf("Failed to set time mode: %v", err)
+	}
+	if err := engine.Step(session.ID); err != nil {
+		t.Fatalf("Failed to step: %v", err)
+	}
+
+	updated, err := model.GetSession(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if updated.TimeMode != clock.ModeCombat || updated.CurrentTurn != 1 || updated.CurrentRound != 1 {
+		t.Errorf("Expected combat at turn 1 round 1, got %+v", updated)
+	}
+	if len(modes) != 1 || modes[0] != clock.ModeCombat {
+		t.Errorf("Expected a combat mode event, got %v", modes)
+	}
+
+	if err := engine.SetTimeMode(session.ID, "downtime"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+	if err := engine.SetTimeMode(999, clock.ModeCombat); err == nil {
+		t.Error("Expected an error for a missing session")
+	}
+}
@@ -0,0 +1,832 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// StartEncounter begins an encounter in a session and switches the session
+// to combat time. A session runs one encounter at a time.
+func (e *Engine) StartEncounter(sessionID int64, name string) (*model.Encounter, error) {
+	active, err := model.GetActiveEncounter(e.DB, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, fmt.Errorf("encounter %d is already running", active.ID)
+	}
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionID)
+	}
+
+	encounter := &model.Encounter{SessionID: sessionID, IsActive: true}
+	if name != "" {
+		encounter.Name = &name
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "encounter")
+	if err != nil {
+		return nil, err
+	}
+	if err := model.CreateEncounter(tx, encounter); err != nil {
+		return nil, fmt.Errorf("failed to create encounter: %w", err)
+	}
+	j.created("encounters", encounter.ID)
+
+	switched, err := switchTimeMode(j, session, clock.ModeCombat)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := j.commit("Started " + encounterName(encounter))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(EncounterStarted{SessionID: sessionID, EncounterID: encounter.ID, Name: name})
+		if switched {
+			e.EventBus.Emit(TimeModeChanged{SessionID: sessionID, Mode: clock.ModeCombat})
+		}
+	}
+	return encounter, nil
+}
+
+// EndEncounter finishes an encounter and returns its session to
+// exploration time.
+func (e *Engine) EndEncounter(encounterID int64) error {
+	encounter, err := e.runningEncounter(encounterID)
+	if err != nil {
+		return err
+	}
+	session, err := model.GetSession(e.DB, encounter.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "encounter")
+	if err != nil {
+		return err
+	}
+	if err := j.track("encounters", encounterID); err != nil {
+		return err
+	}
+	if err := model.EndEncounter(tx, encounterID); err != nil {
+		return err
+	}
+
+	switched := false
+	if session != nil {
+		switched, err = switchTimeMode(j, session, clock.ModeExploration)
+		if err != nil {
+			return err
+		}
+	}
+
+	entry, err := j.commit("Ended " + encounterName(encounter))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		name := ""
+		if encounter.Name != nil {
+			name = *encounter.Name
+		}
+		e.EventBus.Emit(EncounterEnded{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounterID,
+			Name:        name,
+			Rounds:      encounter.Round,
+		})
+		if switched {
+			e.EventBus.Emit(TimeModeChanged{SessionID: encounter.SessionID, Mode: clock.ModeExploration})
+		}
+	}
+	return nil
+}
+
+// AddCombatant adds a combatant to a running encounter. With side
+// initiative, a combatant joining a side that has already rolled this
+// round takes the side's roll.
+func (e *Engine) AddCombatant(combatant *model.InitiativeOrder) error {
+	encounter, err := e.runningEncounter(combatant.EncounterID)
+	if err != nil {
+		return err
+	}
+
+	label := "combatant"
+	switch {
+	case combatant.NPCID != nil:
+		npc, err := model.GetNPC(e.DB, *combatant.NPCID)
+		if err != nil {
+			return err
+		}
+		if npc == nil {
+			return fmt.Errorf("npc %d not found", *combatant.NPCID)
+		}
+		label = npc.Name
+	case combatant.CharacterName != nil:
+		label = *combatant.CharacterName
+	}
+
+	combatant.IsActive = true
+	if combatant.Side == "" {
+		combatant.Side = model.SideMonsters
+	}
+	if roll, rolled, err := e.sideInitiative(encounter, combatant.Side); err != nil {
+		return err
+	} else if rolled {
+		combatant.Initiative = roll
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "add_combatant")
+	if err != nil {
+		return err
+	}
+
+	if err := model.CreateCombatant(tx, combatant); err != nil {
+		return err
+	}
+	j.created("initiative_order", combatant.ID)
+
+	entry, err := j.commit(fmt.Sprintf("Added %s to the encounter", label))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(CombatantAdded{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounter.ID,
+			CombatantID: combatant.ID,
+			Name:        label,
+		})
+	}
+	return nil
+}
+
+// sideInitiative returns the roll a side has made this round, when side
+// initiative is in use and the side has already rolled.
+func (e *Engine) sideInitiative(encounter *model.Encounter, side string) (int, bool, error) {
+	if e.config().Combat.Initiative != InitiativeSide || encounter.Round == 0 {
+		return 0, false, nil
+	}
+	combatants, err := e.listCombatants(e.DB, encounter.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, member := range combatants {
+		if member.Side == side {
+			return member.Initiative, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// RemoveCombatant takes a combatant out of its encounter altogether. When
+// it is the combatant's turn, the next turn goes to whoever followed it.
+func (e *Engine) RemoveCombatant(combatantID int64) error {
+	combatant, encounter, err := e.combatant(combatantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "remove_combatant")
+	if err != nil {
+		return err
+	}
+	if err := j.track("initiative_order", combatantID); err != nil {
+		return err
+	}
+
+	if encounter.CurrentCombatantID != nil && *encounter.CurrentCombatantID == combatantID {
+		combatants, err := e.listCombatants(e.DB, encounter.ID)
+		if err != nil {
+			return err
+		}
+		var previous *int64
+		for i := range combatants {
+			if combatants[i].ID == combatantID {
+				break
+			}
+			previous = &combatants[i].ID
+		}
+		if err := j.track("encounters", encounter.ID); err != nil {
+			return err
+		}
+		if err := model.SetEncounterTurn(tx, encounter.ID, encounter.Round, previous); err != nil {
+			return err
+		}
+	}
+
+	if err := model.RemoveCombatant(tx, combatantID); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Removed %s from the encounter", combatant.Name))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(CombatantRemoved{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounter.ID,
+			CombatantID: combatantID,
+			Name:        combatant.Name,
+		})
+	}
+	return nil
+}
+
+// SetCombatantSide moves a combatant to another side, such as a monster
+// that is charmed into joining the party.
+func (e *Engine) SetCombatantSide(combatantID int64, side string) error {
+	side = strings.TrimSpace(side)
+	if side == "" {
+		return fmt.Errorf("side cannot be empty")
+	}
+	combatant, encounter, err := e.combatant(combatantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "combatant_side")
+	if err != nil {
+		return err
+	}
+	if err := j.track("initiative_order", combatantID); err != nil {
+		return err
+	}
+	if err := model.SetCombatantSide(tx, combatantID, side); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Moved %s to the %s side", combatant.Name, side))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil && entry != nil {
+		e.EventBus.Emit(CombatantStatusChanged{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounter.ID,
+			CombatantID: combatantID,
+			Name:        combatant.Name,
+			Active:      combatant.IsActive,
+		})
+	}
+	return nil
+}
+
+// SetCombatantActive takes a combatant out of the turn order without
+// removing it, e.g. when it flees or is held, or puts it back.
+func (e *Engine) SetCombatantActive(combatantID int64, active bool) error {
+	combatant, encounter, err := e.combatant(combatantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "combatant_status")
+	if err != nil {
+		return err
+	}
+	if err := j.track("initiative_order", combatantID); err != nil {
+		return err
+	}
+	if err := model.SetCombatantActive(tx, combatantID, active); err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Took %s out of the turn order", combatant.Name)
+	if active {
+		description = fmt.Sprintf("Put %s back in the turn order", combatant.Name)
+	}
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil && entry != nil {
+		e.EventBus.Emit(CombatantStatusChanged{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounter.ID,
+			CombatantID: combatantID,
+			Name:        combatant.Name,
+			Active:      active,
+		})
+	}
+	return nil
+}
+
+// Damage takes hit points from a combatant, stopping at 0. A combatant at
+// 0 HP is defeated and no longer takes turns.
+func (e *Engine) Damage(combatantID int64, amount int) error {
+	if amount < 1 {
+		return fmt.Errorf("damage must be at least 1, got %d", amount)
+	}
+	return e.changeHP(combatantID, "damage", func(current, _ int) int {
+		return current - amount
+	})
+}
+
+// Heal restores hit points to a combatant, up to its maximum when known.
+func (e *Engine) Heal(combatantID int64, amount int) error {
+	if amount < 1 {
+		return fmt.Errorf("healing must be at least 1, got %d", amount)
+	}
+	return e.changeHP(combatantID, "heal", func(current, _ int) int {
+		return current + amount
+	})
+}
+
+// SetCombatantHP sets a combatant's current hit points.
+func (e *Engine) SetCombatantHP(combatantID int64, hp int) error {
+	return e.changeHP(combatantID, "hp", func(_, _ int) int {
+		return hp
+	})
+}
+
+// changeHP applies an HP change to a combatant, keeping the result between
+// 0 and the combatant's maximum, and emits the damage, healing and defeat
+// events it causes. A combatant with no current HP starts from its
+// maximum. When a combatant falls, the rest of its side roll morale if
+// it is their first death or leaves them at half strength.
+func (e *Engine) changeHP(combatantID int64, action string, change func(current, max int) int) error {
+	_, encounter, err := e.combatant(combatantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, action)
+	if err != nil {
+		return err
+	}
+	// The HP is read once the journal holds the write lock, so a change
+	// another process made in the meantime is built on rather than lost.
+	combatant, err := j.combatant(combatantID)
+	if err != nil {
+		return err
+	}
+
+	var current, hpMax int
+	switch {
+	case combatant.HPCurrent != nil:
+		current = *combatant.HPCurrent
+	case combatant.HPMax != nil:
+		current = *combatant.HPMax
+	case action != "hp":
+		return fmt.Errorf("%s has no hit points set", combatant.Name)
+	}
+	if combatant.HPMax != nil {
+		hpMax = *combatant.HPMax
+	}
+
+	hp := max(change(current, hpMax), 0)
+	if combatant.HPMax != nil && action == "heal" {
+		hp = min(hp, hpMax)
+	}
+	update, err := e.planHP(j, combatant, encounter, current, hp)
+	if err != nil {
+		return err
+	}
+	if err := update.apply(j, e.rng()); err != nil {
+		return err
+	}
+
+	var description string
+	switch {
+	case hp < current:
+		description = fmt.Sprintf("%s took %d damage (%d → %d HP)", combatant.Name, current-hp, current, hp)
+	case hp > current:
+		description = fmt.Sprintf("%s healed %d (%d → %d HP)", combatant.Name, hp-current, current, hp)
+	default:
+		description = fmt.Sprintf("Set %s to %d HP", combatant.Name, hp)
+	}
+	entry, err := j.commit(description + update.summary())
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	e.emitHP(update)
+	return nil
+}
+
+// hpChange moves a combatant from one HP total to another, along with the
+// XP and morale check its fall calls for.
+type hpChange struct {
+	combatant *model.Combatant
+	encounter *model.Encounter
+	from, to  int
+	xp        int
+	trigger   string
+	checkers  []moraleChecker
+	morale    []MoraleRoll
+}
+
+// planHP works out what an HP change calls for, reading the encounter
+// within the change's journaled action. A combatant falling earns the
+// party its XP and can shake the nerve of its side.
+func (e *Engine) planHP(j *journal, combatant *model.Combatant, encounter *model.Encounter, from, to int) (*hpChange, error) {
+	update := &hpChange{combatant: combatant, encounter: encounter, from: from, to: to}
+	if to > 0 || combatant.Dead() {
+		return update, nil
+	}
+
+	var err error
+	if update.xp, err = e.defeatXP(combatant); err != nil {
+		return nil, err
+	}
+	combatants, err := e.listCombatants(j.tx, encounter.ID)
+	if err != nil {
+		return nil, err
+	}
+	if update.trigger = moraleTrigger(combatants, combatant); update.trigger != "" {
+		if update.checkers, err = e.moraleCheckers(combatants, combatant.Side, combatant.ID); err != nil {
+			return nil, err
+		}
+	}
+	return update, nil
+}
+
+// apply saves the new HP, on the character's record too for a PC,
+// credits any XP and rolls any morale check.
+func (c *hpChange) apply(j *journal, rng *rand.Rand) error {
+	if err := j.track("initiative_order", c.combatant.ID); err != nil {
+		return err
+	}
+	if err := model.SetCombatantHP(j.tx, c.combatant.ID, c.to); err != nil {
+		return err
+	}
+	if c.combatant.CharacterID != nil {
+		if err := c.writeBack(j); err != nil {
+			return err
+		}
+	}
+	if c.xp > 0 {
+		entry := &model.XPEntry{
+			SessionID:   c.encounter.SessionID,
+			Source:      model.XPFromMonster,
+			Description: c.combatant.Name,
+			XP:          c.xp,
+			CombatantID: &c.combatant.ID,
+		}
+		if err := model.CreateXPEntry(j.tx, entry); err != nil {
+			return err
+		}
+		j.created("xp_ledger", entry.ID)
+	}
+	if len(c.checkers) > 0 {
+		var err error
+		if c.morale, err = rollMorale(j, c.checkers, rng); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBack copies the new HP to the roster character the combatant
+// stands for, if it is still on the roster.
+func (c *hpChange) writeBack(j *journal) error {
+	id := *c.combatant.CharacterID
+	if err := j.track("characters", id); err != nil {
+		return err
+	}
+	return model.SetCharacterHP(j.tx, id, c.to)
+}
+
+// summary describes the XP and morale check for the journal, if there
+// were any.
+func (c *hpChange) summary() string {
+	var summary string
+	if c.xp > 0 {
+		summary += fmt.Sprintf("; %d XP", c.xp)
+	}
+	if len(c.morale) > 0 {
+		summary += fmt.Sprintf("; morale (%s): %s", c.trigger, describeMorale(c.morale))
+	}
+	return summary
+}
+
+// emitHP emits the damage, healing, defeat, XP and morale events of an HP
+// change.
+func (e *Engine) emitHP(c *hpChange) {
+	if e.EventBus == nil {
+		return
+	}
+	switch {
+	case c.to < c.from:
+		e.EventBus.Emit(DamageApplied{
+			SessionID:   c.encounter.SessionID,
+			EncounterID: c.encounter.ID,
+			CombatantID: c.combatant.ID,
+			Name:        c.combatant.Name,
+			Amount:      c.from - c.to,
+			HPCurrent:   c.to,
+		})
+	case c.to > c.from:
+		e.EventBus.Emit(CombatantHealed{
+			SessionID:   c.encounter.SessionID,
+			EncounterID: c.encounter.ID,
+			CombatantID: c.combatant.ID,
+			Name:        c.combatant.Name,
+			Amount:      c.to - c.from,
+			HPCurrent:   c.to,
+		})
+	}
+	if c.to == 0 && !c.combatant.Dead() {
+		e.EventBus.Emit(CombatantDefeated{
+			SessionID:   c.encounter.SessionID,
+			EncounterID: c.encounter.ID,
+			CombatantID: c.combatant.ID,
+			Name:        c.combatant.Name,
+		})
+	}
+	if c.xp > 0 {
+		e.EventBus.Emit(XPEarned{
+			SessionID:   c.encounter.SessionID,
+			Source:      model.XPFromMonster,
+			Description: c.combatant.Name,
+			XP:          c.xp,
+		})
+	}
+	if len(c.morale) > 0 {
+		e.EventBus.Emit(MoraleChecked{
+			SessionID:   c.encounter.SessionID,
+			EncounterID: c.encounter.ID,
+			Side:        c.combatant.Side,
+			Trigger:     c.trigger,
+			Rolls:       c.morale,
+		})
+	}
+}
+
+// NextTurn passes the turn to the next combatant in initiative order,
+// skipping those out of the turn order or at 0 HP. The first call starts
+// round 1; each time the order wraps round, the encounter moves to the
+// next round and the session's clock advances by a round. With side
+// initiative, every side rolls again at the start of each round.
+func (e *Engine) NextTurn(encounterID int64) (*model.Combatant, error) {
+	rules := e.config().Combat
+	if err := validateInitiative(rules); err != nil {
+		return nil, err
+	}
+
+	encounter, err := e.runningEncounter(encounterID)
+	if err != nil {
+		return nil, err
+	}
+	combatants, err := e.listCombatants(e.DB, encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if nextActor(combatants, -1) < 0 {
+		return nil, fmt.Errorf("no combatant in encounter %d can act", encounterID)
+	}
+
+	start := -1
+	if encounter.CurrentCombatantID != nil {
+		for i, combatant := range combatants {
+			if combatant.ID == *encounter.CurrentCombatantID {
+				start = i
+				break
+			}
+		}
+	}
+
+	round := encounter.Round
+	next := nextActor(combatants, start)
+	newRound := round == 0 || next < 0
+	if newRound {
+		round++
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "next_turn")
+	if err != nil {
+		return nil, err
+	}
+
+	var rolled *SideInitiativeRolled
+	if newRound {
+		if rules.Initiative == InitiativeSide {
+			rolled, err = e.rollSideInitiative(j, rules, combatants)
+			if err != nil {
+				return nil, err
+			}
+			rolled.SessionID = encounter.SessionID
+			rolled.EncounterID = encounterID
+			rolled.Round = round
+		}
+		next = nextActor(combatants, -1)
+	}
+	actor := combatants[next]
+
+	// A new round after the first moves the clock on a round within the
+	// same action, so a single undo takes back both.
+	var advanced *clockAdvance
+	if newRound && round > 1 {
+		if advanced, err = e.advanceClock(j, encounter.SessionID, 0, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := j.track("encounters", encounterID); err != nil {
+		return nil, err
+	}
+	if err := model.SetEncounterTurn(tx, encounterID, round, &actor.ID); err != nil {
+		return nil, err
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Round %d: %s's turn", round, actor.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		if rolled != nil {
+			e.EventBus.Emit(*rolled)
+		}
+		e.EventBus.Emit(CombatTurnStarted{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounterID,
+			Round:       round,
+			CombatantID: actor.ID,
+			Name:        actor.Name,
+			NewRound:    newRound,
+		})
+	}
+
+	if advanced != nil {
+		e.emitAdvanced(advanced)
+	}
+	return &actor, nil
+}
+
+// nextActor returns the index of the first combatant after start that can
+// act, without wrapping round, or -1 when there is none.
+func nextActor(combatants []model.Combatant, start int) int {
+	for i := start + 1; i < len(combatants); i++ {
+		if combatants[i].CanAct() {
+			return i
+		}
+	}
+	return -1
+}
+
+// runningEncounter loads an encounter that has not ended.
+func (e *Engine) runningEncounter(encounterID int64) (*model.Encounter, error) {
+	encounter, err := model.GetEncounter(e.DB, encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if encounter == nil {
+		return nil, fmt.Errorf("encounter %d not found", encounterID)
+	}
+	if !encounter.IsActive {
+		return nil, fmt.Errorf("encounter %d has ended", encounterID)
+	}
+	return encounter, nil
+}
+
+// combatant loads a combatant and its encounter.
+// combatant re-reads a combatant within a journaled action.
+func (j *journal) combatant(combatantID int64) (*model.Combatant, error) {
+	combatant, err := model.FindCombatant(j.tx, combatantID)
+	if err != nil {
+		return nil, err
+	}
+	if combatant == nil {
+		return nil, fmt.Errorf("combatant %d not found", combatantID)
+	}
+	return combatant, nil
+}
+
+func (e *Engine) combatant(combatantID int64) (*model.Combatant, *model.Encounter, error) {
+	combatant, err := model.FindCombatant(e.DB, combatantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if combatant == nil {
+		return nil, nil, fmt.Errorf("combatant %d not found", combatantID)
+	}
+	encounter, err := model.GetEncounter(e.DB, combatant.EncounterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if encounter == nil {
+		return nil, nil, fmt.Errorf("encounter %d not found", combatant.EncounterID)
+	}
+	return combatant, encounter, nil
+}
+
+// switchTimeMode moves a session to a time mode within a journaled action,
+// reporting whether it was in another mode.
+func switchTimeMode(j *journal, session *model.Session, mode string) (bool, error) {
+	if session.TimeMode == mode {
+		return false, nil
+	}
+	if err := j.track("sessions", session.ID); err != nil {
+		return false, err
+	}
+	if err := session.SetTimeMode(j.tx, mode); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// encounterName names an encounter for the journal.
+func encounterName(encounter *model.Encounter) string {
+	if encounter.Name != nil && *encounter.Name != "" {
+		return "encounter " + *encounter.Name
+	}
+	return fmt.Sprintf("encounter %d", encounter.ID)
+}
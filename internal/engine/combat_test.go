@@ -0,0 +1,284 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Combat(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var events []Event
+	for _, eventType := range []string{"EncounterStarted", "EncounterEnded", "CombatantAdded", "CombatantRemoved",
+		"DamageApplied", "CombatantHealed", "CombatantDefeated", "CombatTurnStarted", "TimeModeChanged"} {
+		engine.EventBus.Subscribe(eventType, func(event Event) {
+			events = append(events, event)
+		})
+	}
+
+	encounter, err := engine.StartEncounter(session.ID, "Goblin ambush")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	if _, err := engine.StartEncounter(session.ID, "Second"); err == nil {
+		t.Error("Expected an error starting a second encounter")
+	}
+
+	add := func(name string, initiative, hp int) int64 {
+		t.Helper()
+		combatant := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, Initiative: initiative, HPCurrent: &hp, HPMax: &hp}
+		if err := engine.AddCombatant(combatant); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		return combatant.ID
+	}
+	fighter := add("Fighter", 15, 8)
+	goblin := add("Goblin", 12, 4)
+	wolf := add("Wolf", 8, 6)
+
+	next := func() string {
+		t.Helper()
+		combatant, err := engine.NextTurn(encounter.ID)
+		if err != nil {
+			t.Fatalf("Failed to pass the turn: %v", err)
+		}
+		return combatant.Name
+	}
+
+	if name := next(); name != "Fighter" {
+		t.Errorf("Expected the fighter to act first, got %s", name)
+	}
+	if err := engine.Damage(goblin, 10); err != nil {
+		t.Fatalf("Failed to damage goblin: %v", err)
+	}
+	if err := engine.SetCombatantActive(wolf, false); err != nil {
+		t.Fatalf("Failed to deactivate wolf: %v", err)
+	}
+	// The goblin is dead and the wolf out of the order, so the fighter goes
+	// again in round 2.
+	if name := next(); name != "Fighter" {
+		t.Errorf("Expected the turn to wrap to the fighter, got %s", name)
+	}
+
+	current, err := model.GetEncounter(engine.DB, encounter.ID)
+	if err != nil {
+		t.Fatalf("Failed to get encounter: %v", err)
+	}
+	if current.Round != 2 || current.CurrentCombatantID == nil || *current.CurrentCombatantID != fighter {
+		t.Errorf("Expected round 2 with the fighter acting, got %+v", current)
+	}
+	updated, err := model.GetSession(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if updated.CurrentRound != 1 || updated.TimeMode != clock.ModeCombat {
+		t.Errorf("Expected one combat round on the clock, got round %d in %s time", updated.CurrentRound, updated.TimeMode)
+	}
+
+	if err := engine.SetCombatantActive(wolf, true); err != nil {
+		t.Fatalf("Failed to reactivate wolf: %v", err)
+	}
+	if err := engine.Heal(goblin, 10); err != nil {
+		t.Fatalf("Failed to heal goblin: %v", err)
+	}
+	if healed, _ := model.GetCombatant(engine.DB, goblin); *healed.HPCurrent != 4 {
+		t.Errorf("Expected healing to stop at max HP, got %d", *healed.HPCurrent)
+	}
+	if err := engine.RemoveCombatant(fighter); err != nil {
+		t.Fatalf("Failed to remove fighter: %v", err)
+	}
+	if name := next(); name != "Goblin" {
+		t.Errorf("Expected the goblin to follow the removed fighter, got %s", name)
+	}
+	if name := next(); name != "Wolf" {
+		t.Errorf("Expected the wolf after the goblin, got %s", name)
+	}
+
+	if err := engine.EndEncounter(encounter.ID); err != nil {
+		t.Fatalf("Failed to end encounter: %v", err)
+	}
+	if err := engine.Damage(wolf, 1); err != nil {
+		t.Fatalf("Failed to damage wolf after the encounter: %v", err)
+	}
+	if _, err := engine.NextTurn(encounter.ID); err == nil {
+		t.Error("Expected an error passing the turn in an ended encounter")
+	}
+	if updated, _ := model.GetSession(engine.DB, session.ID); updated.TimeMode != clock.ModeExploration {
+		t.Errorf("Expected exploration time after the encounter, got %s", updated.TimeMode)
+	}
+
+	var defeated []CombatantDefeated
+	var damage []DamageApplied
+	for _, event := range events {
+		switch event := event.(type) {
+		case CombatantDefeated:
+			defeated = append(defeated, event)
+		case DamageApplied:
+			damage = append(damage, event)
+		}
+	}
+	if len(defeated) != 1 || defeated[0].Name != "Goblin" {
+		t.Errorf("Expected the goblin to be defeated once, got %+v", defeated)
+	}
+	if len(damage) != 2 || damage[0].Amount != 4 || damage[0].HPCurrent != 0 {
+		t.Errorf("Expected damage to stop at 0 HP, got %+v", damage)
+	}
+	if _, ok := events[0].(EncounterStarted); !ok {
+		t.Errorf("Expected EncounterStarted first, got %T", events[0])
+	}
+	if _, ok := events[len(events)-1].(DamageApplied); !ok {
+		t.Errorf("Expected DamageApplied last, got %T", events[len(events)-1])
+	}
+}
+
+func TestEngine_SideInitiative(t *testing.T) {
+	engine, session := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(7))
+	engine.Config = &config.Config{Combat: config.CombatConfig{Initiative: InitiativeSide, InitiativeDie: "1d6", Ties: TiesReroll}}
+
+	var rolled []SideInitiativeRolled
+	engine.EventBus.Subscribe("SideInitiativeRolled", func(event Event) {
+		rolled = append(rolled, event.(SideInitiativeRolled))
+	})
+
+	encounter, err := engine.StartEncounter(session.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	for _, c := range []struct {
+		name, side string
+	}{{"Fighter", model.SideParty}, {"Goblin 1", model.SideMonsters}, {"Cleric", model.SideParty}, {"Goblin 2", model.SideMonsters}} {
+		name := c.name
+		if err := engine.AddCombatant(&model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, Side: c.side}); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+	}
+
+	for round := int64(1); round <= 3; round++ {
+		for turn := 0; turn < 4; turn++ {
+			if _, err := engine.NextTurn(encounter.ID); err != nil {
+				t.Fatalf("Failed to pass the turn: %v", err)
+			}
+		}
+		if len(rolled) != int(round) {
+			t.Fatalf("Expected one roll per round, got %d by round %d", len(rolled), round)
+		}
+		rolls := rolled[round-1].Rolls
+		if rolls[model.SideParty] == rolls[model.SideMonsters] || len(rolled[round-1].Tied) != 0 {
+			t.Errorf("Expected ties to be re-rolled, got %v", rolls)
+		}
+
+		combatants, err := model.ListCombatants(engine.DB, encounter.ID)
+		if err != nil {
+			t.Fatalf("Failed to list combatants: %v", err)
+		}
+		for _, combatant := range combatants {
+			if combatant.Initiative != rolls[combatant.Side] {
+				t.Errorf("Expected %s to act on the %s roll %d, got %d", combatant.Name, combatant.Side, rolls[combatant.Side], combatant.Initiative)
+			}
+		}
+		if combatants[0].Side != combatants[1].Side || combatants[2].Side != combatants[3].Side {
+			t.Errorf("Expected each side to act together, got %+v", combatants)
+		}
+	}
+
+	// A goblin joining mid-round acts with its side.
+	name := "Goblin 3"
+	late := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name}
+	if err := engine.AddCombatant(late); err != nil {
+		t.Fatalf("Failed to add late goblin: %v", err)
+	}
+	if late.Initiative != rolled[2].Rolls[model.SideMonsters] {
+		t.Errorf("Expected the late goblin to take the monsters' roll, got %d", late.Initiative)
+	}
+
+	engine.Config.Combat.Ties = "coin"
+	if _, err := engine.NextTurn(encounter.ID); err == nil {
+		t.Error("Expected an error for an unknown tie rule")
+	}
+}
+
+func TestEngine_NextTurnUndo(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	encounter, err := engine.StartEncounter(session.ID, "Skeletons")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	name, hp := "Skeleton", 4
+	if err := engine.AddCombatant(&model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, Initiative: 10, HPCurrent: &hp, HPMax: &hp}); err != nil {
+		t.Fatalf("Failed to add combatant: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := engine.NextTurn(encounter.ID); err != nil {
+			t.Fatalf("Failed to pass the turn: %v", err)
+		}
+	}
+
+	// Starting round 2 moves the clock on a round; one undo takes back both.
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	current, err := model.GetEncounter(engine.DB, encounter.ID)
+	if err != nil {
+		t.Fatalf("Failed to get encounter: %v", err)
+	}
+	if current.Round != 1 {
+		t.Errorf("Expected the undo to return to round 1, got %d", current.Round)
+	}
+	updated, err := model.GetSession(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if updated.CurrentRound != 0 {
+		t.Errorf("Expected the undo to take the round off the clock, got round %d", updated.CurrentRound)
+	}
+}
+
+func TestEngine_DexTies(t *testing.T) {
+	for _, tt := range []struct {
+		ties  string
+		first string
+	}{
+		{TiesDex, "Thief"},
+		{TiesSimultaneous, "Fighter"},
+	} {
+		t.Run(tt.ties, func(t *testing.T) {
+			engine, session := newTestEngine(t)
+			engine.Config = &config.Config{Combat: config.CombatConfig{Ties: tt.ties}}
+
+			encounter, err := engine.StartEncounter(session.ID, "")
+			if err != nil {
+				t.Fatalf("Failed to start encounter: %v", err)
+			}
+			for _, c := range []struct {
+				name string
+				dex  int
+			}{{"Fighter", 9}, {"Thief", 17}} {
+				name, dex := c.name, c.dex
+				if err := engine.AddCombatant(&model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, Initiative: 10, Dex: &dex}); err != nil {
+					t.Fatalf("Failed to add %s: %v", name, err)
+				}
+			}
+
+			actor, err := engine.NextTurn(encounter.ID)
+			if err != nil {
+				t.Fatalf("Failed to pass the turn: %v", err)
+			}
+			if actor.Name != tt.first {
+				t.Errorf("Expected %s to act first, got %s", tt.first, actor.Name)
+			}
+			combatants, err := engine.Combatants(encounter.ID)
+			if err != nil {
+				t.Fatalf("Failed to list combatants: %v", err)
+			}
+			if combatants[0].Name != tt.first {
+				t.Errorf("Expected %s first in the turn order, got %s", tt.first, combatants[0].Name)
+			}
+		})
+	}
+}
@@ -109,3 +109,47 @@ func TestConcurrentTurnAdvancement(t *testing.T) {
 
 	t.Logf("Successfully completed concurrent test. Final turn: %d", finalSession.CurrentTurn)
 }
+
+func TestConcurrentDamage(t *testing.T) {
+	engine1, session := newTestEngine(t)
+	engine2 := &Engine{DB: engine1.DB}
+
+	encounter, err := engine1.StartEncounter(session.ID, "Siege")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	name, hp := "Ogre", 200
+	ogre := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, HPCurrent: &hp, HPMax: &hp}
+	if err := engine1.AddCombatant(ogre); err != nil {
+		t.Fatalf("Failed to add combatant: %v", err)
+	}
+
+	// Each engine deals its damage without re-reading the combatant, so
+	// every hit must build on the HP the other one left behind.
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for _, engine := range []*Engine{engine1, engine2} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				errs <- engine.Damage(ogre.ID, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to apply damage: %v", err)
+		}
+	}
+
+	combatant, err := model.FindCombatant(engine1.DB, ogre.ID)
+	if err != nil {
+		t.Fatalf("Failed to find combatant: %v", err)
+	}
+	if combatant.HPCurrent == nil || *combatant.HPCurrent != 100 {
+		t.Errorf("Expected 100 HP after 100 hits, got %v", combatant.HPCurrent)
+	}
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// AddCondition puts a condition on a combatant, replacing any condition of
+// the same name. A duration of 0 lasts until the condition is removed;
+// otherwise unit is model.ConditionRounds or model.ConditionTurns and the
+// condition wears off as that much game time passes.
+func (e *Engine) AddCondition(combatantID int64, name string, duration int64, unit string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("condition needs a name")
+	}
+	condition := model.Condition{Name: name}
+	if duration != 0 {
+		if duration < 0 {
+			return fmt.Errorf("condition duration cannot be negative, got %d", duration)
+		}
+		if unit != model.ConditionRounds && unit != model.ConditionTurns {
+			return fmt.Errorf("unknown condition unit %q, expected %s or %s", unit, model.ConditionRounds, model.ConditionTurns)
+		}
+		condition.Unit = unit
+		condition.Remaining = duration
+	}
+
+	return e.updateConditions(combatantID, func(combatant *model.Combatant, conditions []model.Condition) ([]model.Condition, string, error) {
+		kept := removeCondition(conditions, name)
+		return append(kept, condition), fmt.Sprintf("%s is %s", combatant.Name, condition), nil
+	}, func(combatant *model.Combatant, encounter *model.Encounter) Event {
+		return ConditionAdded{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounter.ID,
+			CombatantID: combatantID,
+			Name:        combatant.Name,
+			Condition:   condition,
+		}
+	})
+}
+
+// RemoveCondition takes a condition off a combatant.
+func (e *Engine) RemoveCondition(combatantID int64, name string) error {
+	return e.updateConditions(combatantID, func(combatant *model.Combatant, conditions []model.Condition) ([]model.Condition, string, error) {
+		kept := removeCondition(conditions, name)
+		if len(kept) == len(conditions) {
+			return nil, "", fmt.Errorf("%s is not %s", combatant.Name, name)
+		}
+		return kept, fmt.Sprintf("%s is no longer %s", combatant.Name, name), nil
+	}, func(combatant *model.Combatant, encounter *model.Encounter) Event {
+		return ConditionRemoved{
+			SessionID:   encounter.SessionID,
+			EncounterID: encounter.ID,
+			CombatantID: combatantID,
+			Name:        combatant.Name,
+			Condition:   name,
+		}
+	})
+}
+
+// updateConditions applies a change to a combatant's conditions as one
+// journaled action and emits the event it describes.
+func (e *Engine) updateConditions(
+	combatantID int64,
+	change func(*model.Combatant, []model.Condition) ([]model.Condition, string, error),
+	event func(*model.Combatant, *model.Encounter) Event,
+) error {
+	combatant, encounter, err := e.combatant(combatantID)
+	if err != nil {
+		return err
+	}
+	conditions, err := model.DecodeConditions(combatant.Conditions)
+	if err != nil {
+		return err
+	}
+	updated, description, err := change(combatant, conditions)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "condition")
+	if err != nil {
+		return err
+	}
+	if err := j.track("initiative_order", combatantID); err != nil {
+		return err
+	}
+	if err := model.SetCombatantConditions(tx, combatantID, updated); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(event(combatant, encounter))
+	}
+	return nil
+}
+
+// tickConditions wears down the timed conditions of combatants in a
+// session's running encounters by the rounds and turns that passed,
+// removing those that run out.
+func tickConditions(j *journal, sessionID, rounds, turns int64) ([]ConditionExpired, error) {
+	if rounds <= 0 && turns <= 0 {
+		return nil, nil
+	}
+	combatants, err := model.ListTimedConditions(j.tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []ConditionExpired
+	for _, combatant := range combatants {
+		conditions, err := model.DecodeConditions(combatant.Conditions)
+		if err != nil {
+			return nil, err
+		}
+
+		kept := make([]model.Condition, 0, len(conditions))
+		for _, condition := range conditions {
+			switch condition.Unit {
+			case model.ConditionRounds:
+				condition.Remaining -= rounds
+			case model.ConditionTurns:
+				condition.Remaining -= turns
+			}
+			if condition.Unit != "" && condition.Remaining <= 0 {
+				expired = append(expired, ConditionExpired{
+					SessionID:   sessionID,
+					EncounterID: combatant.EncounterID,
+					CombatantID: combatant.ID,
+					Name:        combatant.Name,
+					Condition:   condition.Name,
+				})
+				continue
+			}
+			kept = append(kept, condition)
+		}
+
+		if err := j.track("initiative_order", combatant.ID); err != nil {
+			return nil, err
+		}
+		if err := model.SetCombatantConditions(j.tx, combatant.ID, kept); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
+
+// removeCondition drops the conditions with a name, ignoring case.
+func removeCondition(conditions []model.Condition, name string) []model.Condition {
+	kept := make([]model.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		if !strings.EqualFold(condition.Name, name) {
+			kept = append(kept, condition)
+		}
+	}
+	return kept
+}
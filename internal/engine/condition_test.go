@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Conditions(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var expired []ConditionExpired
+	engine.EventBus.Subscribe("ConditionExpired", func(event Event) {
+		expired = append(expired, event.(ConditionExpired))
+	})
+
+	encounter, err := engine.StartEncounter(session.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	name := "Goblin"
+	goblin := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name}
+	if err := engine.AddCombatant(goblin); err != nil {
+		t.Fatalf("Failed to add goblin: %v", err)
+	}
+
+	if err := engine.AddCondition(goblin.ID, "poisoned", 2, model.ConditionRounds); err != nil {
+		t.Fatalf("Failed to poison goblin: %v", err)
+	}
+	if err := engine.AddCondition(goblin.ID, "asleep", 1, model.ConditionTurns); err != nil {
+		t.Fatalf("Failed to put goblin to sleep: %v", err)
+	}
+	if err := engine.AddCondition(goblin.ID, "prone", 0, ""); err != nil {
+		t.Fatalf("Failed to knock goblin prone: %v", err)
+	}
+	if err := engine.AddCondition(goblin.ID, "held", 3, "week"); err == nil {
+		t.Error("Expected an error for an unknown unit")
+	}
+
+	conditions := func() string {
+		t.Helper()
+		combatant, err := model.FindCombatant(engine.DB, goblin.ID)
+		if err != nil {
+			t.Fatalf("Failed to find goblin: %v", err)
+		}
+		return model.FormatConditions(combatant.Conditions)
+	}
+
+	// Round 1 starts without time passing; round 2 ticks a round.
+	for i := 0; i < 2; i++ {
+		if _, err := engine.NextTurn(encounter.ID); err != nil {
+			t.Fatalf("Failed to pass the turn: %v", err)
+		}
+	}
+	if got := conditions(); got != "poisoned (1 round), asleep (1 turn), prone" {
+		t.Errorf("Unexpected conditions after a round: %q", got)
+	}
+
+	if err := engine.AdvanceRounds(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance a round: %v", err)
+	}
+	if got := conditions(); got != "asleep (1 turn), prone" {
+		t.Errorf("Expected the poison to wear off, got %q", got)
+	}
+
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance a turn: %v", err)
+	}
+	if got := conditions(); got != "prone" {
+		t.Errorf("Expected the sleep to wear off, got %q", got)
+	}
+	if len(expired) != 2 || expired[0].Condition != "poisoned" || expired[1].Condition != "asleep" || expired[1].Name != "Goblin" {
+		t.Errorf("Unexpected expiry events %+v", expired)
+	}
+
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo the advance: %v", err)
+	}
+	if got := conditions(); got != "asleep (1 turn), prone" {
+		t.Errorf("Expected undo to restore the sleep, got %q", got)
+	}
+
+	if err := engine.RemoveCondition(goblin.ID, "Prone"); err != nil {
+		t.Fatalf("Failed to remove prone: %v", err)
+	}
+	if err := engine.RemoveCondition(goblin.ID, "prone"); err == nil {
+		t.Error("Expected an error removing a condition the goblin does not have")
+	}
+	if got := conditions(); got != "asleep (1 turn)" {
+		t.Errorf("Unexpected conditions after removal: %q", got)
+	}
+}
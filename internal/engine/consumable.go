@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// AddConsumable gives a character quantity more of a consumable, such as
+// rations or arrows, starting a new stack if they have none. usage is
+// model.ConsumablePerDay or model.ConsumablePerUse; empty keeps the stack's
+// usage, or is per use for a new one.
+func (e *Engine) AddConsumable(characterID int64, name string, quantity int, usage string) (*model.Consumable, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("consumables need a name")
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("quantity cannot be negative, got %d", quantity)
+	}
+	if usage != "" && usage != model.ConsumablePerDay && usage != model.ConsumablePerUse {
+		return nil, fmt.Errorf("unknown usage %q, expected %s or %s", usage, model.ConsumablePerDay, model.ConsumablePerUse)
+	}
+	character, err := model.GetCharacter(e.DB, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		return nil, fmt.Errorf("character %d not found", characterID)
+	}
+	consumable, err := model.GetConsumableByName(e.DB, characterID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "consumable")
+	if err != nil {
+		return nil, err
+	}
+	if consumable == nil {
+		consumable = &model.Consumable{CharacterID: characterID, Name: name, Quantity: quantity, Usage: usage}
+		if err := model.CreateConsumable(tx, consumable); err != nil {
+			return nil, err
+		}
+		j.created("consumables", consumable.ID)
+	} else {
+		if err := j.track("consumables", consumable.ID); err != nil {
+			return nil, err
+		}
+		consumable.Quantity += quantity
+		if usage != "" {
+			consumable.Usage = usage
+		}
+		if err := model.SetConsumable(tx, consumable.ID, consumable.Quantity, consumable.Usage); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := j.commit(fmt.Sprintf("%s took %d %s", character.Name, quantity, consumable.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return consumable, nil
+}
+
+// UseConsumable spends n of a character's consumable. Spending more than
+// they have is an error.
+func (e *Engine) UseConsumable(characterID int64, name string, n int) (*model.Consumable, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("must use at least 1, got %d", n)
+	}
+	character, err := model.GetCharacter(e.DB, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		return nil, fmt.Errorf("character %d not found", characterID)
+	}
+	consumable, err := model.GetConsumableByName(e.DB, characterID, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if consumable == nil {
+		return nil, fmt.Errorf("%s has no %s", character.Name, name)
+	}
+	if consumable.Quantity < n {
+		return nil, fmt.Errorf("%s has only %d %s", character.Name, consumable.Quantity, consumable.Name)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "consumable")
+	if err != nil {
+		return nil, err
+	}
+	if err := j.track("consumables", consumable.ID); err != nil {
+		return nil, err
+	}
+	consumable.Quantity -= n
+	if err := model.SetConsumable(tx, consumable.ID, consumable.Quantity, consumable.Usage); err != nil {
+		return nil, err
+	}
+
+	entry, err := j.commit(fmt.Sprintf("%s used %d %s, %d left", character.Name, n, consumable.Name, consumable.Quantity))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil && consumable.Quantity == 0 {
+		e.EventBus.Emit(ConsumableRanOut{
+			CharacterID:  characterID,
+			Name:         character.Name,
+			ConsumableID: consumable.ID,
+			Consumable:   consumable.Name,
+		})
+	}
+	return consumable, nil
+}
+
+// tickConsumables uses up the active characters' daily consumables, one
+// for each day that passed.
+func tickConsumables(j *journal, days int64) ([]ConsumableRanOut, error) {
+	if days <= 0 {
+		return nil, nil
+	}
+	consumables, err := model.ListDailyConsumables(j.tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranOut []ConsumableRanOut
+	for _, consumable := range consumables {
+		if err := j.track("consumables", consumable.ID); err != nil {
+			return nil, err
+		}
+		left := max(consumable.Quantity-int(days), 0)
+		if err := model.SetConsumable(j.tx, consumable.ID, left, consumable.Usage); err != nil {
+			return nil, err
+		}
+		if left == 0 {
+			ranOut = append(ranOut, ConsumableRanOut{
+				CharacterID:  consumable.CharacterID,
+				Name:         consumable.CharacterName,
+				ConsumableID: consumable.ID,
+				Consumable:   consumable.Name,
+			})
+		}
+	}
+	return ranOut, nil
+}
@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Encumbrance loads: the inventory slots carried items take up, or their
+// weight in coins.
+const (
+	LoadSlots  = "slots"
+	LoadWeight = "weight"
+)
+
+// Overloaded is the encumbrance tier of a character carrying more than the
+// campaign's last tier allows. They cannot move.
+const Overloaded = "overloaded"
+
+// Encumbrance is how much a character carries and how fast it lets them
+// move, in feet per turn.
+type Encumbrance struct {
+	Character model.Character
+	Load      int
+	Tier      string
+	Movement  int
+}
+
+// PartyMovement is the party's exploration movement rate: that of its
+// slowest member. Slowest is nil when the roster has no active characters
+// and the party moves at the campaign's unencumbered rate.
+type PartyMovement struct {
+	Members  []Encumbrance
+	Slowest  *Encumbrance
+	Movement int
+}
+
+// Exploration is the time the party took to explore a distance at its
+// movement rate.
+type Exploration struct {
+	Feet     int
+	Movement int
+	Turns    int64
+}
+
+// AddItem puts an item in a character's inventory. An item of the same
+// name is added to the stack already there, with its slots and weight.
+func (e *Engine) AddItem(item *model.Item) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return fmt.Errorf("items need a name")
+	}
+	if item.Quantity < 0 || item.Slots < 0 || item.Weight < 0 {
+		return fmt.Errorf("item quantity, slots and weight cannot be negative")
+	}
+	character, err := e.character(item.CharacterID)
+	if err != nil {
+		return err
+	}
+	existing, err := model.GetItemByName(e.DB, character.ID, item.Name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "item")
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if err := model.CreateItem(tx, item); err != nil {
+			return err
+		}
+		j.created("items", item.ID)
+	} else {
+		if err := j.track("items", existing.ID); err != nil {
+			return err
+		}
+		existing.Quantity += max(item.Quantity, 1)
+		existing.Slots += item.Slots
+		existing.Weight += item.Weight
+		if err := model.UpdateItem(tx, existing); err != nil {
+			return err
+		}
+		*item = *existing
+	}
+
+	entry, err := j.commit(fmt.Sprintf("%s took %s", character.Name, item.Name))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// StashItem leaves one of a character's items somewhere safe, or with
+// stashed false, has them carry it again.
+func (e *Engine) StashItem(characterID int64, name string, stashed bool) (*model.Item, error) {
+	character, item, err := e.item(characterID, name)
+	if err != nil {
+		return nil, err
+	}
+	item.Stashed = stashed
+	description := fmt.Sprintf("%s stashed %s", character.Name, item.Name)
+	if !stashed {
+		description = fmt.Sprintf("%s picked up %s", character.Name, item.Name)
+	}
+	if err := e.saveItem(item, description, false); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// DropItem takes an item out of a character's inventory.
+func (e *Engine) DropItem(characterID int64, name string) error {
+	character, item, err := e.item(characterID, name)
+	if err != nil {
+		return err
+	}
+	return e.saveItem(item, fmt.Sprintf("%s dropped %s", character.Name, item.Name), true)
+}
+
+// Encumbrance works out a character's load from the items they carry and
+// the encumbrance tier and movement rate it puts them in.
+func (e *Engine) Encumbrance(characterID int64) (*Encumbrance, error) {
+	character, err := e.character(characterID)
+	if err != nil {
+		return nil, err
+	}
+	return e.encumbrance(*character)
+}
+
+// PartyMovement works out each active character's encumbrance and the
+// rate the party moves at, that of its slowest member.
+func (e *Engine) PartyMovement() (*PartyMovement, error) {
+	tiers := e.config().Encumbrance.Tiers
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("no encumbrance tiers configured")
+	}
+	party, err := model.ListCharacters(e.DB, true)
+	if err != nil {
+		return nil, err
+	}
+
+	movement := &PartyMovement{Movement: tiers[0].Movement}
+	for _, character := range party {
+		encumbrance, err := e.encumbrance(character)
+		if err != nil {
+			return nil, err
+		}
+		movement.Members = append(movement.Members, *encumbrance)
+	}
+	for i, member := range movement.Members {
+		if movement.Slowest == nil || member.Movement < movement.Slowest.Movement {
+			movement.Slowest = &movement.Members[i]
+		}
+	}
+	if movement.Slowest != nil {
+		movement.Movement = movement.Slowest.Movement
+	}
+	return movement, nil
+}
+
+// Explore advances a session by the turns the party takes to cover feet
+// at its movement rate, rounding up to whole turns.
+func (e *Engine) Explore(sessionID int64, feet int) (*Exploration, error) {
+	if feet <= 0 {
+		return nil, fmt.Errorf("distance must be positive, got %d", feet)
+	}
+	party, err := e.PartyMovement()
+	if err != nil {
+		return nil, err
+	}
+	if party.Movement <= 0 {
+		name := "The party"
+		if party.Slowest != nil {
+			name = party.Slowest.Character.Name
+		}
+		return nil, fmt.Errorf("%s is too encumbered to move", name)
+	}
+
+	exploration := &Exploration{
+		Feet:     feet,
+		Movement: party.Movement,
+		Turns:    int64((feet + party.Movement - 1) / party.Movement),
+	}
+	if err := e.Advance(sessionID, exploration.Turns); err != nil {
+		return nil, err
+	}
+	return exploration, nil
+}
+
+// encumbrance finds the tier a character's carried load falls in.
+func (e *Engine) encumbrance(character model.Character) (*Encumbrance, error) {
+	rules := e.config().Encumbrance
+	if rules.Load != LoadSlots && rules.Load != LoadWeight {
+		return nil, fmt.Errorf("unknown encumbrance load %q, expected %s or %s", rules.Load, LoadSlots, LoadWeight)
+	}
+	items, err := model.ListItems(e.DB, character.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	encumbrance := &Encumbrance{Character: character, Tier: Overloaded}
+	for _, item := range items {
+		if item.Stashed {
+			continue
+		}
+		if rules.Load == LoadSlots {
+			encumbrance.Load += item.Slots
+		} else {
+			encumbrance.Load += item.Weight
+		}
+	}
+	for _, tier := range rules.Tiers {
+		if encumbrance.Load <= tier.Max {
+			encumbrance.Tier = tier.Name
+			encumbrance.Movement = tier.Movement
+			break
+		}
+	}
+	return encumbrance, nil
+}
+
+// item looks up an item in a character's inventory by name.
+func (e *Engine) item(characterID int64, name string) (*model.Character, *model.Item, error) {
+	character, err := e.character(characterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	item, err := model.GetItemByName(e.DB, characterID, strings.TrimSpace(name))
+	if err != nil {
+		return nil, nil, err
+	}
+	if item == nil {
+		return nil, nil, fmt.Errorf("%s has no %s", character.Name, name)
+	}
+	return character, item, nil
+}
+
+// saveItem updates an item, or deletes it, as one journaled action.
+func (e *Engine) saveItem(item *model.Item, description string, remove bool) error {
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "item")
+	if err != nil {
+		return err
+	}
+	if err := j.track("items", item.ID); err != nil {
+		return err
+	}
+	if remove {
+		err = model.DeleteItem(tx, item.ID)
+	} else {
+		err = model.UpdateItem(tx, item)
+	}
+	if err != nil {
+		return err
+	}
+
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Encumbrance(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	movement, err := engine.PartyMovement()
+	if err != nil {
+		t.Fatalf("Failed to get party movement: %v", err)
+	}
+	if movement.Slowest != nil || movement.Movement != 120 {
+		t.Errorf("Expected an empty roster to move 120', got %+v", movement)
+	}
+
+	theron := &model.Character{Name: "Theron", IsActive: true}
+	mira := &model.Character{Name: "Mira", IsActive: true}
+	for _, character := range []*model.Character{theron, mira} {
+		if err := engine.AddCharacter(character); err != nil {
+			t.Fatalf("Failed to add character: %v", err)
+		}
+	}
+	items := []*model.Item{
+		{CharacterID: theron.ID, Name: "Plate mail", Slots: 6, Weight: 500},
+		{CharacterID: theron.ID, Name: "Sword", Slots: 1, Weight: 60},
+		{CharacterID: theron.ID, Name: "Rope", Slots: 1, Weight: 50},
+		{CharacterID: theron.ID, Name: "Rope", Slots: 1, Weight: 50},
+		{CharacterID: theron.ID, Name: "Sack of coins", Slots: 3, Weight: 300},
+		{CharacterID: mira.ID, Name: "Spellbook", Slots: 1, Weight: 50},
+	}
+	for _, item := range items {
+		if err := engine.AddItem(item); err != nil {
+			t.Fatalf("Failed to add item: %v", err)
+		}
+	}
+	if rope := items[3]; rope.ID != items[2].ID || rope.Quantity != 2 || rope.Slots != 2 {
+		t.Errorf("Expected the second rope added to the first, got %+v", rope)
+	}
+
+	encumbrance, err := engine.Encumbrance(theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to get encumbrance: %v", err)
+	}
+	if encumbrance.Load != 12 || encumbrance.Tier != "lightly encumbered" || encumbrance.Movement != 90 {
+		t.Errorf("Expected Theron lightly encumbered with 12 slots, got %+v", encumbrance)
+	}
+
+	// Mapping 240' of corridor at Theron's 90' takes 3 turns.
+	exploration, err := engine.Explore(session.ID, 240)
+	if err != nil {
+		t.Fatalf("Failed to explore: %v", err)
+	}
+	if exploration.Movement != 90 || exploration.Turns != 3 {
+		t.Errorf("Expected 3 turns at 90', got %+v", exploration)
+	}
+	current, err := model.GetSession(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if current.CurrentTurn != 3 {
+		t.Errorf("Expected exploring to advance to turn 3, got %d", current.CurrentTurn)
+	}
+
+	// Stashing the coins lets Theron keep up with Mira.
+	if _, err := engine.StashItem(theron.ID, "sack of coins", true); err != nil {
+		t.Fatalf("Failed to stash item: %v", err)
+	}
+	if movement, err = engine.PartyMovement(); err != nil {
+		t.Fatalf("Failed to get party movement: %v", err)
+	}
+	if movement.Movement != 120 || len(movement.Members) != 2 {
+		t.Errorf("Expected the party to move 120', got %+v", movement)
+	}
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if movement, err = engine.PartyMovement(); err != nil {
+		t.Fatalf("Failed to get party movement: %v", err)
+	}
+	if movement.Movement != 90 || movement.Slowest.Character.Name != "Theron" {
+		t.Errorf("Expected undo to slow the party to Theron's 90', got %+v", movement)
+	}
+
+	if err := engine.DropItem(mira.ID, "Spellbook"); err != nil {
+		t.Fatalf("Failed to drop item: %v", err)
+	}
+	if err := engine.DropItem(mira.ID, "Spellbook"); err == nil {
+		t.Error("Expected an error dropping an item Mira no longer has")
+	}
+
+	// By weight, Theron's 960 coins overload him.
+	cfg := config.DefaultConfig()
+	cfg.Encumbrance = config.EncumbranceConfig{Load: LoadWeight, Tiers: []config.EncumbranceTier{
+		{Name: "unencumbered", Max: 400, Movement: 120},
+		{Name: "encumbered", Max: 800, Movement: 60},
+	}}
+	engine.Config = &cfg
+	if encumbrance, err = engine.Encumbrance(theron.ID); err != nil {
+		t.Fatalf("Failed to get encumbrance: %v", err)
+	}
+	if encumbrance.Load != 960 || encumbrance.Tier != Overloaded || encumbrance.Movement != 0 {
+		t.Errorf("Expected Theron overloaded with 960 coins, got %+v", encumbrance)
+	}
+	if _, err := engine.Explore(session.ID, 60); err == nil {
+		t.Error("Expected an error exploring with an overloaded character")
+	}
+}
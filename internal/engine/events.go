@@ -1,6 +1,13 @@
 package engine
 
-import "sync"
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/script-wizards/spells/internal/model"
+)
 
 type Event interface {
 	Type() string
@@ -46,3 +53,473 @@ func (bus *EventBus) Emit(event Event) {
 		handler(event)
 	}
 }
+
+// EventTriggered is emitted for each scheduled time event that a turn
+// advance reaches. A recurring event is emitted once per occurrence.
+type EventTriggered struct {
+	SessionID   int64
+	EventID     int64
+	EventType   string
+	Description string
+	TriggerTurn int64
+	Recurring   bool
+}
+
+func (e EventTriggered) Type() string {
+	return "EventTriggered"
+}
+
+// WanderingCheckRolled is emitted for every wandering monster check. When
+// Encountered is set, Result holds the encounter resolved from Table and
+// EncounterID the encounter started for it, if any.
+type WanderingCheckRolled struct {
+	SessionID   int64
+	CheckID     int64
+	Location    string
+	Roll        int
+	Chance      int
+	Die         int
+	Encountered bool
+	Table       string
+	Result      string
+	EncounterID *int64
+}
+
+func (e WanderingCheckRolled) Type() string {
+	return "WanderingCheckRolled"
+}
+
+// RoundAdvanced is emitted when time moves forward by combat rounds. When
+// the rounds carry into a new turn a TurnAdvanced event follows it.
+type RoundAdvanced struct {
+	SessionID int64
+	OldTurn   int64
+	OldRound  int64
+	NewTurn   int64
+	NewRound  int64
+	Delta     int64
+}
+
+func (e RoundAdvanced) Type() string {
+	return "RoundAdvanced"
+}
+
+// TimeModeChanged is emitted when a session switches between exploration
+// and combat time.
+type TimeModeChanged struct {
+	SessionID int64
+	Mode      string
+}
+
+func (e TimeModeChanged) Type() string {
+	return "TimeModeChanged"
+}
+
+// TimerStarted is emitted when a real-world timer starts.
+type TimerStarted struct {
+	TimerID   int64
+	Label     string
+	ExpiresAt time.Time
+}
+
+func (e TimerStarted) Type() string {
+	return "TimerStarted"
+}
+
+// TimerExpired is emitted once per process for each running timer that
+// has run out, including timers that ran out while no process was open.
+type TimerExpired struct {
+	TimerID   int64
+	Label     string
+	ExpiresAt time.Time
+}
+
+func (e TimerExpired) Type() string {
+	return "TimerExpired"
+}
+
+// TimerFinished is emitted when a timer is completed or cancelled.
+type TimerFinished struct {
+	TimerID int64
+	Label   string
+	Status  string
+}
+
+func (e TimerFinished) Type() string {
+	return "TimerFinished"
+}
+
+// DataChanged is emitted by a ChangeWatcher for each write to shared state
+// logged in the database, whichever process made it. Table is the table
+// written to, e.g. sessions, initiative_order, npcs or timers, and Action
+// is insert, update or delete. Handlers should reload what they show
+// rather than assume the change came from elsewhere.
+type DataChanged struct {
+	ChangeID  int64
+	Table     string
+	RowID     int64
+	Action    string
+	SessionID *int64
+}
+
+func (e DataChanged) Type() string {
+	return "DataChanged"
+}
+
+// ActionRecorded is emitted when an action is written to the journal,
+// including undo and redo, whose Action is "undo" or "redo".
+type ActionRecorded struct {
+	SessionID   *int64
+	EntryID     int64
+	Action      string
+	Description string
+}
+
+func (e ActionRecorded) Type() string {
+	return "ActionRecorded"
+}
+
+// EncounterStarted is emitted when an encounter begins.
+type EncounterStarted struct {
+	SessionID   int64
+	EncounterID int64
+	Name        string
+}
+
+func (e EncounterStarted) Type() string {
+	return "EncounterStarted"
+}
+
+// EncounterEnded is emitted when an encounter is over.
+type EncounterEnded struct {
+	SessionID   int64
+	EncounterID int64
+	Name        string
+	Rounds      int64
+}
+
+func (e EncounterEnded) Type() string {
+	return "EncounterEnded"
+}
+
+// CombatantAdded is emitted when a combatant joins an encounter.
+type CombatantAdded struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+}
+
+func (e CombatantAdded) Type() string {
+	return "CombatantAdded"
+}
+
+// CombatantRemoved is emitted when a combatant leaves an encounter.
+type CombatantRemoved struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+}
+
+func (e CombatantRemoved) Type() string {
+	return "CombatantRemoved"
+}
+
+// CombatantStatusChanged is emitted when a combatant is taken out of the
+// turn order or put back.
+type CombatantStatusChanged struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+	Active      bool
+}
+
+func (e CombatantStatusChanged) Type() string {
+	return "CombatantStatusChanged"
+}
+
+// DamageApplied is emitted when a combatant loses hit points. HPCurrent is
+// what it has left, never below 0.
+type DamageApplied struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+	Amount      int
+	HPCurrent   int
+}
+
+func (e DamageApplied) Type() string {
+	return "DamageApplied"
+}
+
+// CombatantHealed is emitted when a combatant regains hit points.
+type CombatantHealed struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+	Amount      int
+	HPCurrent   int
+}
+
+func (e CombatantHealed) Type() string {
+	return "CombatantHealed"
+}
+
+// CombatantDefeated is emitted after DamageApplied when a combatant drops
+// to 0 HP. Defeated combatants stay in the encounter but no longer act.
+type CombatantDefeated struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+}
+
+func (e CombatantDefeated) Type() string {
+	return "CombatantDefeated"
+}
+
+// CombatTurnStarted is emitted when it becomes a combatant's turn. NewRound
+// is set on the first turn of each round.
+type CombatTurnStarted struct {
+	SessionID   int64
+	EncounterID int64
+	Round       int64
+	CombatantID int64
+	Name        string
+	NewRound    bool
+}
+
+func (e CombatTurnStarted) Type() string {
+	return "CombatTurnStarted"
+}
+
+// SideInitiativeRolled is emitted at the start of each round under side
+// initiative, with each side's roll. Tied lists sides that share a roll
+// and act simultaneously, or by Dexterity when ties go by DEX.
+type SideInitiativeRolled struct {
+	SessionID   int64
+	EncounterID int64
+	Round       int64
+	Rolls       map[string]int
+	Tied        []string
+}
+
+func (e SideInitiativeRolled) Type() string {
+	return "SideInitiativeRolled"
+}
+
+// Summary lists the rolls from highest to lowest, e.g. "party 5,
+// monsters 2".
+func (e SideInitiativeRolled) Summary() string {
+	return describeSideRolls(e.Rolls)
+}
+
+// ConditionAdded is emitted when a combatant gains a condition.
+type ConditionAdded struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+	Condition   model.Condition
+}
+
+func (e ConditionAdded) Type() string {
+	return "ConditionAdded"
+}
+
+// ConditionRemoved is emitted when a condition is taken off a combatant.
+type ConditionRemoved struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+	Condition   string
+}
+
+func (e ConditionRemoved) Type() string {
+	return "ConditionRemoved"
+}
+
+// ConditionExpired is emitted when a timed condition wears off as time
+// advances.
+type ConditionExpired struct {
+	SessionID   int64
+	EncounterID int64
+	CombatantID int64
+	Name        string
+	Condition   string
+}
+
+func (e ConditionExpired) Type() string {
+	return "ConditionExpired"
+}
+
+// MoraleChecked is emitted when a side rolls morale, either because a
+// combatant fell (Trigger is MoraleFirstDeath or MoraleHalfStrength) or
+// because the referee called for it (MoraleCalled).
+type MoraleChecked struct {
+	SessionID   int64
+	EncounterID int64
+	Side        string
+	Trigger     string
+	Rolls       []MoraleRoll
+}
+
+func (e MoraleChecked) Type() string {
+	return "MoraleChecked"
+}
+
+// Summary lists each roll, e.g. "Goblin 1 flees (9 vs ML 7), Goblin 2
+// stands (4 vs ML 7)".
+func (e MoraleChecked) Summary() string {
+	return describeMorale(e.Rolls)
+}
+
+// Fled lists the names of those who failed the check.
+func (e MoraleChecked) Fled() []string {
+	var fled []string
+	for _, roll := range e.Rolls {
+		if roll.Fled {
+			fled = append(fled, roll.Name)
+		}
+	}
+	return fled
+}
+
+// ReactionRolled is emitted for a reaction roll. Applied is set when the
+// roll changed the NPC's status from OldStatus to Status.
+type ReactionRolled struct {
+	NPCID     int64
+	Name      string
+	Roll      int
+	Modifier  int
+	Total     int
+	Reaction  string
+	Status    string
+	OldStatus string
+	Applied   bool
+}
+
+func (e ReactionRolled) Type() string {
+	return "ReactionRolled"
+}
+
+// AttackResolved is emitted when an attack is resolved, with each attack
+// roll, the total damage done and the target's HP afterwards. The
+// DamageApplied and CombatantDefeated events for the target follow it.
+type AttackResolved struct {
+	SessionID   int64
+	EncounterID int64
+	AttackerID  int64
+	Attacker    string
+	TargetID    int64
+	Target      string
+	Rolls       []AttackRoll
+	Damage      int
+	HPCurrent   int
+}
+
+func (e AttackResolved) Type() string {
+	return "AttackResolved"
+}
+
+// Summary describes the attack, e.g. "Goblin 1 attacks Fighter: hit for 4
+// (14 vs 12), Fighter at 4 HP".
+func (e AttackResolved) Summary() string {
+	rolls := make([]string, len(e.Rolls))
+	for i, roll := range e.Rolls {
+		rolls[i] = roll.String()
+	}
+	summary := fmt.Sprintf("%s attacks %s: %s", e.Attacker, e.Target, strings.Join(rolls, ", "))
+	switch {
+	case e.Damage > 0 && e.HPCurrent == 0:
+		summary += fmt.Sprintf("; %s is defeated", e.Target)
+	case e.Damage > 0:
+		summary += fmt.Sprintf("; %s at %d HP", e.Target, e.HPCurrent)
+	}
+	return summary
+}
+
+// TreasureFound is emitted when treasure is recorded.
+type TreasureFound struct {
+	SessionID   int64
+	TreasureID  int64
+	Description string
+	GoldValue   int
+	Location    string
+}
+
+func (e TreasureFound) Type() string {
+	return "TreasureFound"
+}
+
+// XPEarned is emitted when XP is credited to the party, for a defeated
+// monster or for awarded treasure. XP is the party's total, before it is
+// shared out.
+type XPEarned struct {
+	SessionID   int64
+	Source      string
+	Description string
+	XP          int
+}
+
+func (e XPEarned) Type() string {
+	return "XPEarned"
+}
+
+// LightLow is emitted when a character's light has only the campaign's
+// light warning turns or fewer left to burn.
+type LightLow struct {
+	SessionID   int64
+	CharacterID int64
+	Name        string
+	Source      string
+	Remaining   int
+}
+
+func (e LightLow) Type() string {
+	return "LightLow"
+}
+
+// Summary warns of the light, e.g. "Theron's torch is about to go out (1
+// turn left)".
+func (e LightLow) Summary() string {
+	return fmt.Sprintf("%s's %s is about to go out (%s left)", e.Name, e.Source, plural(int64(e.Remaining), "turn"))
+}
+
+// LightOut is emitted when a character's light burns out.
+type LightOut struct {
+	SessionID   int64
+	CharacterID int64
+	Name        string
+	Source      string
+}
+
+func (e LightOut) Type() string {
+	return "LightOut"
+}
+
+func (e LightOut) Summary() string {
+	return fmt.Sprintf("%s's %s goes out", e.Name, e.Source)
+}
+
+// ConsumableRanOut is emitted when a character uses the last of a
+// consumable, whether spent or used up as the days pass.
+type ConsumableRanOut struct {
+	CharacterID  int64
+	Name         string
+	ConsumableID int64
+	Consumable   string
+}
+
+func (e ConsumableRanOut) Type() string {
+	return "ConsumableRanOut"
+}
+
+func (e ConsumableRanOut) Summary() string {
+	return fmt.Sprintf("%s is out of %s", e.Name, e.Consumable)
+}
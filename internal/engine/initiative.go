@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Initiative modes and tie rules for config.CombatConfig.
+const (
+	InitiativeIndividual = "individual"
+	InitiativeSide       = "side"
+
+	TiesSimultaneous = "simultaneous"
+	TiesReroll       = "reroll"
+	TiesDex          = "dex"
+)
+
+// maxInitiativeRerolls bounds re-rolling tied sides, for when there are
+// more sides than faces on the die.
+const maxInitiativeRerolls = 20
+
+func validateInitiative(rules config.CombatConfig) error {
+	switch rules.Initiative {
+	case InitiativeIndividual, "":
+		return nil
+	case InitiativeSide:
+	default:
+		return fmt.Errorf("unknown initiative mode %q, expected %s or %s", rules.Initiative, InitiativeIndividual, InitiativeSide)
+	}
+
+	switch rules.Ties {
+	case TiesSimultaneous, TiesReroll, TiesDex, "":
+		return nil
+	default:
+		return fmt.Errorf("unknown initiative tie rule %q, expected %s, %s or %s", rules.Ties, TiesSimultaneous, TiesReroll, TiesDex)
+	}
+}
+
+// rollSideInitiative rolls the initiative die for every side with a
+// combatant that can act, gives each member its side's roll and re-sorts
+// combatants into the new turn order.
+func (e *Engine) rollSideInitiative(j *journal, rules config.CombatConfig, combatants []model.Combatant) (*SideInitiativeRolled, error) {
+	die := rules.InitiativeDie
+	if die == "" {
+		die = "1d6"
+	}
+
+	var sides []string
+	for _, combatant := range combatants {
+		if combatant.CanAct() && !slices.Contains(sides, combatant.Side) {
+			sides = append(sides, combatant.Side)
+		}
+	}
+
+	rng := e.rng()
+	rolls := make(map[string]int, len(sides))
+	for attempt := 0; ; attempt++ {
+		for _, side := range sides {
+			roll, _, err := dice.Roll(die, rng)
+			if err != nil {
+				return nil, fmt.Errorf("failed to roll initiative: %w", err)
+			}
+			rolls[side] = roll
+		}
+		if rules.Ties != TiesReroll || len(tiedSides(rolls)) == 0 || attempt >= maxInitiativeRerolls {
+			break
+		}
+	}
+
+	for i := range combatants {
+		roll, rolled := rolls[combatants[i].Side]
+		if !rolled || combatants[i].Initiative == roll {
+			continue
+		}
+		if err := j.track("initiative_order", combatants[i].ID); err != nil {
+			return nil, err
+		}
+		if err := model.SetCombatantInitiative(j.tx, combatants[i].ID, roll); err != nil {
+			return nil, err
+		}
+		combatants[i].Initiative = roll
+	}
+	sortCombatants(combatants, rules.Ties == TiesDex)
+
+	return &SideInitiativeRolled{Rolls: rolls, Tied: tiedSides(rolls)}, nil
+}
+
+// Combatants returns every combatant in an encounter in turn order.
+func (e *Engine) Combatants(encounterID int64) ([]model.Combatant, error) {
+	return e.listCombatants(e.DB, encounterID)
+}
+
+// listCombatants returns an encounter's combatants in turn order: by
+// initiative, then by Dexterity when the campaign breaks ties that way,
+// then in the order they were added.
+func (e *Engine) listCombatants(db sqlx.Queryer, encounterID int64) ([]model.Combatant, error) {
+	combatants, err := model.ListCombatants(db, encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if e.config().Combat.Ties == TiesDex {
+		sortCombatants(combatants, true)
+	}
+	return combatants, nil
+}
+
+// sortCombatants puts combatants in turn order, breaking ties by Dexterity
+// when byDex is set.
+func sortCombatants(combatants []model.Combatant, byDex bool) {
+	sort.SliceStable(combatants, func(i, j int) bool {
+		a, b := combatants[i], combatants[j]
+		if a.Initiative != b.Initiative {
+			return a.Initiative > b.Initiative
+		}
+		if byDex {
+			if (a.Dex == nil) != (b.Dex == nil) {
+				return a.Dex != nil
+			}
+			if a.Dex != nil && *a.Dex != *b.Dex {
+				return *a.Dex > *b.Dex
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+// tiedSides returns the sides that share their roll with another side, in
+// name order.
+func tiedSides(rolls map[string]int) []string {
+	var tied []string
+	for side, roll := range rolls {
+		for other, otherRoll := range rolls {
+			if other != side && otherRoll == roll {
+				tied = append(tied, side)
+				break
+			}
+		}
+	}
+	sort.Strings(tied)
+	return tied
+}
+
+// describeSideRolls lists side rolls from highest to lowest, e.g. "party
+// 5, monsters 2".
+func describeSideRolls(rolls map[string]int) string {
+	sides := make([]string, 0, len(rolls))
+	for side := range rolls {
+		sides = append(sides, side)
+	}
+	sort.Slice(sides, func(i, j int) bool {
+		if rolls[sides[i]] != rolls[sides[j]] {
+			return rolls[sides[i]] > rolls[sides[j]]
+		}
+		return sides[i] < sides[j]
+	})
+
+	parts := make([]string, len(sides))
+	for i, side := range sides {
+		parts[i] = fmt.Sprintf("%s %d", side, rolls[side])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,180 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// journal records the rows one action changes, so the action can be undone
+// and redone. Its entry is written when it begins, which takes the write
+// lock before any row is read, and completed by commit.
+type journal struct {
+	tx      *sqlx.Tx
+	entry   *model.JournalEntry
+	changes []model.RowChange
+	tracked map[string]int
+}
+
+func beginJournal(tx *sqlx.Tx, sessionID *int64, action string) (*journal, error) {
+	entry := &model.JournalEntry{SessionID: sessionID, Action: action}
+	if err := model.CreateJournalEntry(tx, entry); err != nil {
+		return nil, fmt.Errorf("failed to start journal entry: %w", err)
+	}
+	return &journal{tx: tx, entry: entry, tracked: make(map[string]int)}, nil
+}
+
+// track snapshots a row before the action changes it. Rows tracked more
+// than once keep their first snapshot.
+func (j *journal) track(table string, id int64) error {
+	key := fmt.Sprintf("%s/%d", table, id)
+	if _, exists := j.tracked[key]; exists {
+		return nil
+	}
+
+	before, err := model.SnapshotRow(j.tx, table, id)
+	if err != nil {
+		return err
+	}
+	j.tracked[key] = len(j.changes)
+	j.changes = append(j.changes, model.RowChange{Table: table, ID: id, Before: before})
+	return nil
+}
+
+// created records a row the action inserted.
+func (j *journal) created(table string, id int64) {
+	key := fmt.Sprintf("%s/%d", table, id)
+	if _, exists := j.tracked[key]; exists {
+		return
+	}
+	j.tracked[key] = len(j.changes)
+	j.changes = append(j.changes, model.RowChange{Table: table, ID: id})
+}
+
+// commit snapshots every tracked row after the action and completes the
+// entry. Rows the action left as they were are dropped, and an action that
+// changed nothing leaves no entry and returns nil.
+func (j *journal) commit(description string) (*model.JournalEntry, error) {
+	changes := make([]model.RowChange, 0, len(j.changes))
+	for _, change := range j.changes {
+		after, err := model.SnapshotRow(j.tx, change.Table, change.ID)
+		if err != nil {
+			return nil, err
+		}
+		if model.SnapshotsEqual(change.Before, after) {
+			continue
+		}
+		change.After = after
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return nil, model.DiscardJournalEntry(j.tx, j.entry.ID)
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	j.entry.Description = description
+	j.entry.Changes = string(encoded)
+	if err := model.FinishJournalEntry(j.tx, j.entry); err != nil {
+		return nil, err
+	}
+	return j.entry, nil
+}
+
+// emitRecorded announces a committed journal entry.
+func (e *Engine) emitRecorded(entry *model.JournalEntry) {
+	if e.EventBus == nil || entry == nil {
+		return
+	}
+	e.EventBus.Emit(ActionRecorded{
+		SessionID:   entry.SessionID,
+		EntryID:     entry.ID,
+		Action:      entry.Action,
+		Description: entry.Description,
+	})
+}
+
+// Undo reverts the latest action in a session that is still in effect.
+// The undo is journaled too, and can itself be redone.
+func (e *Engine) Undo(sessionID int64) (*model.JournalEntry, error) {
+	return e.revert(sessionID, model.JournalUndo)
+}
+
+// Redo reapplies the most recently undone action in a session.
+func (e *Engine) Redo(sessionID int64) (*model.JournalEntry, error) {
+	return e.revert(sessionID, model.JournalRedo)
+}
+
+func (e *Engine) revert(sessionID int64, action string) (*model.JournalEntry, error) {
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, action)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *model.JournalEntry
+	if action == model.JournalUndo {
+		target, err = model.UndoTarget(tx, sessionID)
+	} else {
+		target, err = model.RedoTarget(tx, sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("nothing to %s", action)
+	}
+
+	changes, err := target.RowChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	if action == model.JournalUndo {
+		for i := len(changes) - 1; i >= 0; i-- {
+			if err := j.restore(changes[i].Table, changes[i].ID, changes[i].Before); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, change := range changes {
+			if err := j.restore(change.Table, change.ID, change.After); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	j.entry.RevertsID = &target.ID
+	label := "Undo"
+	if action == model.JournalRedo {
+		label = "Redo"
+	}
+	entry, err := j.commit(label + ": " + target.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return target, nil
+}
+
+// restore tracks a row and puts it back to a snapshot.
+func (j *journal) restore(table string, id int64, values map[string]any) error {
+	if err := j.track(table, id); err != nil {
+		return err
+	}
+	return model.RestoreRow(j.tx, table, id, values)
+}
@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_UndoRedoAdvance(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var recorded []ActionRecorded
+	engine.EventBus.Subscribe("ActionRecorded", func(event Event) {
+		recorded = append(recorded, event.(ActionRecorded))
+	})
+
+	reminder, err := engine.Schedule(session.ID, model.TimeEventReminder, "Guards change", 2)
+	if err != nil {
+		t.Fatalf("Failed to schedule reminder: %v", err)
+	}
+	if err := engine.Advance(session.ID, 3); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+
+	undone, err := engine.Undo(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if undone.Description != "Advanced 3 turns to turn 3" {
+		t.Errorf("Unexpected undone action %q", undone.Description)
+	}
+	assertTurn(t, engine, session.ID, 0)
+	event, err := model.GetTimeEvent(engine.DB, reminder.ID)
+	if err != nil || event == nil || event.Handled {
+		t.Fatalf("Expected the reminder to be pending again, got %+v, %v", event, err)
+	}
+
+	if _, err := engine.Redo(session.ID); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	assertTurn(t, engine, session.ID, 3)
+	if event, _ := model.GetTimeEvent(engine.DB, reminder.ID); event == nil || !event.Handled {
+		t.Errorf("Expected the reminder to trigger again, got %+v", event)
+	}
+	if _, err := engine.Redo(session.ID); err == nil {
+		t.Error("Expected nothing to redo")
+	}
+
+	// Undo walks back through earlier actions, down to the reminder.
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo advance: %v", err)
+	}
+	if undone, err = engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo reminder: %v", err)
+	}
+	if event, _ := model.GetTimeEvent(engine.DB, reminder.ID); event != nil {
+		t.Errorf("Expected the reminder to be unscheduled, got %+v", event)
+	}
+	if _, err := engine.Undo(session.ID); err == nil {
+		t.Error("Expected nothing to undo")
+	}
+
+	// A new action leaves nothing to redo.
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if _, err := engine.Redo(session.ID); err == nil {
+		t.Error("Expected a new action to clear redo")
+	}
+
+	last := recorded[len(recorded)-1]
+	if last.Action != "advance" || last.SessionID == nil || *last.SessionID != session.ID {
+		t.Errorf("Unexpected last recorded action %+v", last)
+	}
+	if recorded[2].Action != model.JournalUndo || recorded[2].Description != "Undo: Advanced 3 turns to turn 3" {
+		t.Errorf("Unexpected undo entry %+v", recorded[2])
+	}
+}
+
+func TestEngine_UndoCombatAndNPCEdits(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	tx, err := engine.DB.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	encounter := &model.Encounter{SessionID: session.ID, IsActive: true}
+	if err := model.CreateEncounter(tx, encounter); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create encounter: %v", err)
+	}
+	npc := &model.NPC{Name: "Mira", Status: "neutral"}
+	if err := model.CreateNPC(tx, npc); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create npc: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	name, hp := "Goblin", 5
+	combatant := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, Initiative: 12, HPCurrent: &hp, HPMax: &hp}
+	if err := engine.AddCombatant(combatant); err != nil {
+		t.Fatalf("Failed to add combatant: %v", err)
+	}
+	if err := engine.SetCombatantHP(combatant.ID, 2); err != nil {
+		t.Fatalf("Failed to set hp: %v", err)
+	}
+	npc.Status = "hostile"
+	if err := engine.UpdateNPC(npc); err != nil {
+		t.Fatalf("Failed to update npc: %v", err)
+	}
+
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo npc edit: %v", err)
+	}
+	if restored, _ := model.GetNPC(engine.DB, npc.ID); restored == nil || restored.Status != "neutral" {
+		t.Errorf("Expected the npc edit to be undone, got %+v", restored)
+	}
+
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo hp change: %v", err)
+	}
+	if restored, _ := model.GetCombatant(engine.DB, combatant.ID); restored == nil || *restored.HPCurrent != 5 {
+		t.Errorf("Expected the hp change to be undone, got %+v", restored)
+	}
+
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo combatant add: %v", err)
+	}
+	if removed, _ := model.GetCombatant(engine.DB, combatant.ID); removed != nil {
+		t.Errorf("Expected the combatant to be removed, got %+v", removed)
+	}
+
+	if _, err := engine.Redo(session.ID); err != nil {
+		t.Fatalf("Failed to redo combatant add: %v", err)
+	}
+	if readded, _ := model.GetCombatant(engine.DB, combatant.ID); readded == nil || *readded.CharacterName != "Goblin" {
+		t.Errorf("Expected the combatant back, got %+v", readded)
+	}
+}
+
+func assertTurn(t *testing.T, engine *Engine, sessionID, turn int64) {
+	t.Helper()
+
+	session, err := model.GetSession(engine.DB, sessionID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if session.CurrentTurn != turn {
+		t.Errorf("Expected turn %d, got %d", turn, session.CurrentTurn)
+	}
+}
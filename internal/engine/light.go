@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// LightSource lights a fresh light of a kind for a character: a torch, a
+// lantern or magic light, burning for the turns the campaign sets for that
+// kind. Without a kind, the character's own light source is lit, or a
+// torch if they have none.
+func (e *Engine) LightSource(characterID int64, kind string) (*model.Character, error) {
+	character, err := model.GetCharacter(e.DB, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		return nil, fmt.Errorf("character %d not found", characterID)
+	}
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if kind == "" {
+		kind = model.LightTorch
+		if character.LightSource != nil {
+			kind = strings.ToLower(*character.LightSource)
+		}
+	}
+	duration, err := e.lightDuration(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := duration
+	character.LightSource = &kind
+	character.LightRemaining = &remaining
+	character.LightDuration = &duration
+	if err := e.setLight(character, fmt.Sprintf("%s lit a %s", character.Name, kind)); err != nil {
+		return nil, err
+	}
+	return character, nil
+}
+
+// PutOutLight puts out a character's light. The light source is kept, so
+// it can be lit again.
+func (e *Engine) PutOutLight(characterID int64) (*model.Character, error) {
+	character, err := model.GetCharacter(e.DB, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character == nil {
+		return nil, fmt.Errorf("character %d not found", characterID)
+	}
+	if !character.Lit() {
+		return nil, fmt.Errorf("%s has no light burning", character.Name)
+	}
+
+	character.LightRemaining = nil
+	character.LightDuration = nil
+	if err := e.setLight(character, fmt.Sprintf("%s put out their %s", character.Name, *character.LightSource)); err != nil {
+		return nil, err
+	}
+	return character, nil
+}
+
+func (e *Engine) setLight(character *model.Character, description string) error {
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "light")
+	if err != nil {
+		return err
+	}
+	if err := j.track("characters", character.ID); err != nil {
+		return err
+	}
+	if err := model.SetCharacterLight(tx, character.ID, character.LightSource, character.LightRemaining,
+		character.LightDuration); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// lightDuration returns how many turns a kind of light burns.
+func (e *Engine) lightDuration(kind string) (int, error) {
+	cfg := e.config()
+	var duration int
+	switch kind {
+	case model.LightTorch:
+		duration = cfg.TorchDuration
+	case model.LightLantern:
+		duration = cfg.LanternDuration
+	case model.LightMagic:
+		duration = cfg.MagicLightDuration
+	default:
+		return 0, fmt.Errorf("unknown light source %q, expected %s, %s or %s", kind, model.LightTorch,
+			model.LightLantern, model.LightMagic)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("a %s has no duration set", kind)
+	}
+	return duration, nil
+}
+
+// tickLights burns down the lights of the active characters by the turns
+// that passed. A light warns once it has the campaign's light warning
+// turns or fewer left, and goes out when none are left.
+func (e *Engine) tickLights(j *journal, sessionID, turns int64) ([]Event, error) {
+	if turns <= 0 {
+		return nil, nil
+	}
+	characters, err := model.ListLitCharacters(j.tx)
+	if err != nil {
+		return nil, err
+	}
+
+	warning := int64(e.config().LightWarning)
+	var events []Event
+	for _, character := range characters {
+		before := int64(*character.LightRemaining)
+		remaining := max(before-turns, 0)
+		switch {
+		case remaining == 0:
+			events = append(events, LightOut{
+				SessionID:   sessionID,
+				CharacterID: character.ID,
+				Name:        character.Name,
+				Source:      *character.LightSource,
+			})
+		case remaining <= warning && before > warning:
+			events = append(events, LightLow{
+				SessionID:   sessionID,
+				CharacterID: character.ID,
+				Name:        character.Name,
+				Source:      *character.LightSource,
+				Remaining:   int(remaining),
+			})
+		}
+
+		if err := j.track("characters", character.ID); err != nil {
+			return nil, err
+		}
+		left := int(remaining)
+		if err := model.SetCharacterLight(j.tx, character.ID, character.LightSource, &left,
+			character.LightDuration); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
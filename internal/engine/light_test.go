@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Lights(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var events []Event
+	for _, eventType := range []string{"LightLow", "LightOut"} {
+		engine.EventBus.Subscribe(eventType, func(event Event) {
+			events = append(events, event)
+		})
+	}
+
+	lantern := "Lantern"
+	theron := &model.Character{Name: "Theron", IsActive: true}
+	mira := &model.Character{Name: "Mira", LightSource: &lantern, IsActive: true}
+	for _, character := range []*model.Character{theron, mira} {
+		if err := engine.AddCharacter(character); err != nil {
+			t.Fatalf("Failed to add character: %v", err)
+		}
+	}
+	if _, err := engine.LightSource(theron.ID, "candle"); err == nil {
+		t.Error("Expected an error lighting an unknown light source")
+	}
+	if _, err := engine.PutOutLight(theron.ID); err == nil {
+		t.Error("Expected an error putting out a light that is not lit")
+	}
+
+	torch, err := engine.LightSource(theron.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to light torch: %v", err)
+	}
+	if *torch.LightSource != model.LightTorch || *torch.LightRemaining != 10 || *torch.LightDuration != 10 {
+		t.Errorf("Expected a fresh 10 turn torch, got %+v", torch)
+	}
+	lit, err := engine.LightSource(mira.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to light lantern: %v", err)
+	}
+	if *lit.LightSource != model.LightLantern || *lit.LightRemaining != 24 {
+		t.Errorf("Expected Mira's own lantern to be lit for 24 turns, got %+v", lit)
+	}
+
+	if err := engine.Advance(session.ID, 8); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected no warnings with 2 turns left, got %+v", events)
+	}
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(events) != 1 || events[0] != (LightLow{SessionID: session.ID, CharacterID: theron.ID, Name: "Theron",
+		Source: model.LightTorch, Remaining: 1}) {
+		t.Fatalf("Expected the torch to warn with 1 turn left, got %+v", events)
+	}
+	if err := engine.Advance(session.ID, 3); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(events) != 2 || events[1] != (LightOut{SessionID: session.ID, CharacterID: theron.ID, Name: "Theron",
+		Source: model.LightTorch}) {
+		t.Fatalf("Expected the torch to go out, got %+v", events)
+	}
+
+	character, err := model.GetCharacter(engine.DB, theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to get character: %v", err)
+	}
+	if character.Lit() || *character.LightRemaining != 0 {
+		t.Errorf("Expected the torch to be burnt out, got %d turns left", *character.LightRemaining)
+	}
+	character, err = model.GetCharacter(engine.DB, mira.ID)
+	if err != nil {
+		t.Fatalf("Failed to get character: %v", err)
+	}
+	if *character.LightRemaining != 12 {
+		t.Errorf("Expected the lantern to have 12 turns left, got %d", *character.LightRemaining)
+	}
+
+	// Undoing the advance relights the torch.
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	character, err = model.GetCharacter(engine.DB, theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to get character: %v", err)
+	}
+	if !character.Lit() || *character.LightRemaining != 1 {
+		t.Errorf("Expected the torch back with 1 turn left, got %+v", character.LightRemaining)
+	}
+
+	if character, err = engine.PutOutLight(mira.ID); err != nil {
+		t.Fatalf("Failed to put out the lantern: %v", err)
+	}
+	if character.Lit() || *character.LightSource != model.LightLantern {
+		t.Errorf("Expected the lantern put out but kept, got %+v", character)
+	}
+}
+
+func TestEngine_Consumables(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var ranOut []ConsumableRanOut
+	engine.EventBus.Subscribe("ConsumableRanOut", func(event Event) {
+		ranOut = append(ranOut, event.(ConsumableRanOut))
+	})
+
+	theron := &model.Character{Name: "Theron", IsActive: true}
+	resting := &model.Character{Name: "Gareth", IsActive: false}
+	for _, character := range []*model.Character{theron, resting} {
+		if err := engine.AddCharacter(character); err != nil {
+			t.Fatalf("Failed to add character: %v", err)
+		}
+	}
+	if _, err := engine.AddConsumable(theron.ID, "Rations", 2, model.ConsumablePerDay); err != nil {
+		t.Fatalf("Failed to add rations: %v", err)
+	}
+	if _, err := engine.AddConsumable(resting.ID, "Rations", 2, model.ConsumablePerDay); err != nil {
+		t.Fatalf("Failed to add rations: %v", err)
+	}
+	if _, err := engine.AddConsumable(theron.ID, "Arrows", 20, ""); err != nil {
+		t.Fatalf("Failed to add arrows: %v", err)
+	}
+	arrows, err := engine.AddConsumable(theron.ID, "arrows", 4, "")
+	if err != nil {
+		t.Fatalf("Failed to add arrows: %v", err)
+	}
+	if arrows.Quantity != 24 || arrows.Usage != model.ConsumablePerUse {
+		t.Errorf("Expected 24 arrows used per use, got %+v", arrows)
+	}
+	if _, err := engine.AddConsumable(theron.ID, "Oil", 1, "weekly"); err == nil {
+		t.Error("Expected an error for an unknown usage")
+	}
+
+	if _, err := engine.UseConsumable(theron.ID, "Arrows", 25); err == nil {
+		t.Error("Expected an error using more arrows than Theron has")
+	}
+	if arrows, err = engine.UseConsumable(theron.ID, "Arrows", 3); err != nil {
+		t.Fatalf("Failed to use arrows: %v", err)
+	}
+	if arrows.Quantity != 21 {
+		t.Errorf("Expected 21 arrows left, got %d", arrows.Quantity)
+	}
+
+	// Play starts at 08:00, so the first day ends 96 turns of 10 minutes in.
+	if err := engine.Advance(session.ID, 95); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	rations, err := model.GetConsumableByName(engine.DB, theron.ID, "rations")
+	if err != nil {
+		t.Fatalf("Failed to get rations: %v", err)
+	}
+	if rations.Quantity != 2 {
+		t.Errorf("Expected no rations eaten within the day, got %d left", rations.Quantity)
+	}
+	if err := engine.Advance(session.ID, 2*144); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if rations, err = model.GetConsumableByName(engine.DB, theron.ID, "rations"); err != nil {
+		t.Fatalf("Failed to get rations: %v", err)
+	}
+	if rations.Quantity != 0 {
+		t.Errorf("Expected the rations eaten, got %d left", rations.Quantity)
+	}
+	if len(ranOut) != 1 || ranOut[0].Name != "Theron" || ranOut[0].Consumable != "Rations" {
+		t.Errorf("Expected Theron to run out of rations, got %+v", ranOut)
+	}
+	if resting, err := model.GetConsumableByName(engine.DB, resting.ID, "rations"); err != nil || resting.Quantity != 2 {
+		t.Errorf("Expected an inactive character to keep their rations, got %+v, %v", resting, err)
+	}
+
+	// Removing a character takes their consumables with them, until undone.
+	if err := engine.RemoveCharacter(theron.ID); err != nil {
+		t.Fatalf("Failed to remove character: %v", err)
+	}
+	if consumables, err := model.ListConsumables(engine.DB, theron.ID); err != nil || len(consumables) != 0 {
+		t.Errorf("Expected Theron's consumables removed, got %+v, %v", consumables, err)
+	}
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if consumables, err := model.ListConsumables(engine.DB, theron.ID); err != nil || len(consumables) != 2 {
+		t.Errorf("Expected Theron's consumables back, got %+v, %v", consumables, err)
+	}
+}
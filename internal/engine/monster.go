@@ -0,0 +1,227 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// FindMonsters reads a request such as "3 goblins" or "ogre" against the
+// monster catalog, returning the stat block and how many to add. The
+// monster is nil when nothing in the catalog matches.
+func (e *Engine) FindMonsters(request string) (*model.Monster, int, error) {
+	count, name := splitCount(request)
+	if name == "" {
+		return nil, 0, fmt.Errorf("monsters need a name")
+	}
+	monster, err := e.catalogMonster(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	return monster, count, nil
+}
+
+// catalogMonster looks a monster up by name, trying the singular of a
+// plural name too.
+func (e *Engine) catalogMonster(name string) (*model.Monster, error) {
+	candidates := []string{name, singular(name)}
+	if stem, found := strings.CutSuffix(name, "es"); found {
+		candidates = append(candidates, stem)
+	}
+	for _, candidate := range candidates {
+		monster, err := model.GetMonsterByName(e.DB, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if monster != nil {
+			return monster, nil
+		}
+	}
+	return nil, nil
+}
+
+// AddMonsters adds count of a catalog monster to a running encounter,
+// each with hit points rolled from the stat block's hit dice. Several are
+// numbered after any of the same monster already in the encounter:
+// Goblin 1, Goblin 2 and so on.
+func (e *Engine) AddMonsters(encounterID int64, monster *model.Monster, count, initiative int, side string) ([]model.InitiativeOrder, error) {
+	if count < 1 || count > maxWanderingMonsters {
+		return nil, fmt.Errorf("monster count must be between 1 and %d, got %d", maxWanderingMonsters, count)
+	}
+	encounter, err := e.runningEncounter(encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if side == "" {
+		side = model.SideMonsters
+	}
+	if roll, rolled, err := e.sideInitiative(encounter, side); err != nil {
+		return nil, err
+	} else if rolled {
+		initiative = roll
+	}
+
+	combatants, err := e.listCombatants(e.DB, encounter.ID)
+	if err != nil {
+		return nil, err
+	}
+	existing := 0
+	for _, combatant := range combatants {
+		if combatant.MonsterID != nil && *combatant.MonsterID == monster.ID {
+			existing++
+		}
+	}
+
+	added, err := monsterCombatants(encounter.ID, monster, count, existing, e.rng())
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "add_combatant")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(added))
+	for i := range added {
+		added[i].Initiative = initiative
+		added[i].Side = side
+		if err := model.CreateCombatant(tx, &added[i]); err != nil {
+			return nil, err
+		}
+		j.created("initiative_order", added[i].ID)
+		names[i] = *added[i].CharacterName
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Added %s to the encounter", strings.Join(names, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		for _, combatant := range added {
+			e.EventBus.Emit(CombatantAdded{
+				SessionID:   encounter.SessionID,
+				EncounterID: encounter.ID,
+				CombatantID: combatant.ID,
+				Name:        *combatant.CharacterName,
+			})
+		}
+	}
+	return added, nil
+}
+
+// monsterCombatants builds count combatants for a catalog monster with
+// rolled hit points, numbered after existing ones already in play.
+func monsterCombatants(encounterID int64, monster *model.Monster, count, existing int, rng *rand.Rand) ([]model.InitiativeOrder, error) {
+	bonus := MonsterAttackBonus(monster.HitDice)
+	combatants := make([]model.InitiativeOrder, count)
+	for i := range combatants {
+		hp, err := RollHitPoints(monster.HitDice, rng)
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll hit points for %s: %w", monster.Name, err)
+		}
+		name := monster.Name
+		if count > 1 || existing > 0 {
+			name = fmt.Sprintf("%s %d", monster.Name, existing+i+1)
+		}
+		combatants[i] = model.InitiativeOrder{
+			EncounterID:   encounterID,
+			CharacterName: &name,
+			HPCurrent:     &hp,
+			HPMax:         &hp,
+			IsActive:      true,
+			Side:          model.SideMonsters,
+			MonsterID:     &monster.ID,
+			ArmorClass:    &monster.ArmorClass,
+			AttackBonus:   &bonus,
+			Damage:        monster.Damage,
+		}
+	}
+	return combatants, nil
+}
+
+// RollHitPoints rolls hit points for a monster's hit dice. A monster
+// always has at least 1 hit point.
+func RollHitPoints(hitDice string, rng *rand.Rand) (int, error) {
+	expr, err := HitDiceExpr(hitDice)
+	if err != nil {
+		return 0, err
+	}
+	hp, _, err := dice.Roll(expr, rng)
+	if err != nil {
+		return 0, err
+	}
+	return max(hp, 1), nil
+}
+
+// HitDiceExpr turns OSR hit dice into a dice expression: "2" is 2d8,
+// "1+1" is 1d8+1, "1-1" is 1d8-1 and "1/2" is 1d4. Asterisks marking
+// special abilities are ignored, and a dice expression is used as it is.
+func HitDiceExpr(hitDice string) (string, error) {
+	hd := strings.TrimRight(strings.TrimSpace(hitDice), "*")
+	if hd == "1/2" || hd == "½" {
+		return "1d4", nil
+	}
+
+	expr := hd
+	if !strings.ContainsAny(hd, "dD") {
+		dice, modifier := hd, ""
+		if i := strings.IndexAny(hd, "+-"); i > 0 {
+			dice, modifier = hd[:i], hd[i:]
+		}
+		if _, err := strconv.Atoi(dice); err != nil {
+			return "", fmt.Errorf("invalid hit dice %q", hitDice)
+		}
+		expr = dice + "d8" + modifier
+	}
+	if _, err := dice.Parse(expr); err != nil {
+		return "", fmt.Errorf("invalid hit dice %q: %w", hitDice, err)
+	}
+	return expr, nil
+}
+
+// MonsterAttackBonus is a monster's attack bonus by hit dice, from the
+// classic monster attack table: THAC0 19 (+0) up to 1 HD, improving by 1
+// per hit die up to 7 HD and by 1 per 2 hit dice after that, to at most
+// THAC0 5 (+14). Hit dice with a bonus, such as "2+1", count as more than
+// their number.
+func MonsterAttackBonus(hitDice string) int {
+	hd := strings.TrimRight(strings.TrimSpace(hitDice), "*")
+	count := hd
+	if i := strings.IndexAny(hd, "+-dD"); i > 0 {
+		count = hd[:i]
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return 0
+	}
+
+	level := float64(n)
+	if i := strings.Index(hd, "+"); i > 0 && !strings.ContainsAny(hd, "dD") {
+		level += 0.5
+	}
+	switch {
+	case level <= 1:
+		return 0
+	case level <= 7:
+		return int(math.Ceil(level)) - 1
+	default:
+		return min(6+int(math.Ceil((level-7)/2)), 14)
+	}
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func createTestMonster(t *testing.T, engine *Engine, monster *model.Monster) {
+	t.Helper()
+
+	tx, err := engine.DB.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if err := model.CreateMonster(tx, monster); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create monster: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+}
+
+func TestEngine_AddMonsters(t *testing.T) {
+	engine, session := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(1))
+	createTestMonster(t, engine, &model.Monster{Name: "Goblin", ArmorClass: 6, HitDice: "1-1", Movement: 60, Morale: 7, XP: 5})
+
+	var added []CombatantAdded
+	engine.EventBus.Subscribe("CombatantAdded", func(event Event) {
+		added = append(added, event.(CombatantAdded))
+	})
+
+	monster, count, err := engine.FindMonsters("3 goblins")
+	if err != nil || monster == nil || monster.Name != "Goblin" || count != 3 {
+		t.Fatalf("Expected 3 goblins from the catalog, got %+v x%d, %v", monster, count, err)
+	}
+	if missing, _, err := engine.FindMonsters("2 dragons"); err != nil || missing != nil {
+		t.Fatalf("Expected no dragons in the catalog, got %+v, %v", missing, err)
+	}
+
+	encounter, err := engine.StartEncounter(session.ID, "Ambush")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	goblins, err := engine.AddMonsters(encounter.ID, monster, count, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to add goblins: %v", err)
+	}
+	if _, err := engine.AddMonsters(encounter.ID, monster, 1, 2, ""); err != nil {
+		t.Fatalf("Failed to add another goblin: %v", err)
+	}
+
+	combatants, err := model.ListCombatants(engine.DB, encounter.ID)
+	if err != nil {
+		t.Fatalf("Failed to list combatants: %v", err)
+	}
+	var names []string
+	for _, combatant := range combatants {
+		names = append(names, combatant.Name)
+		if combatant.MonsterID == nil || *combatant.MonsterID != monster.ID {
+			t.Errorf("Expected %s to be linked to the goblin stat block", combatant.Name)
+		}
+		if combatant.HPMax == nil || *combatant.HPMax < 1 || *combatant.HPMax > 7 || *combatant.HPCurrent != *combatant.HPMax {
+			t.Errorf("Expected %s to have 1-7 HP, got %v", combatant.Name, combatant.HPMax)
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"Goblin 1", "Goblin 2", "Goblin 3", "Goblin 4"}) {
+		t.Errorf("Unexpected combatants: %v", names)
+	}
+	if len(goblins) != 3 || len(added) != 4 {
+		t.Errorf("Expected 3 goblins then 1 added, got %d and %d events", len(goblins), len(added))
+	}
+
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if combatants, _ := model.ListCombatants(engine.DB, encounter.ID); len(combatants) != 0 {
+		t.Errorf("Expected undo to take every goblin back out, got %d", len(combatants))
+	}
+	if _, err := engine.AddMonsters(encounter.ID, monster, 0, 0, ""); err == nil {
+		t.Error("Expected an error adding no monsters")
+	}
+}
+
+func TestHitDiceExpr(t *testing.T) {
+	tests := []struct {
+		hitDice  string
+		expected string
+	}{
+		{"1", "1d8"},
+		{"2+1", "2d8+1"},
+		{"1-1", "1d8-1"},
+		{"1/2", "1d4"},
+		{"3**", "3d8"},
+		{"2d6", "2d6"},
+	}
+	for _, tt := range tests {
+		if got, err := HitDiceExpr(tt.hitDice); err != nil || got != tt.expected {
+			t.Errorf("HitDiceExpr(%q) = %q, %v, expected %q", tt.hitDice, got, err, tt.expected)
+		}
+	}
+	if _, err := HitDiceExpr("lots"); err == nil {
+		t.Error("Expected an error for invalid hit dice")
+	}
+}
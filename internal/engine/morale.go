@@ -0,0 +1,206 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Morale triggers: what called for a morale check.
+const (
+	MoraleFirstDeath   = "first death"
+	MoraleHalfStrength = "half strength"
+	MoraleCalled       = "called"
+)
+
+// ConditionFleeing is put on combatants that fail a morale check.
+const ConditionFleeing = "fleeing"
+
+// MoraleRoll is one combatant's 2d6 morale check. A roll over the
+// combatant's morale score fails, and the combatant flees.
+type MoraleRoll struct {
+	CombatantID int64
+	Name        string
+	Morale      int
+	Roll        int
+	Fled        bool
+}
+
+func (r MoraleRoll) String() string {
+	verb := "stands"
+	if r.Fled {
+		verb = "flees"
+	}
+	return fmt.Sprintf("%s %s (%d vs ML %d)", r.Name, verb, r.Roll, r.Morale)
+}
+
+// moraleChecker is a combatant that checks morale, with its score.
+type moraleChecker struct {
+	combatant model.Combatant
+	morale    int
+}
+
+// CheckMorale rolls morale for every combatant on a side that checks it,
+// when the referee calls for a check. Combatants check morale when they
+// are in the turn order, alive, spawned from a stat block and not already
+// fleeing.
+func (e *Engine) CheckMorale(encounterID int64, side string) (*MoraleChecked, error) {
+	encounter, err := e.runningEncounter(encounterID)
+	if err != nil {
+		return nil, err
+	}
+	combatants, err := e.listCombatants(e.DB, encounter.ID)
+	if err != nil {
+		return nil, err
+	}
+	checkers, err := e.moraleCheckers(combatants, side, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkers) == 0 {
+		return nil, fmt.Errorf("no one on %s checks morale", side)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &encounter.SessionID, "morale")
+	if err != nil {
+		return nil, err
+	}
+	rolls, err := rollMorale(j, checkers, e.rng())
+	if err != nil {
+		return nil, err
+	}
+
+	checked := &MoraleChecked{
+		SessionID:   encounter.SessionID,
+		EncounterID: encounter.ID,
+		Side:        side,
+		Trigger:     MoraleCalled,
+		Rolls:       rolls,
+	}
+	entry, err := j.commit(fmt.Sprintf("Morale check for %s: %s", side, checked.Summary()))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(*checked)
+	}
+	return checked, nil
+}
+
+// moraleTrigger reports what calls for a morale check when a combatant
+// falls: the first death on its side, or the side dropping to half
+// strength. It is empty when nothing does, or no one is left standing.
+func moraleTrigger(combatants []model.Combatant, fallen *model.Combatant) string {
+	total, dead := 0, 0
+	for _, combatant := range combatants {
+		if combatant.Side != fallen.Side {
+			continue
+		}
+		total++
+		if combatant.Dead() && combatant.ID != fallen.ID {
+			dead++
+		}
+	}
+
+	standing := total - dead - 1
+	switch {
+	case standing <= 0:
+		return ""
+	case dead == 0:
+		return MoraleFirstDeath
+	case (standing+1)*2 > total && standing*2 <= total:
+		return MoraleHalfStrength
+	}
+	return ""
+}
+
+// moraleCheckers returns the combatants on a side that check morale,
+// leaving out the one with ID exclude.
+func (e *Engine) moraleCheckers(combatants []model.Combatant, side string, exclude int64) ([]moraleChecker, error) {
+	scores := make(map[int64]int)
+	var checkers []moraleChecker
+	for _, combatant := range combatants {
+		if combatant.Side != side || combatant.ID == exclude || !combatant.CanAct() || combatant.MonsterID == nil {
+			continue
+		}
+		conditions, err := model.DecodeConditions(combatant.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		if len(removeCondition(conditions, ConditionFleeing)) < len(conditions) {
+			continue
+		}
+
+		score, exists := scores[*combatant.MonsterID]
+		if !exists {
+			monster, err := model.GetMonster(e.DB, *combatant.MonsterID)
+			if err != nil {
+				return nil, err
+			}
+			if monster == nil {
+				continue
+			}
+			score = monster.Morale
+			scores[*combatant.MonsterID] = score
+		}
+		checkers = append(checkers, moraleChecker{combatant: combatant, morale: score})
+	}
+	return checkers, nil
+}
+
+// rollMorale rolls 2d6 for each checker and puts those that fail to
+// flight.
+func rollMorale(j *journal, checkers []moraleChecker, rng *rand.Rand) ([]MoraleRoll, error) {
+	rolls := make([]MoraleRoll, len(checkers))
+	for i, checker := range checkers {
+		roll, _, err := dice.Roll("2d6", rng)
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll morale: %w", err)
+		}
+		rolls[i] = MoraleRoll{
+			CombatantID: checker.combatant.ID,
+			Name:        checker.combatant.Name,
+			Morale:      checker.morale,
+			Roll:        roll,
+			Fled:        roll > checker.morale,
+		}
+		if !rolls[i].Fled {
+			continue
+		}
+
+		conditions, err := model.DecodeConditions(checker.combatant.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		if err := j.track("initiative_order", checker.combatant.ID); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, model.Condition{Name: ConditionFleeing})
+		if err := model.SetCombatantConditions(j.tx, checker.combatant.ID, conditions); err != nil {
+			return nil, err
+		}
+	}
+	return rolls, nil
+}
+
+func describeMorale(rolls []MoraleRoll) string {
+	described := make([]string, len(rolls))
+	for i, roll := range rolls {
+		described[i] = roll.String()
+	}
+	return strings.Join(described, ", ")
+}
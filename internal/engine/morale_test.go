@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Morale(t *testing.T) {
+	engine, session := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(1))
+	goblin := &model.Monster{Name: "Goblin", ArmorClass: 6, HitDice: "1", Movement: 60, Morale: 7, XP: 5}
+	createTestMonster(t, engine, goblin)
+
+	var checks []MoraleChecked
+	engine.EventBus.Subscribe("MoraleChecked", func(event Event) {
+		checks = append(checks, event.(MoraleChecked))
+	})
+
+	encounter, err := engine.StartEncounter(session.ID, "Ambush")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	goblins, err := engine.AddMonsters(encounter.ID, goblin, 4, 0, "")
+	if err != nil {
+		t.Fatalf("Failed to add goblins: %v", err)
+	}
+	name, hp := "Fighter", 8
+	fighter := &model.InitiativeOrder{EncounterID: encounter.ID, CharacterName: &name, HPCurrent: &hp, HPMax: &hp, Side: model.SideParty}
+	if err := engine.AddCombatant(fighter); err != nil {
+		t.Fatalf("Failed to add fighter: %v", err)
+	}
+
+	if err := engine.Damage(goblins[0].ID, 20); err != nil {
+		t.Fatalf("Failed to damage goblin: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Trigger != MoraleFirstDeath || checks[0].Side != model.SideMonsters || len(checks[0].Rolls) != 3 {
+		t.Fatalf("Expected the other goblins to check morale on the first death, got %+v", checks)
+	}
+	for _, roll := range checks[0].Rolls {
+		if roll.Morale != 7 || roll.Fled != (roll.Roll > 7) {
+			t.Errorf("Unexpected morale roll: %+v", roll)
+		}
+		combatant, err := model.FindCombatant(engine.DB, roll.CombatantID)
+		if err != nil {
+			t.Fatalf("Failed to find combatant: %v", err)
+		}
+		if fleeing := model.FormatConditions(combatant.Conditions) == ConditionFleeing; fleeing != roll.Fled {
+			t.Errorf("Expected %s fleeing to be %v, got conditions %s", roll.Name, roll.Fled, combatant.Conditions)
+		}
+	}
+
+	// Those left standing check again when the side drops to half strength.
+	standing := 0
+	for _, roll := range checks[0].Rolls {
+		if !roll.Fled && roll.CombatantID != goblins[1].ID {
+			standing++
+		}
+	}
+	if err := engine.Damage(goblins[1].ID, 20); err != nil {
+		t.Fatalf("Failed to damage goblin: %v", err)
+	}
+	if len(checks) != 2 || checks[1].Trigger != MoraleHalfStrength || len(checks[1].Rolls) != standing {
+		t.Fatalf("Expected %d goblins to check morale at half strength, got %+v", standing, checks)
+	}
+
+	// The party has no stat blocks, so no one rolls for it.
+	before := len(checks)
+	if err := engine.Damage(fighter.ID, 20); err != nil {
+		t.Fatalf("Failed to damage fighter: %v", err)
+	}
+	if len(checks) != before {
+		t.Errorf("Expected no morale check for the party, got %+v", checks[before:])
+	}
+	if _, err := engine.CheckMorale(encounter.ID, model.SideParty); err == nil {
+		t.Error("Expected an error checking morale for the party")
+	}
+
+	// Undo takes back the damage and the flight it caused.
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	combatants, err := model.ListCombatants(engine.DB, encounter.ID)
+	if err != nil {
+		t.Fatalf("Failed to list combatants: %v", err)
+	}
+	for _, combatant := range combatants {
+		if combatant.Dead() || combatant.Conditions != "[]" {
+			t.Errorf("Expected undo to restore %s, got %+v", combatant.Name, combatant)
+		}
+	}
+
+	checked, err := engine.CheckMorale(encounter.ID, model.SideMonsters)
+	if err != nil {
+		t.Fatalf("Failed to check morale: %v", err)
+	}
+	if checked.Trigger != MoraleCalled || len(checked.Rolls) != 4 || len(checks) != before+1 {
+		t.Errorf("Expected all four goblins to check morale, got %+v", checked)
+	}
+}
+
+func TestMoraleTrigger(t *testing.T) {
+	hp := func(n int) *int { return &n }
+	side := func(dead ...bool) []model.Combatant {
+		combatants := make([]model.Combatant, len(dead))
+		for i, isDead := range dead {
+			combatants[i] = model.Combatant{ID: int64(i + 1), Side: model.SideMonsters, HPCurrent: hp(4)}
+			if isDead {
+				combatants[i].HPCurrent = hp(0)
+			}
+		}
+		return combatants
+	}
+
+	tests := []struct {
+		name       string
+		combatants []model.Combatant
+		expected   string
+	}{
+		{"first death", side(false, false, false, false), MoraleFirstDeath},
+		{"half strength", side(false, true, false, false), MoraleHalfStrength},
+		{"already at half", side(false, true, true, true, false, false), ""},
+		{"last one standing", side(false, true, true, false), ""},
+		{"nobody left", side(false), ""},
+	}
+	for _, tt := range tests {
+		if got := moraleTrigger(tt.combatants, &tt.combatants[0]); got != tt.expected {
+			t.Errorf("%s: moraleTrigger = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestEngine_RollReaction(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(1))
+
+	tx, err := engine.DB.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	npc := &model.NPC{Name: "Thorg", Status: model.NPCNeutral}
+	if err := model.CreateNPC(tx, npc); err != nil {
+		t.Fatalf("Failed to create npc: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	rolled, err := engine.RollReaction(npc.ID, -10, false)
+	if err != nil {
+		t.Fatalf("Failed to roll reaction: %v", err)
+	}
+	if rolled.Reaction != "attacks" || rolled.Status != model.NPCHostile || rolled.Applied {
+		t.Errorf("Expected an unapplied attack, got %+v", rolled)
+	}
+	if stored, _ := model.GetNPC(engine.DB, npc.ID); stored.Status != model.NPCNeutral {
+		t.Errorf("Expected the status to be left alone, got %s", stored.Status)
+	}
+
+	rolled, err = engine.RollReaction(npc.ID, 10, true)
+	if err != nil {
+		t.Fatalf("Failed to roll reaction: %v", err)
+	}
+	if rolled.Reaction != "friendly" || !rolled.Applied || rolled.OldStatus != model.NPCNeutral {
+		t.Errorf("Expected an applied friendly reaction, got %+v", rolled)
+	}
+	if stored, _ := model.GetNPC(engine.DB, npc.ID); stored.Status != model.NPCAlly {
+		t.Errorf("Expected Thorg to become an ally, got %s", stored.Status)
+	}
+
+	if _, err := engine.RollReaction(999, 0, true); err == nil {
+		t.Error("Expected an error for a missing npc")
+	}
+}
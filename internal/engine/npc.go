@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// UpdateNPC saves an edited NPC. NPCs belong to the campaign rather than a
+// session, so the edit can be undone from any session.
+func (e *Engine) UpdateNPC(npc *model.NPC) error {
+	return e.saveNPC(npc, "Edited "+npc.Name)
+}
+
+// RollReaction makes a 2d6 reaction roll, plus a modifier such as the
+// speaker's Charisma bonus, for an NPC meeting the party. With apply set,
+// the NPC's status becomes the one the reaction calls for.
+func (e *Engine) RollReaction(npcID int64, modifier int, apply bool) (*ReactionRolled, error) {
+	npc, err := model.GetNPC(e.DB, npcID)
+	if err != nil {
+		return nil, err
+	}
+	if npc == nil {
+		return nil, fmt.Errorf("npc %d not found", npcID)
+	}
+
+	roll, _, err := dice.Roll("2d6", e.rng())
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll reaction: %w", err)
+	}
+	total := roll + modifier
+	reaction, status := reactionResult(total)
+
+	rolled := &ReactionRolled{
+		NPCID:     npc.ID,
+		Name:      npc.Name,
+		Roll:      roll,
+		Modifier:  modifier,
+		Total:     total,
+		Reaction:  reaction,
+		Status:    status,
+		OldStatus: npc.Status,
+	}
+	if apply && npc.Status != status {
+		npc.Status = status
+		description := fmt.Sprintf("%s reacts: %s (%d), now %s", npc.Name, reaction, total, status)
+		if err := e.saveNPC(npc, description); err != nil {
+			return nil, err
+		}
+		rolled.Applied = true
+	}
+
+	if e.EventBus != nil {
+		e.EventBus.Emit(*rolled)
+	}
+	return rolled, nil
+}
+
+// reactionResult reads a reaction total off the 2d6 reaction table, giving
+// the reaction and the NPC status it leads to.
+func reactionResult(total int) (string, string) {
+	switch {
+	case total <= 2:
+		return "attacks", model.NPCHostile
+	case total <= 5:
+		return "hostile", model.NPCHostile
+	case total <= 8:
+		return "uncertain", model.NPCNeutral
+	case total <= 11:
+		return "indifferent", model.NPCNeutral
+	default:
+		return "friendly", model.NPCAlly
+	}
+}
+
+func (e *Engine) saveNPC(npc *model.NPC, description string) error {
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, nil, "npc")
+	if err != nil {
+		return err
+	}
+	if err := j.track("npcs", npc.ID); err != nil {
+		return err
+	}
+	if err := model.UpdateNPC(tx, npc); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// Schedule queues a one-off event to trigger in the given number of turns
+// from the session's current turn.
+func (e *Engine) Schedule(sessionID int64, eventType, description string, inTurns int64) (*model.TimeEvent, error) {
+	return e.schedule(sessionID, eventType, description, inTurns, nil)
+}
+
+// ScheduleRecurring queues an event that triggers every given number of
+// turns, starting that many turns from now, e.g. a wandering monster check
+// every 2 turns.
+func (e *Engine) ScheduleRecurring(sessionID int64, eventType, description string, every int64) (*model.TimeEvent, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("recurring events need a positive interval, got %d", every)
+	}
+	return e.schedule(sessionID, eventType, description, every, &every)
+}
+
+// CancelEvent removes a scheduled event.
+func (e *Engine) CancelEvent(eventID int64) error {
+	event, err := model.GetTimeEvent(e.DB, eventID)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return fmt.Errorf("time event %d not found", eventID)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &event.SessionID, "cancel_event")
+	if err != nil {
+		return err
+	}
+	if err := j.track("time_events", eventID); err != nil {
+		return err
+	}
+	if err := model.DeleteTimeEvent(tx, eventID); err != nil {
+		return err
+	}
+	entry, err := j.commit("Cancelled " + timeEventName(event))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// timeEventName describes an event by its description, or its type when
+// it has none.
+func timeEventName(event *model.TimeEvent) string {
+	if event.Description != nil && *event.Description != "" {
+		return *event.Description
+	}
+	return event.EventType
+}
+
+func (e *Engine) schedule(sessionID int64, eventType, description string, inTurns int64, repeatEvery *int64) (*model.TimeEvent, error) {
+	if inTurns < 0 {
+		return nil, fmt.Errorf("cannot schedule an event %d turns in the past", -inTurns)
+	}
+
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionID)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	event := &model.TimeEvent{
+		SessionID:   sessionID,
+		TriggerTurn: session.CurrentTurn + inTurns,
+		EventType:   eventType,
+		RepeatEvery: repeatEvery,
+	}
+	if description != "" {
+		event.Description = &description
+	}
+
+	if err := model.CreateTimeEvent(tx, event); err != nil {
+		return nil, fmt.Errorf("failed to schedule event: %w", err)
+	}
+	j.created("time_events", event.ID)
+
+	entry, err := j.commit(fmt.Sprintf("Scheduled %s at turn %d", timeEventName(event), event.TriggerTurn))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return event, nil
+}
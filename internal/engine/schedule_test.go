@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func newTestEngine(t *testing.T) (*Engine, *model.Session) {
+	t.Helper()
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	return &Engine{DB: database, EventBus: NewEventBus()}, session
+}
+
+func TestEngine_TimeEvents(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var triggered []EventTriggered
+	engine.EventBus.Subscribe("EventTriggered", func(event Event) {
+		triggered = append(triggered, event.(EventTriggered))
+	})
+
+	spell, err := engine.Schedule(session.ID, model.TimeEventSpellEnd, "Bless ends", 3)
+	if err != nil {
+		t.Fatalf("Failed to schedule spell end: %v", err)
+	}
+	if spell.TriggerTurn != 3 || spell.EventType != model.TimeEventSpellEnd {
+		t.Fatalf("Expected the spell to end at turn 3, got %+v", spell)
+	}
+
+	wandering, err := engine.ScheduleRecurring(session.ID, model.TimeEventWanderingCheck, "Wandering monster check", 2)
+	if err != nil {
+		t.Fatalf("Failed to schedule wandering check: %v", err)
+	}
+
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(triggered) != 0 {
+		t.Fatalf("Expected no events at turn 1, got %+v", triggered)
+	}
+
+	if err := engine.Advance(session.ID, 4); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+
+	expected := []struct {
+		eventType string
+		turn      int64
+	}{
+		{model.TimeEventWanderingCheck, 2},
+		{model.TimeEventSpellEnd, 3},
+		{model.TimeEventWanderingCheck, 4},
+	}
+	if len(triggered) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), triggered)
+	}
+	for i, want := range expected {
+		if triggered[i].EventType != want.eventType || triggered[i].TriggerTurn != want.turn {
+			t.Errorf("Event %d: expected %s at turn %d, got %s at turn %d",
+				i, want.eventType, want.turn, triggered[i].EventType, triggered[i].TriggerTurn)
+		}
+	}
+	if triggered[1].Description != "Bless ends" || triggered[1].Recurring {
+		t.Errorf("Unexpected spell event: %+v", triggered[1])
+	}
+
+	spell, err = model.GetTimeEvent(engine.DB, spell.ID)
+	if err != nil {
+		t.Fatalf("Failed to get spell event: %v", err)
+	}
+	if !spell.Handled {
+		t.Error("Expected the spell end to be handled")
+	}
+
+	wandering, err = model.GetTimeEvent(engine.DB, wandering.ID)
+	if err != nil {
+		t.Fatalf("Failed to get wandering event: %v", err)
+	}
+	if wandering.Handled || wandering.TriggerTurn != 6 {
+		t.Errorf("Expected wandering check to move to turn 6, got %+v", wandering)
+	}
+
+	pending, err := model.ListPendingTimeEvents(engine.DB, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to list pending events: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != wandering.ID {
+		t.Errorf("Expected only the wandering check to be pending, got %+v", pending)
+	}
+
+	if err := engine.CancelEvent(wandering.ID); err != nil {
+		t.Fatalf("Failed to cancel event: %v", err)
+	}
+	triggered = nil
+	if err := engine.Advance(session.ID, 10); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(triggered) != 0 {
+		t.Errorf("Expected no events after cancelling, got %+v", triggered)
+	}
+}
+
+func TestEngine_ScheduleErrors(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	if _, err := engine.ScheduleRecurring(session.ID, model.TimeEventWanderingCheck, "", 0); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+	if _, err := engine.Schedule(session.ID, model.TimeEventSpellEnd, "", -1); err == nil {
+		t.Error("Expected an error for an event in the past")
+	}
+	if _, err := engine.Schedule(999, model.TimeEventSpellEnd, "", 1); err == nil {
+		t.Error("Expected an error for a missing session")
+	}
+}
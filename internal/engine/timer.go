@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// StartTimer starts a real-world timer. The timer is stored with its start
+// time, so it keeps running when every spells process exits.
+func (e *Engine) StartTimer(label string, d time.Duration, sessionID *int64) (*model.Timer, error) {
+	if d < time.Second {
+		return nil, fmt.Errorf("timers must run for at least a second, got %v", d)
+	}
+	if label == "" {
+		label = "timer"
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	timer := &model.Timer{
+		Label:           label,
+		DurationSeconds: int64(d / time.Second),
+		SessionID:       sessionID,
+	}
+	if err := model.CreateTimer(tx, timer); err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if e.EventBus != nil {
+		e.EventBus.Emit(TimerStarted{TimerID: timer.ID, Label: timer.Label, ExpiresAt: timer.ExpiresAt()})
+	}
+	return timer, nil
+}
+
+// CompleteTimer marks a running timer as done.
+func (e *Engine) CompleteTimer(id int64) error {
+	return e.finishTimer(id, model.TimerCompleted)
+}
+
+// CancelTimer stops a running timer.
+func (e *Engine) CancelTimer(id int64) error {
+	return e.finishTimer(id, model.TimerCancelled)
+}
+
+func (e *Engine) finishTimer(id int64, status string) error {
+	timer, err := model.GetTimer(e.DB, id)
+	if err != nil {
+		return err
+	}
+	if timer == nil {
+		return fmt.Errorf("timer %d not found", id)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := model.FinishTimer(tx, id, status); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if e.EventBus != nil {
+		e.EventBus.Emit(TimerFinished{TimerID: id, Label: timer.Label, Status: status})
+	}
+	return nil
+}
+
+// TimerWatcher polls the database for expired timers, so a process notices
+// timers started by any other process on the same campaign.
+type TimerWatcher struct {
+	engine   *Engine
+	mu       sync.Mutex
+	notified map[int64]bool
+	done     chan bool
+}
+
+func NewTimerWatcher(engine *Engine) *TimerWatcher {
+	return &TimerWatcher{
+		engine:   engine,
+		notified: make(map[int64]bool),
+		done:     make(chan bool),
+	}
+}
+
+// Check emits a TimerExpired event for every running timer that has run
+// out by now and has not been reported by this watcher yet.
+func (w *TimerWatcher) Check(now time.Time) ([]TimerExpired, error) {
+	timers, err := model.ListRunningTimers(w.engine.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	var expired []TimerExpired
+	for _, timer := range timers {
+		if !timer.Expired(now) || w.notified[timer.ID] {
+			continue
+		}
+		w.notified[timer.ID] = true
+		expired = append(expired, TimerExpired{TimerID: timer.ID, Label: timer.Label, ExpiresAt: timer.ExpiresAt()})
+	}
+	w.mu.Unlock()
+
+	if w.engine.EventBus != nil {
+		for _, event := range expired {
+			w.engine.EventBus.Emit(event)
+		}
+	}
+	return expired, nil
+}
+
+// Start checks for expired timers straight away and then every interval
+// until Stop is called.
+func (w *TimerWatcher) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := w.Check(time.Now()); err != nil {
+				log.Printf("Timer watcher error: %v", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+func (w *TimerWatcher) Stop() {
+	close(w.done)
+}
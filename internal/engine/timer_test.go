@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_Timers(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	var started []TimerStarted
+	var finished []TimerFinished
+	engine.EventBus.Subscribe("TimerStarted", func(event Event) {
+		started = append(started, event.(TimerStarted))
+	})
+	engine.EventBus.Subscribe("TimerFinished", func(event Event) {
+		finished = append(finished, event.(TimerFinished))
+	})
+
+	pizza, err := engine.StartTimer("pizza", 10*time.Minute, &session.ID)
+	if err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+	brk, err := engine.StartTimer("", 90*time.Second, nil)
+	if err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+	if brk.Label != "timer" || brk.DurationSeconds != 90 {
+		t.Errorf("Unexpected default timer: %+v", brk)
+	}
+	if len(started) != 2 || !started[0].ExpiresAt.Equal(pizza.StartedAt.Add(10*time.Minute)) {
+		t.Errorf("Expected two TimerStarted events, got %+v", started)
+	}
+
+	if _, err := engine.StartTimer("instant", 0, nil); err == nil {
+		t.Error("Expected an error for a zero-length timer")
+	}
+
+	if err := engine.CancelTimer(brk.ID); err != nil {
+		t.Fatalf("Failed to cancel timer: %v", err)
+	}
+	if err := engine.CompleteTimer(brk.ID); err == nil {
+		t.Error("Expected an error completing a cancelled timer")
+	}
+	if err := engine.CompleteTimer(999); err == nil {
+		t.Error("Expected an error for a missing timer")
+	}
+	if len(finished) != 1 || finished[0].Status != model.TimerCancelled {
+		t.Errorf("Expected one cancelled event, got %+v", finished)
+	}
+}
+
+func TestTimerWatcher(t *testing.T) {
+	engine, _ := newTestEngine(t)
+
+	var expired []TimerExpired
+	engine.EventBus.Subscribe("TimerExpired", func(event Event) {
+		expired = append(expired, event.(TimerExpired))
+	})
+
+	pizza, err := engine.StartTimer("pizza", 10*time.Minute, nil)
+	if err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+
+	watcher := NewTimerWatcher(engine)
+	if found, err := watcher.Check(pizza.StartedAt.Add(5 * time.Minute)); err != nil || len(found) != 0 {
+		t.Fatalf("Expected nothing expired after 5 minutes, got %+v, %v", found, err)
+	}
+	if found, err := watcher.Check(pizza.ExpiresAt()); err != nil || len(found) != 1 || found[0].Label != "pizza" {
+		t.Fatalf("Expected pizza to expire, got %+v, %v", found, err)
+	}
+	if found, _ := watcher.Check(pizza.ExpiresAt().Add(time.Minute)); len(found) != 0 {
+		t.Errorf("Expected an expired timer to be reported once, got %+v", found)
+	}
+	if len(expired) != 1 {
+		t.Errorf("Expected one TimerExpired event, got %+v", expired)
+	}
+
+	// A process started later still notices the timer until it is completed.
+	restarted := NewTimerWatcher(engine)
+	if found, _ := restarted.Check(pizza.ExpiresAt().Add(time.Hour)); len(found) != 1 {
+		t.Errorf("Expected a new watcher to report the expired timer, got %+v", found)
+	}
+
+	if err := engine.CompleteTimer(pizza.ID); err != nil {
+		t.Fatalf("Failed to complete timer: %v", err)
+	}
+	if found, _ := NewTimerWatcher(engine).Check(pizza.ExpiresAt().Add(time.Hour)); len(found) != 0 {
+		t.Errorf("Expected a completed timer not to be reported, got %+v", found)
+	}
+}
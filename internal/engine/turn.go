@@ -3,52 +3,290 @@ package engine
 import (
 	"fmt"
 	"log"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/config"
 	"github.com/script-wizards/spells/internal/model"
+	"github.com/script-wizards/spells/internal/oracle"
 )
 
 type Engine struct {
 	DB       *sqlx.DB
 	EventBus *EventBus
+	// Config is the campaign configuration. The defaults are used when it
+	// is nil.
+	Config *config.Config
+	// Tables are the oracle tables used to resolve wandering encounters.
+	Tables map[string]*oracle.List
+	// Rand rolls the engine's dice. A time-seeded source is used when it is
+	// nil.
+	Rand *rand.Rand
 }
 
+func (e *Engine) config() config.Config {
+	if e.Config == nil {
+		return config.DefaultConfig()
+	}
+	return *e.Config
+}
+
+func (e *Engine) rng() *rand.Rand {
+	if e.Rand == nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return e.Rand
+}
+
+// Advance moves the session forward by delta turns.
 func (e *Engine) Advance(sessionID int64, delta int64) error {
+	return e.advance(sessionID, delta, 0)
+}
+
+// advance moves the session forward by turns and rounds, handles the time
+// events in the turns crossed and emits the resulting events.
+func (e *Engine) advance(sessionID int64, turns, rounds int64) error {
+	if rounds < 0 {
+		return fmt.Errorf("cannot go back %d rounds", -rounds)
+	}
+
 	tx, err := e.DB.Beginx()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	session, err := model.GetSession(e.DB, sessionID)
+	j, err := beginJournal(tx, &sessionID, "advance")
 	if err != nil {
-		return fmt.Errorf("failed to get session: %w", err)
+		return err
 	}
-	if session == nil {
-		return fmt.Errorf("session %d not found", sessionID)
+	advanced, err := e.advanceClock(j, sessionID, turns, rounds)
+	if err != nil {
+		return err
 	}
 
-	oldTurn := session.CurrentTurn
-	err = session.AdvanceTurn(tx, delta)
+	entry, err := j.commit(describeAdvance(turns, rounds, advanced.newTurn))
 	if err != nil {
-		return fmt.Errorf("failed to advance turn: %w", err)
+		return err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	newTurn := oldTurn + delta
-	log.Printf("TURN_ADVANCED %d→%d", oldTurn, newTurn)
+	e.emitRecorded(entry)
+	e.emitAdvanced(advanced)
+	return nil
+}
+
+// clockAdvance is what moving the clock forward did, held until the
+// journaled action that moved it is committed.
+type clockAdvance struct {
+	sessionID         int64
+	turns, rounds     int64
+	oldTurn, oldRound int64
+	newTurn, newRound int64
+	delta             int64
+	triggered         []EventTriggered
+	expired           []ConditionExpired
+	lights            []Event
+	ranOut            []ConsumableRanOut
+}
+
+// advanceClock moves the session forward by turns and rounds within a
+// journaled action, handling the time events, conditions, lights and
+// supplies in the time crossed.
+func (e *Engine) advanceClock(j *journal, sessionID int64, turns, rounds int64) (*clockAdvance, error) {
+	roundsPerTurn := e.Clock().RoundsPerTurn()
+
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionID)
+	}
+	if err := j.track("sessions", sessionID); err != nil {
+		return nil, err
+	}
+
+	// The new position comes back from the update itself, so concurrent
+	// advances each see the turns they actually crossed.
+	newTurn, newRound, err := session.AdvanceClock(j.tx, turns, rounds, roundsPerTurn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance turn: %w", err)
+	}
+	oldTotal := newTurn*roundsPerTurn + newRound - (turns*roundsPerTurn + rounds)
+	oldTurn := floorDiv(oldTotal, roundsPerTurn)
+	oldRound := oldTotal - oldTurn*roundsPerTurn
+	advanced := &clockAdvance{
+		sessionID: sessionID,
+		turns:     turns,
+		rounds:    rounds,
+		oldTurn:   oldTurn,
+		oldRound:  oldRound,
+		newTurn:   newTurn,
+		newRound:  newRound,
+		delta:     newTurn - oldTurn,
+	}
+
+	if advanced.delta > 0 {
+		advanced.triggered, err = triggerTimeEvents(j, sessionID, newTurn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	advanced.expired, err = tickConditions(j, sessionID, turns*roundsPerTurn+rounds, advanced.delta)
+	if err != nil {
+		return nil, err
+	}
+	advanced.lights, err = e.tickLights(j, sessionID, advanced.delta)
+	if err != nil {
+		return nil, err
+	}
+	days := e.Clock().At(newTurn, newRound).Day - e.Clock().At(oldTurn, oldRound).Day
+	advanced.ranOut, err = tickConsumables(j, days)
+	if err != nil {
+		return nil, err
+	}
+	return advanced, nil
+}
+
+// emitAdvanced emits the events of a committed clock advance and runs the
+// wandering checks it triggered.
+func (e *Engine) emitAdvanced(a *clockAdvance) {
+	if a.turns != 0 || a.delta != 0 {
+		log.Printf("TURN_ADVANCED %d→%d", a.oldTurn, a.newTurn)
+	}
 
 	if e.EventBus != nil {
-		e.EventBus.Emit(TurnAdvanced{
-			SessionID: sessionID,
-			OldTurn:   oldTurn,
-			NewTurn:   newTurn,
-			Delta:     delta,
-		})
+		if a.rounds > 0 {
+			e.EventBus.Emit(RoundAdvanced{
+				SessionID: a.sessionID,
+				OldTurn:   a.oldTurn,
+				OldRound:  a.oldRound,
+				NewTurn:   a.newTurn,
+				NewRound:  a.newRound,
+				Delta:     a.rounds,
+			})
+		}
+		if a.turns != 0 || a.delta != 0 {
+			e.EventBus.Emit(TurnAdvanced{
+				SessionID: a.sessionID,
+				OldTurn:   a.oldTurn,
+				NewTurn:   a.newTurn,
+				Delta:     a.delta,
+			})
+		}
+		for _, event := range a.triggered {
+			e.EventBus.Emit(event)
+		}
+		for _, event := range a.expired {
+			e.EventBus.Emit(event)
+		}
+		for _, event := range a.lights {
+			e.EventBus.Emit(event)
+		}
+		for _, event := range a.ranOut {
+			e.EventBus.Emit(event)
+		}
 	}
 
-	return nil
+	for _, event := range a.triggered {
+		if event.EventType == model.TimeEventWanderingCheck {
+			if err := e.runWanderingCheck(event.EventID); err != nil {
+				log.Printf("WANDERING_CHECK_FAILED %v", err)
+			}
+		}
+	}
+}
+
+// describeAdvance summarises an advance for the journal, e.g. "Advanced 3
+// turns to turn 7".
+func describeAdvance(turns, rounds, newTurn int64) string {
+	var parts []string
+	if turns != 0 {
+		parts = append(parts, plural(turns, "turn"))
+	}
+	if rounds != 0 || turns == 0 {
+		parts = append(parts, plural(rounds, "round"))
+	}
+	return fmt.Sprintf("Advanced %s to turn %d", strings.Join(parts, " "), newTurn)
+}
+
+// plural formats a count with a noun, e.g. "1 turn" or "3 turns".
+func plural(n int64, noun string) string {
+	if n == 1 || n == -1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// triggerTimeEvents handles every pending time event due by turn. One-off
+// events are marked handled; recurring events fire once for each
+// occurrence crossed and move to their next occurrence after turn.
+func triggerTimeEvents(j *journal, sessionID, turn int64) ([]EventTriggered, error) {
+	tx := j.tx
+	due, err := model.ListDueTimeEvents(tx, sessionID, turn)
+	if err != nil {
+		return nil, err
+	}
+
+	var triggered []EventTriggered
+	for _, event := range due {
+		if err := j.track("time_events", event.ID); err != nil {
+			return nil, err
+		}
+
+		description := ""
+		if event.Description != nil {
+			description = *event.Description
+		}
+
+		if event.RepeatEvery == nil || *event.RepeatEvery <= 0 {
+			if err := model.MarkTimeEventHandled(tx, event.ID); err != nil {
+				return nil, err
+			}
+			triggered = append(triggered, EventTriggered{
+				SessionID:   sessionID,
+				EventID:     event.ID,
+				EventType:   event.EventType,
+				Description: description,
+				TriggerTurn: event.TriggerTurn,
+			})
+			continue
+		}
+
+		next := event.TriggerTurn
+		for ; next <= turn; next += *event.RepeatEvery {
+			triggered = append(triggered, EventTriggered{
+				SessionID:   sessionID,
+				EventID:     event.ID,
+				EventType:   event.EventType,
+				Description: description,
+				TriggerTurn: next,
+				Recurring:   true,
+			})
+		}
+		if err := model.RescheduleTimeEvent(tx, event.ID, next); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(triggered, func(i, j int) bool {
+		return triggered[i].TriggerTurn < triggered[j].TriggerTurn
+	})
+	return triggered, nil
 }
@@ -0,0 +1,377 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/script-wizards/spells/internal/dice"
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/script-wizards/spells/internal/oracle"
+)
+
+// maxWanderingMonsters caps how many combatants a rolled encounter adds.
+const maxWanderingMonsters = 50
+
+var monsterCount = regexp.MustCompile(`^(\d+)\s+(.+)$`)
+
+// AddWanderingCheck validates a check and schedules it to recur every
+// FrequencyTurns turns from the session's current turn.
+func (e *Engine) AddWanderingCheck(check *model.WanderingCheck) error {
+	if check.Die == 0 {
+		check.Die = 6
+	}
+	if check.Chance == 0 {
+		check.Chance = 1
+	}
+	switch {
+	case check.FrequencyTurns <= 0:
+		return fmt.Errorf("wandering checks need a positive frequency, got %d", check.FrequencyTurns)
+	case check.Chance < 1 || check.Chance > check.Die:
+		return fmt.Errorf("chance must be between 1 and %d, got %d", check.Die, check.Chance)
+	case check.TableName == "":
+		return fmt.Errorf("wandering checks need an encounter table")
+	}
+
+	session, err := model.GetSession(e.DB, check.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return fmt.Errorf("session %d not found", check.SessionID)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &check.SessionID, "wandering_check")
+	if err != nil {
+		return err
+	}
+
+	description := "Wandering monster check"
+	if check.Location != nil && *check.Location != "" {
+		description += " (" + *check.Location + ")"
+	}
+	event := &model.TimeEvent{
+		SessionID:   check.SessionID,
+		TriggerTurn: session.CurrentTurn + check.FrequencyTurns,
+		EventType:   model.TimeEventWanderingCheck,
+		Description: &description,
+		RepeatEvery: &check.FrequencyTurns,
+	}
+	if err := model.CreateTimeEvent(tx, event); err != nil {
+		return fmt.Errorf("failed to schedule wandering check: %w", err)
+	}
+
+	check.TimeEventID = &event.ID
+	if err := model.CreateWanderingCheck(tx, check); err != nil {
+		return fmt.Errorf("failed to create wandering check: %w", err)
+	}
+	j.created("time_events", event.ID)
+	j.created("wandering_checks", check.ID)
+
+	entry, err := j.commit("Added " + strings.ToLower(description[:1]) + description[1:])
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// RemoveWanderingCheck deletes a check and its scheduled time event.
+func (e *Engine) RemoveWanderingCheck(id int64) error {
+	check, err := model.GetWanderingCheck(e.DB, id)
+	if err != nil {
+		return err
+	}
+	if check == nil {
+		return fmt.Errorf("wandering check %d not found", id)
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &check.SessionID, "wandering_check")
+	if err != nil {
+		return err
+	}
+	if err := j.track("wandering_checks", id); err != nil {
+		return err
+	}
+
+	if check.TimeEventID != nil {
+		if err := j.track("time_events", *check.TimeEventID); err != nil {
+			return err
+		}
+		if err := model.DeleteTimeEvent(tx, *check.TimeEventID); err != nil {
+			return err
+		}
+	}
+	if err := model.DeleteWanderingCheck(tx, id); err != nil {
+		return err
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Removed wandering check %d", id))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// SetLocation moves the party to a location, so that of the checks for a
+// location only those for it roll. An empty location leaves the party
+// nowhere in particular, where only the checks for the whole session roll.
+func (e *Engine) SetLocation(sessionID int64, location string) error {
+	location = strings.TrimSpace(location)
+	var where *string
+	description := "Cleared the party's location"
+	if location != "" {
+		where = &location
+		description = "Party moved to " + location
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "location")
+	if err != nil {
+		return err
+	}
+	if err := j.track("sessions", sessionID); err != nil {
+		return err
+	}
+
+	session := &model.Session{ID: sessionID}
+	if err := session.SetLocation(tx, where); err != nil {
+		return err
+	}
+	entry, err := j.commit(description)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	return nil
+}
+
+// runWanderingCheck rolls the check driven by a triggered time event,
+// unless it is for a location the party is not at.
+func (e *Engine) runWanderingCheck(timeEventID int64) error {
+	check, err := model.GetWanderingCheckByTimeEvent(e.DB, timeEventID)
+	if err != nil {
+		return err
+	}
+	if check == nil {
+		return nil
+	}
+
+	session, err := model.GetSession(e.DB, check.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil || !check.RollsAt(session.Location) {
+		return nil
+	}
+	_, err = e.RollWanderingCheck(check)
+	return err
+}
+
+// RollWanderingCheck rolls a check. On an encounter it resolves the check's
+// oracle table, records the result against the session and, when the check
+// asks for it and no encounter is running, starts an encounter with the
+// rolled monsters. The outcome is emitted as a WanderingCheckRolled event.
+func (e *Engine) RollWanderingCheck(check *model.WanderingCheck) (*WanderingCheckRolled, error) {
+	rng := e.rng()
+
+	roll, _, err := dice.Roll(fmt.Sprintf("1d%d", check.Die), rng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll wandering check: %w", err)
+	}
+
+	rolled := &WanderingCheckRolled{
+		SessionID:   check.SessionID,
+		CheckID:     check.ID,
+		Roll:        roll,
+		Chance:      check.Chance,
+		Die:         check.Die,
+		Encountered: roll <= check.Chance,
+		Table:       check.TableName,
+	}
+	if check.Location != nil {
+		rolled.Location = *check.Location
+	}
+
+	if rolled.Encountered {
+		if _, exists := e.Tables[check.TableName]; !exists {
+			return nil, fmt.Errorf("encounter table %q not found", check.TableName)
+		}
+
+		tree, err := oracle.NewResolver(e.Tables, rng).ResolveTree(fmt.Sprintf("[%s]", check.TableName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve encounter: %w", err)
+		}
+		rolled.Result = tree.Result
+
+		if err := e.recordEncounter(check, rolled, tree); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("WANDERING_CHECK %d on d%d (chance %d) %s", roll, check.Die, check.Chance, rolled.Result)
+
+	if e.EventBus != nil {
+		e.EventBus.Emit(*rolled)
+	}
+	return rolled, nil
+}
+
+// recordEncounter saves the oracle result for an encounter and starts an
+// encounter for it when the check asks for one.
+func (e *Engine) recordEncounter(check *model.WanderingCheck, rolled *WanderingCheckRolled, tree *oracle.Node) error {
+	var active *model.Encounter
+	var monster *model.Monster
+	count := 0
+	if check.CreateEncounter {
+		var err error
+		active, err = model.GetActiveEncounter(e.DB, check.SessionID)
+		if err != nil {
+			return err
+		}
+		if monster, count, err = e.FindMonsters(tree.Result); err != nil {
+			return err
+		}
+	}
+
+	nested, err := json.Marshal(tree.Children)
+	if err != nil {
+		return fmt.Errorf("failed to marshal oracle tree: %w", err)
+	}
+	nestedStr := string(nested)
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &model.OracleResult{
+		TableName:     &check.TableName,
+		QueryContext:  &tree.Source,
+		Result:        tree.Result,
+		NestedResults: &nestedStr,
+		SessionID:     &check.SessionID,
+	}
+	if err := model.CreateOracleResult(tx, result); err != nil {
+		return fmt.Errorf("failed to save encounter result: %w", err)
+	}
+
+	if check.CreateEncounter && active == nil {
+		name := "Wandering: " + tree.Result
+		encounter := &model.Encounter{
+			SessionID:   check.SessionID,
+			Name:        &name,
+			Description: check.Location,
+			IsActive:    true,
+		}
+		if err := model.CreateEncounter(tx, encounter); err != nil {
+			return fmt.Errorf("failed to create encounter: %w", err)
+		}
+
+		combatants, err := rolledMonsters(encounter.ID, tree.Result, monster, count, e.rng())
+		if err != nil {
+			return err
+		}
+		for i := range combatants {
+			if err := model.CreateCombatant(tx, &combatants[i]); err != nil {
+				return err
+			}
+		}
+		rolled.EncounterID = &encounter.ID
+	}
+
+	return tx.Commit()
+}
+
+// rolledMonsters builds the combatants for an encounter result. count of
+// a catalog monster get their stat block's hit points, and a result that
+// is not in the catalog is added by name alone.
+func rolledMonsters(encounterID int64, result string, monster *model.Monster, count int, rng *rand.Rand) ([]model.InitiativeOrder, error) {
+	if monster != nil {
+		return monsterCombatants(encounterID, monster, count, 0, rng)
+	}
+
+	names := monsterNames(result)
+	combatants := make([]model.InitiativeOrder, len(names))
+	for i := range names {
+		combatants[i] = model.InitiativeOrder{EncounterID: encounterID, CharacterName: &names[i], IsActive: true}
+	}
+	return combatants, nil
+}
+
+// monsterNames turns an encounter result such as "3 goblins" into numbered
+// combatant names: goblin 1, goblin 2, goblin 3. A result without a
+// leading count is a single combatant.
+func monsterNames(result string) []string {
+	count, name := splitCount(result)
+	if count == 1 {
+		return []string{name}
+	}
+	name = singular(name)
+
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s %d", name, i+1)
+	}
+	return names
+}
+
+// splitCount splits a leading count off a result such as "3 goblins". A
+// result without one counts once, and counts are capped at
+// maxWanderingMonsters.
+func splitCount(result string) (int, string) {
+	result = strings.TrimSpace(result)
+	match := monsterCount.FindStringSubmatch(result)
+	if match == nil {
+		return 1, result
+	}
+
+	count, err := strconv.Atoi(match[1])
+	if err != nil || count < 1 {
+		return 1, result
+	}
+	return min(count, maxWanderingMonsters), match[2]
+}
+
+// singular drops the plural "s" from a name such as "goblins".
+func singular(name string) string {
+	if strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") {
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/model"
+	"github.com/script-wizards/spells/internal/oracle"
+)
+
+func TestEngine_WanderingCheck(t *testing.T) {
+	engine, session := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(1))
+	engine.Tables = oracle.Templates(map[string]string{
+		"level_1": "3 goblins",
+	})
+
+	var rolls []WanderingCheckRolled
+	engine.EventBus.Subscribe("WanderingCheckRolled", func(event Event) {
+		rolls = append(rolls, event.(WanderingCheckRolled))
+	})
+
+	location := "Level 1"
+	check := &model.WanderingCheck{
+		SessionID:       session.ID,
+		Location:        &location,
+		FrequencyTurns:  2,
+		Chance:          6,
+		TableName:       "level_1",
+		CreateEncounter: true,
+	}
+	if err := engine.AddWanderingCheck(check); err != nil {
+		t.Fatalf("Failed to add wandering check: %v", err)
+	}
+	if check.Die != 6 || check.TimeEventID == nil {
+		t.Fatalf("Expected a d6 check with a time event, got %+v", check)
+	}
+
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(rolls) != 0 {
+		t.Fatalf("Expected no check before turn 2, got %+v", rolls)
+	}
+
+	// The party is not on level 1 yet, so the check does not roll.
+	if err := engine.Advance(session.ID, 1); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(rolls) != 0 {
+		t.Fatalf("Expected no check away from level 1, got %+v", rolls)
+	}
+
+	if err := engine.SetLocation(session.ID, "level 1"); err != nil {
+		t.Fatalf("Failed to set location: %v", err)
+	}
+	if err := engine.Advance(session.ID, 2); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(rolls) != 1 {
+		t.Fatalf("Expected one check at turn 4, got %+v", rolls)
+	}
+
+	rolled := rolls[0]
+	if !rolled.Encountered || rolled.Result != "3 goblins" || rolled.Location != "Level 1" {
+		t.Fatalf("Unexpected check result: %+v", rolled)
+	}
+	if rolled.EncounterID == nil {
+		t.Fatal("Expected an encounter to be started")
+	}
+
+	encounter, err := model.GetActiveEncounter(engine.DB, session.ID)
+	if err != nil || encounter == nil || encounter.ID != *rolled.EncounterID {
+		t.Fatalf("Expected the new encounter to be active, got %+v, %v", encounter, err)
+	}
+	combatants, err := model.ListActiveBySort(engine.DB, encounter.ID)
+	if err != nil {
+		t.Fatalf("Failed to list combatants: %v", err)
+	}
+	var names []string
+	for _, combatant := range combatants {
+		names = append(names, combatant.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"goblin 1", "goblin 2", "goblin 3"}) {
+		t.Errorf("Unexpected combatants: %v", names)
+	}
+
+	results, err := model.ListOracleResults(engine.DB, model.OracleResultFilter{TableName: "level_1"})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected the encounter to be logged, got %+v, %v", results, err)
+	}
+
+	// A second hit while the encounter is running does not start another.
+	if err := engine.Advance(session.ID, 2); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(rolls) != 2 || rolls[1].EncounterID != nil {
+		t.Fatalf("Expected a second check without a new encounter, got %+v", rolls)
+	}
+
+	if err := engine.SetLocation(session.ID, ""); err != nil {
+		t.Fatalf("Failed to clear location: %v", err)
+	}
+	if err := engine.Advance(session.ID, 2); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(rolls) != 2 {
+		t.Fatalf("Expected no check after leaving level 1, got %+v", rolls)
+	}
+
+	if err := engine.RemoveWanderingCheck(check.ID); err != nil {
+		t.Fatalf("Failed to remove wandering check: %v", err)
+	}
+	if err := engine.Advance(session.ID, 4); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	if len(rolls) != 2 {
+		t.Errorf("Expected no checks after removal, got %d", len(rolls))
+	}
+}
+
+func TestEngine_AddWanderingCheckErrors(t *testing.T) {
+	engine, session := newTestEngine(t)
+
+	tests := []model.WanderingCheck{
+		{SessionID: session.ID, FrequencyTurns: 0, TableName: "level_1"},
+		{SessionID: session.ID, FrequencyTurns: 2, Chance: 7, Die: 6, TableName: "level_1"},
+		{SessionID: session.ID, FrequencyTurns: 2},
+		{SessionID: 999, FrequencyTurns: 2, TableName: "level_1"},
+	}
+	for _, check := range tests {
+		if err := engine.AddWanderingCheck(&check); err == nil {
+			t.Errorf("Expected an error for %+v", check)
+		}
+	}
+}
+
+func TestMonsterNames(t *testing.T) {
+	tests := []struct {
+		result   string
+		expected []string
+	}{
+		{"3 goblins", []string{"goblin 1", "goblin 2", "goblin 3"}},
+		{"1 ogre", []string{"ogre"}},
+		{"2 giant bass", []string{"giant bass 1", "giant bass 2"}},
+		{"a lost merchant", []string{"a lost merchant"}},
+	}
+
+	for _, tt := range tests {
+		if got := monsterNames(tt.result); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("monsterNames(%q) = %v, expected %v", tt.result, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/script-wizards/spells/internal/model"
+)
+
+// XPSummary is the party's XP: what it earned this session and across the
+// campaign, and the XP its unawarded treasure will be worth.
+type XPSummary struct {
+	Session   int
+	Campaign  int
+	Pending   int
+	PartySize int
+	Ledger    []model.XPEntry
+}
+
+// Share returns each PC's share of the session's XP.
+func (s XPSummary) Share() int {
+	return s.Session / max(s.PartySize, 1)
+}
+
+// TreasureAwarded is the outcome of awarding a session's treasure as XP.
+type TreasureAwarded struct {
+	Treasure []model.Treasure
+	XP       int
+}
+
+// RecordTreasure records treasure the party found in a session, worth
+// gold gold pieces. Its XP is credited when it is awarded.
+func (e *Engine) RecordTreasure(sessionID int64, description string, gold int, location string) (*model.Treasure, error) {
+	if description == "" {
+		return nil, fmt.Errorf("treasure needs a description")
+	}
+	if gold < 0 {
+		return nil, fmt.Errorf("gold value cannot be negative, got %d", gold)
+	}
+	session, err := model.GetSession(e.DB, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionID)
+	}
+
+	treasure := &model.Treasure{SessionID: sessionID, Description: description, GoldValue: gold}
+	if location != "" {
+		treasure.Location = &location
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "treasure")
+	if err != nil {
+		return nil, err
+	}
+	if err := model.CreateTreasure(tx, treasure); err != nil {
+		return nil, err
+	}
+	j.created("treasure_found", treasure.ID)
+
+	entry, err := j.commit(fmt.Sprintf("Found %s (%d gp)", description, gold))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil {
+		e.EventBus.Emit(TreasureFound{
+			SessionID:   sessionID,
+			TreasureID:  treasure.ID,
+			Description: description,
+			GoldValue:   gold,
+			Location:    location,
+		})
+	}
+	return treasure, nil
+}
+
+// AwardXP credits the XP for every treasure in a session that has not
+// been awarded yet, at the campaign's conversion rate and multiplier, and
+// marks it awarded. With nothing to award, it does nothing.
+func (e *Engine) AwardXP(sessionID int64) (*TreasureAwarded, error) {
+	treasure, err := model.ListUnawardedTreasure(e.DB, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	awarded := &TreasureAwarded{Treasure: treasure}
+	if len(treasure) == 0 {
+		return awarded, nil
+	}
+
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	j, err := beginJournal(tx, &sessionID, "award_xp")
+	if err != nil {
+		return nil, err
+	}
+	for _, found := range treasure {
+		if err := j.track("treasure_found", found.ID); err != nil {
+			return nil, err
+		}
+		if err := model.SetTreasureAwarded(tx, found.ID); err != nil {
+			return nil, err
+		}
+
+		xp := e.treasureXP(found.GoldValue)
+		if xp == 0 {
+			continue
+		}
+		entry := &model.XPEntry{
+			SessionID:   sessionID,
+			Source:      model.XPFromTreasure,
+			Description: found.Description,
+			XP:          xp,
+			TreasureID:  &found.ID,
+		}
+		if err := model.CreateXPEntry(tx, entry); err != nil {
+			return nil, err
+		}
+		j.created("xp_ledger", entry.ID)
+		awarded.XP += xp
+	}
+
+	entry, err := j.commit(fmt.Sprintf("Awarded %d XP for %s", awarded.XP, plural(int64(len(treasure)), "treasure")))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.emitRecorded(entry)
+	if e.EventBus != nil && awarded.XP > 0 {
+		e.EventBus.Emit(XPEarned{
+			SessionID:   sessionID,
+			Source:      model.XPFromTreasure,
+			Description: plural(int64(len(treasure)), "treasure"),
+			XP:          awarded.XP,
+		})
+	}
+	return awarded, nil
+}
+
+// XP totals the party's XP for a session and the campaign. Shares are split
+// between the active characters on the roster, or between the campaign's
+// party size when the roster is empty.
+func (e *Engine) XP(sessionID int64) (*XPSummary, error) {
+	summary := &XPSummary{PartySize: e.config().PartySize}
+
+	party, err := model.ListCharacters(e.DB, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(party) > 0 {
+		summary.PartySize = len(party)
+	}
+	if summary.Ledger, err = model.ListXPEntries(e.DB, sessionID); err != nil {
+		return nil, err
+	}
+	for _, entry := range summary.Ledger {
+		summary.Session += entry.XP
+	}
+	if summary.Campaign, err = model.CampaignXP(e.DB); err != nil {
+		return nil, err
+	}
+
+	treasure, err := model.ListUnawardedTreasure(e.DB, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, found := range treasure {
+		summary.Pending += e.treasureXP(found.GoldValue)
+	}
+	return summary, nil
+}
+
+// defeatXP returns the XP the party earns for defeating a combatant: the
+// XP on its stat block, unless it fought for the party or has already been
+// credited.
+func (e *Engine) defeatXP(combatant *model.Combatant) (int, error) {
+	if combatant.MonsterID == nil || combatant.Side == model.SideParty {
+		return 0, nil
+	}
+	monster, err := model.GetMonster(e.DB, *combatant.MonsterID)
+	if err != nil {
+		return 0, err
+	}
+	if monster == nil || monster.XP <= 0 {
+		return 0, nil
+	}
+	credited, err := model.CombatantXPCredited(e.DB, combatant.ID)
+	if err != nil || credited {
+		return 0, err
+	}
+	return e.scaleXP(float64(monster.XP)), nil
+}
+
+// treasureXP converts a gold value to XP at the campaign's rate.
+func (e *Engine) treasureXP(gold int) int {
+	return e.scaleXP(float64(gold) * e.config().XPConversionRate)
+}
+
+// scaleXP applies the campaign's XP multiplier, rounding to the nearest
+// point.
+func (e *Engine) scaleXP(xp float64) int {
+	return int(math.Round(xp * e.config().XPMultiplier))
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/model"
+)
+
+func TestEngine_XP(t *testing.T) {
+	engine, session := newTestEngine(t)
+	engine.Rand = rand.New(rand.NewSource(1))
+	cfg := config.DefaultConfig()
+	cfg.XPMultiplier = 1.5
+	engine.Config = &cfg
+	goblin := &model.Monster{Name: "Goblin", ArmorClass: 6, HitDice: "1", Movement: 60, Morale: 12, XP: 5}
+	createTestMonster(t, engine, goblin)
+
+	var earned []XPEarned
+	engine.EventBus.Subscribe("XPEarned", func(event Event) {
+		earned = append(earned, event.(XPEarned))
+	})
+
+	encounter, err := engine.StartEncounter(session.ID, "Ambush")
+	if err != nil {
+		t.Fatalf("Failed to start encounter: %v", err)
+	}
+	goblins, err := engine.AddMonsters(encounter.ID, goblin, 2, 0, "")
+	if err != nil {
+		t.Fatalf("Failed to add goblins: %v", err)
+	}
+
+	// 5 XP at x1.5 rounds to 8.
+	if err := engine.Damage(goblins[0].ID, 20); err != nil {
+		t.Fatalf("Failed to damage goblin: %v", err)
+	}
+	if len(earned) != 1 || earned[0].XP != 8 || earned[0].Source != model.XPFromMonster || earned[0].Description != "Goblin 1" {
+		t.Fatalf("Expected 8 XP for Goblin 1, got %+v", earned)
+	}
+
+	// A goblin brought back and felled again is only worth its XP once.
+	if err := engine.Heal(goblins[0].ID, 1); err != nil {
+		t.Fatalf("Failed to heal goblin: %v", err)
+	}
+	if err := engine.Damage(goblins[0].ID, 1); err != nil {
+		t.Fatalf("Failed to damage goblin: %v", err)
+	}
+	if len(earned) != 1 {
+		t.Errorf("Expected no more XP for the same goblin, got %+v", earned)
+	}
+
+	// Goblins on the party's side earn nothing.
+	if err := engine.SetCombatantSide(goblins[1].ID, model.SideParty); err != nil {
+		t.Fatalf("Failed to switch side: %v", err)
+	}
+	if err := engine.Damage(goblins[1].ID, 20); err != nil {
+		t.Fatalf("Failed to damage goblin: %v", err)
+	}
+	if len(earned) != 1 {
+		t.Errorf("Expected no XP for an ally, got %+v", earned)
+	}
+
+	if _, err := engine.RecordTreasure(session.ID, "Gold chalice", 50, "Room 12"); err != nil {
+		t.Fatalf("Failed to record treasure: %v", err)
+	}
+	if _, err := engine.RecordTreasure(session.ID, "", 5, ""); err == nil {
+		t.Error("Expected an error for treasure without a description")
+	}
+
+	summary, err := engine.XP(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to total XP: %v", err)
+	}
+	if summary.Session != 8 || summary.Campaign != 8 || summary.Pending != 75 || summary.PartySize != 4 || summary.Share() != 2 {
+		t.Errorf("Unexpected summary before the award: %+v", summary)
+	}
+
+	awarded, err := engine.AwardXP(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to award XP: %v", err)
+	}
+	if awarded.XP != 75 || len(awarded.Treasure) != 1 {
+		t.Errorf("Expected 75 XP for the chalice, got %+v", awarded)
+	}
+	summary, err = engine.XP(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to total XP: %v", err)
+	}
+	if summary.Session != 83 || summary.Pending != 0 || summary.Share() != 20 || len(summary.Ledger) != 2 {
+		t.Errorf("Unexpected summary after the award: %+v", summary)
+	}
+
+	if again, err := engine.AwardXP(session.ID); err != nil || again.XP != 0 || len(again.Treasure) != 0 {
+		t.Errorf("Expected nothing left to award, got %+v, %v", again, err)
+	}
+
+	// Undo takes the award back and leaves the treasure waiting.
+	if _, err := engine.Undo(session.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	summary, err = engine.XP(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to total XP: %v", err)
+	}
+	if summary.Session != 8 || summary.Pending != 75 {
+		t.Errorf("Expected the award undone, got %+v", summary)
+	}
+}
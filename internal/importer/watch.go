@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/fsnotify/fsnotify"
@@ -15,10 +16,23 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// FrontMatter is the YAML header of a markdown note. Type picks what the
+// note becomes: "npc" or "monster". The stat block fields only apply to
+// monsters, and missing ones fall back to a 1 HD, AC 9 creature. Movement
+// is in feet per turn and may be written the OSR way, as 120' (40').
 type FrontMatter struct {
 	Type string   `yaml:"type"`
 	Name string   `yaml:"name"`
 	Tags []string `yaml:"tags"`
+
+	AC        *int   `yaml:"ac"`
+	HD        string `yaml:"hd"`
+	Attacks   string `yaml:"attacks"`
+	Damage    string `yaml:"damage"`
+	Movement  string `yaml:"movement"`
+	Morale    *int   `yaml:"morale"`
+	XP        int    `yaml:"xp"`
+	Alignment string `yaml:"alignment"`
 }
 
 type Watcher struct {
@@ -77,6 +91,24 @@ func (w *Watcher) Stop() {
 	close(w.done)
 }
 
+// Import processes every markdown file under root once, for notes written
+// before the watcher started. A note that cannot be imported is logged and
+// skipped.
+func (w *Watcher) Import(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		if err := w.processFile(path); err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+		}
+		return nil
+	})
+}
+
 func (w *Watcher) addMarkdownFiles(root string) error {
 	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -107,12 +139,21 @@ func (w *Watcher) processFile(filename string) error {
 		return fmt.Errorf("failed to parse front matter: %w", err)
 	}
 
-	if frontMatter == nil || frontMatter.Type != "npc" {
+	if frontMatter == nil {
 		return nil
 	}
 
-	npc := w.convertToNPC(frontMatter, bodyText)
-	return w.upsertNPC(npc)
+	switch frontMatter.Type {
+	case "npc":
+		return w.upsertNPC(w.convertToNPC(frontMatter, bodyText))
+	case "monster":
+		monster, err := w.convertToMonster(frontMatter, bodyText)
+		if err != nil {
+			return err
+		}
+		return w.upsertMonster(monster)
+	}
+	return nil
 }
 
 func (w *Watcher) parseFrontMatter(filename string) (*FrontMatter, string, error) {
@@ -194,6 +235,84 @@ func (w *Watcher) convertToNPC(fm *FrontMatter, body string) *model.NPC {
 	}
 }
 
+func (w *Watcher) convertToMonster(fm *FrontMatter, body string) (*model.Monster, error) {
+	monster := &model.Monster{
+		Name:       fm.Name,
+		ArmorClass: 9,
+		HitDice:    "1",
+		Movement:   120,
+		Morale:     7,
+		XP:         fm.XP,
+	}
+	if fm.AC != nil {
+		monster.ArmorClass = *fm.AC
+	}
+	if fm.HD != "" {
+		monster.HitDice = fm.HD
+	}
+	if fm.Movement != "" {
+		movement, err := parseMovement(fm.Movement)
+		if err != nil {
+			return nil, err
+		}
+		monster.Movement = movement
+	}
+	if fm.Morale != nil {
+		monster.Morale = *fm.Morale
+	}
+
+	optional := func(value string) *string {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	monster.Attacks = optional(fm.Attacks)
+	monster.Damage = optional(fm.Damage)
+	monster.Alignment = optional(fm.Alignment)
+	monster.Description = optional(body)
+
+	if len(fm.Tags) > 0 {
+		tagsJSON, _ := json.Marshal(fm.Tags)
+		tagsStr := string(tagsJSON)
+		monster.Tags = &tagsStr
+	}
+	return monster, nil
+}
+
+// parseMovement reads the feet per turn at the start of a movement rate
+// such as 120, 120' or 120' (40'), ignoring the encounter rate after it.
+func parseMovement(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	end := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if end == -1 {
+		end = len(value)
+	}
+	movement, err := strconv.Atoi(value[:end])
+	if err != nil {
+		return 0, fmt.Errorf("invalid movement %q, expected feet such as 120' (40')", value)
+	}
+	return movement, nil
+}
+
+func (w *Watcher) upsertMonster(monster *model.Monster) error {
+	if monster.Name == "" {
+		return fmt.Errorf("monster has no name")
+	}
+
+	tx, err := w.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := model.UpsertMonster(tx, monster); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (w *Watcher) upsertNPC(npc *model.NPC) error {
 	tx, err := w.db.Beginx()
 	if err != nil {
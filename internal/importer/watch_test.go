@@ -30,6 +30,21 @@ func createTestDB(t *testing.T) *sqlx.DB {
 		tags TEXT,
 		last_mentioned TIMESTAMP,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE monsters (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		armor_class INTEGER NOT NULL DEFAULT 9,
+		hit_dice TEXT NOT NULL DEFAULT '1',
+		attacks TEXT,
+		damage TEXT,
+		movement INTEGER NOT NULL DEFAULT 120,
+		morale INTEGER NOT NULL DEFAULT 7,
+		xp INTEGER NOT NULL DEFAULT 0,
+		alignment TEXT,
+		description TEXT,
+		tags TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);`
 
 	_, err = database.Exec(createTableSQL)
@@ -323,6 +338,86 @@ func TestUpsertNPC(t *testing.T) {
 	}
 }
 
+func TestImportMonster(t *testing.T) {
+	database := createTestDB(t)
+	defer database.Close()
+
+	watcher, err := NewWatcher(database)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	tmpDir := t.TempDir()
+	notes := map[string]string{
+		"goblin.md": `---
+type: monster
+name: Goblin
+ac: 6
+hd: 1-1
+attacks: 1 × weapon
+damage: 1d6
+movement: 60' (20')
+morale: 7
+xp: 5
+alignment: chaotic
+tags: [humanoid]
+---
+
+Small, grotesque humanoids.`,
+		"rat.md": `---
+type: monster
+name: Giant Rat
+hd: 1/2
+---`,
+		"thorg.md": `---
+type: npc
+name: Thorg
+---`,
+		"ogre.md": `---
+type: monster
+name: Ogre
+movement: fast
+---`,
+	}
+	for name, content := range notes {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	if err := watcher.Import(tmpDir); err != nil {
+		t.Fatalf("failed to import notes: %v", err)
+	}
+
+	goblin, err := model.GetMonsterByName(database, "Goblin")
+	if err != nil || goblin == nil {
+		t.Fatalf("expected the goblin to be imported, got %+v, %v", goblin, err)
+	}
+	if goblin.ArmorClass != 6 || goblin.HitDice != "1-1" || goblin.Movement != 60 || goblin.Morale != 7 ||
+		goblin.XP != 5 || *goblin.Damage != "1d6" || *goblin.Description != "Small, grotesque humanoids." {
+		t.Errorf("unexpected goblin stat block: %+v", goblin)
+	}
+
+	rat, err := model.GetMonsterByName(database, "Giant Rat")
+	if err != nil || rat == nil {
+		t.Fatalf("expected the rat to be imported, got %+v, %v", rat, err)
+	}
+	if rat.ArmorClass != 9 || rat.HitDice != "1/2" || rat.Movement != 120 || rat.Morale != 7 || rat.Description != nil {
+		t.Errorf("expected defaults for the rat, got %+v", rat)
+	}
+
+	var npcs int
+	if err := database.Get(&npcs, "SELECT COUNT(*) FROM npcs WHERE name = 'Thorg'"); err != nil || npcs != 1 {
+		t.Errorf("expected the npc note to be imported too, got %d, %v", npcs, err)
+	}
+
+	monsters, err := model.ListMonsters(database)
+	if err != nil || len(monsters) != 2 {
+		t.Errorf("expected the ogre's bad movement to be skipped, got %+v, %v", monsters, err)
+	}
+}
+
 func TestFileWatcher(t *testing.T) {
 	database := createTestDB(t)
 	defer database.Close()
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	ChangeInsert = "insert"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// Change is a row of the change log that database triggers write for every
+// insert, update and delete of shared session state. SessionID is set for
+// rows that belong to a session.
+type Change struct {
+	ID        int64     `db:"id"`
+	TableName string    `db:"table_name"`
+	RowID     int64     `db:"row_id"`
+	Action    string    `db:"action"`
+	SessionID *int64    `db:"session_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ListChangesSince returns up to limit changes logged after the change with
+// ID afterID, oldest first.
+func ListChangesSince(db *sqlx.DB, afterID int64, limit int) ([]Change, error) {
+	var changes []Change
+	query := `SELECT id, table_name, row_id, action, session_id, created_at FROM changes 
+			  WHERE id > ? ORDER BY id LIMIT ?`
+	if err := db.Select(&changes, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+	return changes, nil
+}
+
+// LatestChangeID returns the ID of the most recent change, or 0 when the
+// log is empty.
+func LatestChangeID(db *sqlx.DB) (int64, error) {
+	var id int64
+	if err := db.Get(&id, "SELECT COALESCE(MAX(id), 0) FROM changes"); err != nil {
+		return 0, fmt.Errorf("failed to get latest change: %w", err)
+	}
+	return id, nil
+}
+
+// PruneChanges deletes changes logged more than olderThan ago.
+func PruneChanges(db *sqlx.DB, olderThan time.Duration) (int64, error) {
+	modifier := fmt.Sprintf("-%d seconds", int64(olderThan/time.Second))
+	result, err := db.Exec("DELETE FROM changes WHERE created_at < datetime('now', ?)", modifier)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune changes: %w", err)
+	}
+	return result.RowsAffected()
+}
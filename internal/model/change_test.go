@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangeLog(t *testing.T) {
+	database := newTestDB(t)
+
+	before, err := LatestChangeID(database)
+	if err != nil {
+		t.Fatalf("Failed to get latest change: %v", err)
+	}
+
+	session := createTestSession(t, database)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if _, _, err := session.AdvanceClock(tx, 1, 0, 60); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to advance clock: %v", err)
+	}
+	encounter := &Encounter{SessionID: session.ID, IsActive: true}
+	if err := CreateEncounter(tx, encounter); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create encounter: %v", err)
+	}
+	name := "goblin"
+	combatant, err := AddCombatant(tx, encounter.ID, nil, &name, 10, nil, nil)
+	if err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to add combatant: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	changes, err := ListChangesSince(database, before, 100)
+	if err != nil {
+		t.Fatalf("Failed to list changes: %v", err)
+	}
+
+	expected := []struct {
+		table  string
+		rowID  int64
+		action string
+	}{
+		{"sessions", session.ID, ChangeInsert},
+		{"sessions", session.ID, ChangeUpdate},
+		{"encounters", encounter.ID, ChangeInsert},
+		{"initiative_order", combatant.ID, ChangeInsert},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, want := range expected {
+		got := changes[i]
+		if got.TableName != want.table || got.RowID != want.rowID || got.Action != want.action {
+			t.Errorf("Change %d = %s %d %s, expected %s %d %s", i,
+				got.TableName, got.RowID, got.Action, want.table, want.rowID, want.action)
+		}
+		if got.SessionID == nil || *got.SessionID != session.ID {
+			t.Errorf("Change %d has session %v, expected %d", i, got.SessionID, session.ID)
+		}
+	}
+
+	latest, err := LatestChangeID(database)
+	if err != nil || latest != changes[len(changes)-1].ID {
+		t.Errorf("Expected latest change %d, got %d, %v", changes[len(changes)-1].ID, latest, err)
+	}
+
+	if pruned, err := PruneChanges(database, time.Hour); err != nil || pruned != 0 {
+		t.Errorf("Expected recent changes to be kept, pruned %d, %v", pruned, err)
+	}
+	if _, err := database.Exec("UPDATE changes SET created_at = datetime('now', '-2 days')"); err != nil {
+		t.Fatalf("Failed to age changes: %v", err)
+	}
+	if pruned, err := PruneChanges(database, 24*time.Hour); err != nil || pruned != int64(len(changes)) {
+		t.Errorf("Expected %d old changes pruned, got %d, %v", len(changes), pruned, err)
+	}
+}
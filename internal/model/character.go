@@ -0,0 +1,153 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Character is a player character on the party roster. ArmorClass is in
+// the campaign's AC system, and LightSource is what the character carries
+// to see by, such as "torch". While it burns, LightRemaining counts down
+// the turns left of LightDuration. Inactive characters stay on the roster
+// but are left out of the party.
+type Character struct {
+	ID             int64     `db:"id"`
+	Name           string    `db:"name"`
+	Player         *string   `db:"player"`
+	Class          *string   `db:"class"`
+	Level          int       `db:"level"`
+	HPMax          *int      `db:"hp_max"`
+	HPCurrent      *int      `db:"hp_current"`
+	ArmorClass     *int      `db:"armor_class"`
+	XP             int       `db:"xp"`
+	LightSource    *string   `db:"light_source"`
+	LightRemaining *int      `db:"light_remaining"`
+	LightDuration  *int      `db:"light_duration"`
+	IsActive       bool      `db:"is_active"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// Light sources a character can carry. Each burns for the number of turns
+// the campaign sets for its kind.
+const (
+	LightTorch   = "torch"
+	LightLantern = "lantern"
+	LightMagic   = "magic light"
+)
+
+const characterColumns = `id, name, player, class, level, hp_max, hp_current, armor_class, xp, light_source,
+			  light_remaining, light_duration, is_active, created_at`
+
+func CreateCharacter(tx *sqlx.Tx, character *Character) error {
+	if character.Level < 1 {
+		character.Level = 1
+	}
+	query := `INSERT INTO characters (name, player, class, level, hp_max, hp_current, armor_class, xp, light_source,
+			  light_remaining, light_duration, is_active)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, character.Name, character.Player, character.Class, character.Level, character.HPMax,
+		character.HPCurrent, character.ArmorClass, character.XP, character.LightSource, character.LightRemaining,
+		character.LightDuration, character.IsActive)
+	if err := row.Scan(&character.ID, &character.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create character: %w", err)
+	}
+	return nil
+}
+
+func UpdateCharacter(tx *sqlx.Tx, character *Character) error {
+	query := `UPDATE characters SET name = ?, player = ?, class = ?, level = ?, hp_max = ?, hp_current = ?,
+			  armor_class = ?, xp = ?, light_source = ?, light_remaining = ?, light_duration = ?, is_active = ? WHERE id = ?`
+	_, err := tx.Exec(query, character.Name, character.Player, character.Class, character.Level, character.HPMax,
+		character.HPCurrent, character.ArmorClass, character.XP, character.LightSource, character.LightRemaining,
+		character.LightDuration, character.IsActive, character.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
+	return nil
+}
+
+// SetCharacterHP sets a character's current hit points.
+func SetCharacterHP(tx *sqlx.Tx, id int64, hp int) error {
+	query := "UPDATE characters SET hp_current = ? WHERE id = ?"
+	if _, err := tx.Exec(query, hp, id); err != nil {
+		return fmt.Errorf("failed to set character hp: %w", err)
+	}
+	return nil
+}
+
+// SetCharacterLight sets a character's light source and how many of its
+// duration's turns are left. A nil remaining means it is not lit.
+func SetCharacterLight(tx *sqlx.Tx, id int64, source *string, remaining, duration *int) error {
+	query := "UPDATE characters SET light_source = ?, light_remaining = ?, light_duration = ? WHERE id = ?"
+	if _, err := tx.Exec(query, source, remaining, duration, id); err != nil {
+		return fmt.Errorf("failed to set character light: %w", err)
+	}
+	return nil
+}
+
+// ListLitCharacters returns the active characters whose lights are still
+// burning.
+func ListLitCharacters(tx *sqlx.Tx) ([]Character, error) {
+	var characters []Character
+	query := `SELECT ` + characterColumns + ` FROM characters 
+			  WHERE is_active = 1 AND light_remaining > 0 ORDER BY id`
+	if err := tx.Select(&characters, query); err != nil {
+		return nil, fmt.Errorf("failed to list lit characters: %w", err)
+	}
+	return characters, nil
+}
+
+// Lit reports whether the character's light is burning.
+func (c Character) Lit() bool {
+	return c.LightRemaining != nil && *c.LightRemaining > 0
+}
+
+func GetCharacter(db *sqlx.DB, id int64) (*Character, error) {
+	var character Character
+	query := `SELECT ` + characterColumns + ` FROM characters WHERE id = ?`
+	err := db.Get(&character, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character: %w", err)
+	}
+	return &character, nil
+}
+
+// GetCharacterByName looks a character up by name, ignoring case.
+func GetCharacterByName(db *sqlx.DB, name string) (*Character, error) {
+	var character Character
+	query := `SELECT ` + characterColumns + ` FROM characters WHERE name = ?`
+	err := db.Get(&character, query, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character: %w", err)
+	}
+	return &character, nil
+}
+
+// ListCharacters returns the roster by name, or only the active party.
+func ListCharacters(db *sqlx.DB, activeOnly bool) ([]Character, error) {
+	var characters []Character
+	query := `SELECT ` + characterColumns + ` FROM characters`
+	if activeOnly {
+		query += ` WHERE is_active = 1`
+	}
+	if err := db.Select(&characters, query+` ORDER BY name`); err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+	return characters, nil
+}
+
+func DeleteCharacter(tx *sqlx.Tx, id int64) error {
+	if _, err := tx.Exec("DELETE FROM characters WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete character: %w", err)
+	}
+	return nil
+}
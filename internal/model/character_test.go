@@ -0,0 +1,74 @@
+package model
+
+import "testing"
+
+func TestCharacter(t *testing.T) {
+	database := newTestDB(t)
+
+	hp, ac := 12, 4
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	theron := &Character{Name: "Theron", Player: stringPtr("Alex"), Class: stringPtr("Fighter"), HPMax: &hp, HPCurrent: &hp,
+		ArmorClass: &ac, LightSource: stringPtr("torch"), IsActive: true}
+	gareth := &Character{Name: "Gareth", Class: stringPtr("Cleric"), Level: 2, IsActive: false}
+	for _, character := range []*Character{theron, gareth} {
+		if err := CreateCharacter(tx, character); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create character: %v", err)
+		}
+	}
+	if err := SetCharacterHP(tx, theron.ID, 8); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to set character hp: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	found, err := GetCharacterByName(database, "THERON")
+	if err != nil {
+		t.Fatalf("Failed to get character: %v", err)
+	}
+	if found == nil || found.Level != 1 || *found.HPCurrent != 8 || *found.HPMax != 12 || *found.Player != "Alex" {
+		t.Errorf("Unexpected character: %+v", found)
+	}
+
+	party, err := ListCharacters(database, true)
+	if err != nil {
+		t.Fatalf("Failed to list characters: %v", err)
+	}
+	if len(party) != 1 || party[0].Name != "Theron" {
+		t.Errorf("Expected only Theron in the party, got %+v", party)
+	}
+	roster, err := ListCharacters(database, false)
+	if err != nil || len(roster) != 2 || roster[0].Name != "Gareth" {
+		t.Errorf("Expected the whole roster by name, got %+v, %v", roster, err)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	gareth.IsActive = true
+	gareth.XP = 1500
+	if err := UpdateCharacter(tx, gareth); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to update character: %v", err)
+	}
+	if err := DeleteCharacter(tx, theron.ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	if found, err := GetCharacter(database, gareth.ID); err != nil || found == nil || !found.IsActive || found.XP != 1500 {
+		t.Errorf("Expected Gareth updated, got %+v, %v", found, err)
+	}
+	if found, err := GetCharacter(database, theron.ID); err != nil || found != nil {
+		t.Errorf("Expected Theron deleted, got %+v, %v", found, err)
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CombatLogEntry is a line in an encounter's combat log, such as the
+// outcome of an attack.
+type CombatLogEntry struct {
+	ID          int64     `db:"id"`
+	EncounterID int64     `db:"encounter_id"`
+	Round       int64     `db:"round"`
+	Message     string    `db:"message"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func CreateCombatLogEntry(tx *sqlx.Tx, entry *CombatLogEntry) error {
+	query := `INSERT INTO combat_log (encounter_id, round, message) VALUES (?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, entry.EncounterID, entry.Round, entry.Message)
+	if err := row.Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return fmt.Errorf("failed to write combat log: %w", err)
+	}
+	return nil
+}
+
+// ListCombatLog returns the latest limit lines of an encounter's combat
+// log, oldest first. A limit of 0 returns the whole log.
+func ListCombatLog(db *sqlx.DB, encounterID int64, limit int) ([]CombatLogEntry, error) {
+	query := `SELECT id, encounter_id, round, message, created_at FROM (
+			  SELECT * FROM combat_log WHERE encounter_id = ? ORDER BY id DESC LIMIT ?
+			  ) ORDER BY id`
+	if limit <= 0 {
+		limit = -1
+	}
+
+	var entries []CombatLogEntry
+	if err := db.Select(&entries, query, encounterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list combat log: %w", err)
+	}
+	return entries, nil
+}
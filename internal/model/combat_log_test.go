@@ -0,0 +1,41 @@
+package model
+
+import "testing"
+
+func TestCombatLog(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	encounter := &Encounter{SessionID: session.ID, IsActive: true}
+	if err := CreateEncounter(tx, encounter); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create encounter: %v", err)
+	}
+	for i, message := range []string{"Fighter misses Goblin", "Goblin hits Fighter for 3", "Fighter hits Goblin for 5"} {
+		entry := &CombatLogEntry{EncounterID: encounter.ID, Round: int64(i/2 + 1), Message: message}
+		if err := CreateCombatLogEntry(tx, entry); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to write combat log: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	entries, err := ListCombatLog(database, encounter.ID, 2)
+	if err != nil {
+		t.Fatalf("Failed to list combat log: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Message != "Goblin hits Fighter for 3" || entries[1].Round != 2 {
+		t.Errorf("Expected the latest two lines oldest first, got %+v", entries)
+	}
+
+	all, err := ListCombatLog(database, encounter.ID, 0)
+	if err != nil || len(all) != 3 {
+		t.Errorf("Expected the whole log, got %+v, %v", all, err)
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	ConditionRounds = "round"
+	ConditionTurns  = "turn"
+)
+
+// Condition is a status effect on a combatant. Conditions with a Unit wear
+// off after Remaining rounds or turns; those without last until removed.
+type Condition struct {
+	Name      string `json:"name"`
+	Unit      string `json:"unit,omitempty"`
+	Remaining int64  `json:"remaining,omitempty"`
+}
+
+// String describes a condition, e.g. "poisoned (3 rounds)" or "prone".
+func (c Condition) String() string {
+	if c.Unit == "" {
+		return c.Name
+	}
+	unit := c.Unit + "s"
+	if c.Remaining == 1 {
+		unit = c.Unit
+	}
+	return fmt.Sprintf("%s (%d %s)", c.Name, c.Remaining, unit)
+}
+
+// DecodeConditions parses the conditions column.
+func DecodeConditions(encoded string) ([]Condition, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	var conditions []Condition
+	if err := json.Unmarshal([]byte(encoded), &conditions); err != nil {
+		return nil, fmt.Errorf("failed to decode conditions: %w", err)
+	}
+	return conditions, nil
+}
+
+// FormatConditions lists conditions for display, e.g. "poisoned (3
+// rounds), prone". Undecodable conditions are shown as they are stored.
+func FormatConditions(encoded string) string {
+	conditions, err := DecodeConditions(encoded)
+	if err != nil {
+		return encoded
+	}
+	names := make([]string, len(conditions))
+	for i, condition := range conditions {
+		names[i] = condition.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+func SetCombatantConditions(tx *sqlx.Tx, id int64, conditions []Condition) error {
+	if conditions == nil {
+		conditions = []Condition{}
+	}
+	encoded, err := json.Marshal(conditions)
+	if err != nil {
+		return fmt.Errorf("failed to encode conditions: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE initiative_order SET conditions = ? WHERE id = ?", string(encoded), id); err != nil {
+		return fmt.Errorf("failed to set combatant conditions: %w", err)
+	}
+	return nil
+}
+
+// ListTimedConditions returns the combatants in a session's running
+// encounters that have conditions which wear off.
+func ListTimedConditions(tx *sqlx.Tx, sessionID int64) ([]Combatant, error) {
+	query := combatantQuery + `
+			  JOIN encounters e ON e.id = io.encounter_id
+			  WHERE e.session_id = ? AND e.is_active = 1
+			  AND EXISTS (SELECT 1 FROM json_each(io.conditions) c WHERE json_extract(c.value, '$.unit') != '')
+			  ORDER BY io.id`
+
+	var combatants []Combatant
+	if err := tx.Select(&combatants, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list timed conditions: %w", err)
+	}
+	return combatants, nil
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Consumable usages: supplies used per day, such as rations, go down by
+// one for each day that passes, and supplies used per use only when they
+// are spent.
+const (
+	ConsumablePerDay = "per_day"
+	ConsumablePerUse = "per_use"
+)
+
+// Consumable is a stack of supplies a character carries, such as rations,
+// oil flasks or arrows.
+type Consumable struct {
+	ID          int64     `db:"id"`
+	CharacterID int64     `db:"character_id"`
+	Name        string    `db:"name"`
+	Quantity    int       `db:"quantity"`
+	Usage       string    `db:"usage"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+const consumableColumns = `id, character_id, name, quantity, usage, created_at`
+
+func CreateConsumable(tx *sqlx.Tx, consumable *Consumable) error {
+	if consumable.Usage == "" {
+		consumable.Usage = ConsumablePerUse
+	}
+	query := `INSERT INTO consumables (character_id, name, quantity, usage)
+			  VALUES (?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, consumable.CharacterID, consumable.Name, consumable.Quantity, consumable.Usage)
+	if err := row.Scan(&consumable.ID, &consumable.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create consumable: %w", err)
+	}
+	return nil
+}
+
+// SetConsumable sets how many of a consumable are left and how it is used.
+func SetConsumable(tx *sqlx.Tx, id int64, quantity int, usage string) error {
+	query := "UPDATE consumables SET quantity = ?, usage = ? WHERE id = ?"
+	if _, err := tx.Exec(query, quantity, usage, id); err != nil {
+		return fmt.Errorf("failed to set consumable: %w", err)
+	}
+	return nil
+}
+
+// GetConsumableByName looks up one of a character's consumables by name,
+// ignoring case.
+func GetConsumableByName(db *sqlx.DB, characterID int64, name string) (*Consumable, error) {
+	var consumable Consumable
+	query := `SELECT ` + consumableColumns + ` FROM consumables WHERE character_id = ? AND name = ?`
+	err := db.Get(&consumable, query, characterID, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumable: %w", err)
+	}
+	return &consumable, nil
+}
+
+// ListConsumables returns a character's consumables by name.
+func ListConsumables(db *sqlx.DB, characterID int64) ([]Consumable, error) {
+	var consumables []Consumable
+	query := `SELECT ` + consumableColumns + ` FROM consumables WHERE character_id = ? ORDER BY name`
+	if err := db.Select(&consumables, query, characterID); err != nil {
+		return nil, fmt.Errorf("failed to list consumables: %w", err)
+	}
+	return consumables, nil
+}
+
+// CarriedConsumable is a consumable with the name of the character who
+// carries it.
+type CarriedConsumable struct {
+	Consumable
+	CharacterName string `db:"character_name"`
+}
+
+// ListDailyConsumables returns the consumables the active characters use
+// up each day and still have some of.
+func ListDailyConsumables(tx *sqlx.Tx) ([]CarriedConsumable, error) {
+	var consumables []CarriedConsumable
+	query := `SELECT c.id, c.character_id, c.name, c.quantity, c.usage, c.created_at, ch.name AS character_name
+			  FROM consumables c JOIN characters ch ON ch.id = c.character_id
+			  WHERE ch.is_active = 1 AND c.usage = ? AND c.quantity > 0 ORDER BY c.id`
+	if err := tx.Select(&consumables, query, ConsumablePerDay); err != nil {
+		return nil, fmt.Errorf("failed to list daily consumables: %w", err)
+	}
+	return consumables, nil
+}
+
+func DeleteConsumable(tx *sqlx.Tx, id int64) error {
+	if _, err := tx.Exec("DELETE FROM consumables WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete consumable: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package model
+
+import "testing"
+
+func TestConsumable(t *testing.T) {
+	database := newTestDB(t)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	theron := &Character{Name: "Theron", IsActive: true}
+	gareth := &Character{Name: "Gareth", IsActive: false}
+	for _, character := range []*Character{theron, gareth} {
+		if err := CreateCharacter(tx, character); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create character: %v", err)
+		}
+	}
+	rations := &Consumable{CharacterID: theron.ID, Name: "Rations", Quantity: 7, Usage: ConsumablePerDay}
+	arrows := &Consumable{CharacterID: theron.ID, Name: "Arrows", Quantity: 20}
+	resting := &Consumable{CharacterID: gareth.ID, Name: "Rations", Quantity: 3, Usage: ConsumablePerDay}
+	for _, consumable := range []*Consumable{rations, arrows, resting} {
+		if err := CreateConsumable(tx, consumable); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create consumable: %v", err)
+		}
+	}
+	if arrows.Usage != ConsumablePerUse {
+		t.Errorf("Expected consumables to be used per use by default, got %q", arrows.Usage)
+	}
+	if err := SetConsumable(tx, arrows.ID, 12, ConsumablePerUse); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to set consumable: %v", err)
+	}
+
+	daily, err := ListDailyConsumables(tx)
+	if err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to list daily consumables: %v", err)
+	}
+	if len(daily) != 1 || daily[0].ID != rations.ID || daily[0].CharacterName != "Theron" {
+		t.Errorf("Expected only the active character's rations, got %+v", daily)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	found, err := GetConsumableByName(database, theron.ID, "ARROWS")
+	if err != nil {
+		t.Fatalf("Failed to get consumable: %v", err)
+	}
+	if found == nil || found.Quantity != 12 {
+		t.Errorf("Expected 12 arrows, got %+v", found)
+	}
+	if found, err := GetConsumableByName(database, gareth.ID, "Arrows"); err != nil || found != nil {
+		t.Errorf("Expected Gareth to have no arrows, got %+v, %v", found, err)
+	}
+
+	consumables, err := ListConsumables(database, theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to list consumables: %v", err)
+	}
+	if len(consumables) != 2 || consumables[0].Name != "Arrows" || consumables[1].Name != "Rations" {
+		t.Errorf("Expected Theron's consumables by name, got %+v", consumables)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if err := CreateConsumable(tx, &Consumable{CharacterID: theron.ID, Name: "rations"}); err == nil {
+		t.Error("Expected an error creating a second stack of rations")
+	}
+	tx.Rollback()
+}
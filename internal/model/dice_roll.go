@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type DiceRoll struct {
+	ID         int64     `db:"id"`
+	Expression string    `db:"expression"`
+	Macro      *string   `db:"macro"`
+	Total      int       `db:"total"`
+	Breakdown  *string   `db:"breakdown"`
+	SessionID  *int64    `db:"session_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func CreateDiceRoll(tx *sqlx.Tx, roll *DiceRoll) error {
+	query := `INSERT INTO dice_rolls (expression, macro, total, breakdown, session_id) 
+			  VALUES (?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, roll.Expression, roll.Macro, roll.Total, roll.Breakdown, roll.SessionID)
+	return row.Scan(&roll.ID, &roll.CreatedAt)
+}
+
+// ListDiceRolls returns the rolls made in a session, newest first. A nil
+// sessionID lists rolls from every session.
+func ListDiceRolls(db *sqlx.DB, sessionID *int64, limit int) ([]DiceRoll, error) {
+	query := `SELECT id, expression, macro, total, breakdown, session_id, created_at 
+			  FROM dice_rolls`
+	var args []interface{}
+	if sessionID != nil {
+		query += " WHERE session_id = ?"
+		args = append(args, *sessionID)
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	var rolls []DiceRoll
+	if err := db.Select(&rolls, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list dice rolls: %w", err)
+	}
+	return rolls, nil
+}
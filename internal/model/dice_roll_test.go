@@ -0,0 +1,53 @@
+package model
+
+import "testing"
+
+func TestDiceRollCRUD(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	rolls := []*DiceRoll{
+		{Expression: "2d6", Macro: stringPtr("reaction"), Total: 7, Breakdown: stringPtr(`[]`), SessionID: &session.ID},
+		{Expression: "1d20+2", Total: 15, SessionID: &session.ID},
+		{Expression: "1d6", Total: 3},
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	for _, roll := range rolls {
+		if err := CreateDiceRoll(tx, roll); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create dice roll: %v", err)
+		}
+		if roll.ID == 0 {
+			tx.Rollback()
+			t.Fatal("Dice roll ID was not set after creation")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	all, err := ListDiceRolls(database, nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to list dice rolls: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != rolls[2].ID {
+		t.Fatalf("Expected 3 rolls newest first, got %+v", all)
+	}
+
+	inSession, err := ListDiceRolls(database, &session.ID, 1)
+	if err != nil {
+		t.Fatalf("Failed to list session dice rolls: %v", err)
+	}
+	if len(inSession) != 1 || inSession[0].Expression != "1d20+2" {
+		t.Fatalf("Expected latest session roll 1d20+2, got %+v", inSession)
+	}
+
+	reaction := all[2]
+	if reaction.Macro == nil || *reaction.Macro != "reaction" || reaction.Total != 7 {
+		t.Fatalf("Unexpected reaction roll: %+v", reaction)
+	}
+}
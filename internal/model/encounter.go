@@ -8,34 +8,88 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// Encounter is a fight or other scene tracked in initiative order. Round
+// counts from 1 once the first combatant acts, and CurrentCombatantID is
+// whose turn it is.
 type Encounter struct {
-	ID          int64     `db:"id"`
-	SessionID   int64     `db:"session_id"`
-	Name        *string   `db:"name"`
-	Description *string   `db:"description"`
-	IsActive    bool      `db:"is_active"`
-	CreatedAt   time.Time `db:"created_at"`
+	ID                 int64      `db:"id"`
+	SessionID          int64      `db:"session_id"`
+	Name               *string    `db:"name"`
+	Description        *string    `db:"description"`
+	IsActive           bool       `db:"is_active"`
+	Round              int64      `db:"round"`
+	CurrentCombatantID *int64     `db:"current_combatant_id"`
+	EndedAt            *time.Time `db:"ended_at"`
+	CreatedAt          time.Time  `db:"created_at"`
 }
 
+const encounterColumns = `id, session_id, name, description, is_active, round, current_combatant_id, ended_at, created_at`
+
 type InitiativeOrder struct {
-	ID            int64     `db:"id"`
-	EncounterID   int64     `db:"encounter_id"`
-	NPCID         *int64    `db:"npc_id"`
-	CharacterName *string   `db:"character_name"`
-	Initiative    int       `db:"initiative"`
-	HPCurrent     *int      `db:"hp_current"`
-	HPMax         *int      `db:"hp_max"`
-	IsActive      bool      `db:"is_active"`
-	CreatedAt     time.Time `db:"created_at"`
+	ID            int64   `db:"id"`
+	EncounterID   int64   `db:"encounter_id"`
+	NPCID         *int64  `db:"npc_id"`
+	CharacterName *string `db:"character_name"`
+	Initiative    int     `db:"initiative"`
+	HPCurrent     *int    `db:"hp_current"`
+	HPMax         *int    `db:"hp_max"`
+	IsActive      bool    `db:"is_active"`
+	Side          string  `db:"side"`
+	Dex           *int    `db:"dex"`
+	MonsterID     *int64  `db:"monster_id"`
+	// CharacterID is the roster character the combatant stands for; its HP
+	// is kept in step with the character's.
+	CharacterID *int64 `db:"character_id"`
+	// ArmorClass is in the campaign's AC system. AttackBonus is added to
+	// to-hit rolls, with THAC0 19 as +0, and Damage lists the dice of each
+	// attack in the combatant's routine, separated by "/".
+	ArmorClass  *int    `db:"armor_class"`
+	AttackBonus *int    `db:"attack_bonus"`
+	Damage      *string `db:"damage"`
+	// Conditions is a JSON array of Condition; see DecodeConditions.
+	Conditions string    `db:"conditions"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+const (
+	SideParty    = "party"
+	SideMonsters = "monsters"
+)
+
+const initiativeOrderColumns = `id, encounter_id, npc_id, character_name, initiative, hp_current, hp_max, is_active, side, dex, monster_id, character_id, armor_class, attack_bonus, damage, conditions, created_at`
+
+// Dead reports whether the combatant has been brought to 0 HP.
+func (c InitiativeOrder) Dead() bool {
+	return c.HPCurrent != nil && *c.HPCurrent <= 0
 }
 
 type Combatant struct {
-	ID         int64  `db:"id"`
-	Name       string `db:"name"`
-	Initiative int    `db:"initiative"`
-	HPCurrent  *int   `db:"hp_current"`
-	HPMax      *int   `db:"hp_max"`
-	IsNPC      bool   `db:"is_npc"`
+	ID          int64   `db:"id"`
+	EncounterID int64   `db:"encounter_id"`
+	Name        string  `db:"name"`
+	Initiative  int     `db:"initiative"`
+	HPCurrent   *int    `db:"hp_current"`
+	HPMax       *int    `db:"hp_max"`
+	IsNPC       bool    `db:"is_npc"`
+	IsActive    bool    `db:"is_active"`
+	Side        string  `db:"side"`
+	Dex         *int    `db:"dex"`
+	MonsterID   *int64  `db:"monster_id"`
+	CharacterID *int64  `db:"character_id"`
+	ArmorClass  *int    `db:"armor_class"`
+	AttackBonus *int    `db:"attack_bonus"`
+	Damage      *string `db:"damage"`
+	Conditions  string  `db:"conditions"`
+}
+
+// Dead reports whether the combatant has been brought to 0 HP.
+func (c Combatant) Dead() bool {
+	return c.HPCurrent != nil && *c.HPCurrent <= 0
+}
+
+// CanAct reports whether the combatant takes turns: it is active and alive.
+func (c Combatant) CanAct() bool {
+	return c.IsActive && !c.Dead()
 }
 
 func CreateEncounter(tx *sqlx.Tx, encounter *Encounter) error {
@@ -45,10 +99,22 @@ func CreateEncounter(tx *sqlx.Tx, encounter *Encounter) error {
 	return row.Scan(&encounter.ID, &encounter.CreatedAt)
 }
 
+func GetEncounter(db *sqlx.DB, id int64) (*Encounter, error) {
+	var encounter Encounter
+	query := `SELECT ` + encounterColumns + ` FROM encounters WHERE id = ?`
+	err := db.Get(&encounter, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encounter: %w", err)
+	}
+	return &encounter, nil
+}
+
 func GetActiveEncounter(db *sqlx.DB, sessionID int64) (*Encounter, error) {
 	var encounter Encounter
-	query := `SELECT id, session_id, name, description, is_active, created_at 
-			  FROM encounters WHERE session_id = ? AND is_active = 1 LIMIT 1`
+	query := `SELECT ` + encounterColumns + ` FROM encounters WHERE session_id = ? AND is_active = 1 LIMIT 1`
 	err := db.Get(&encounter, query, sessionID)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -59,6 +125,24 @@ func GetActiveEncounter(db *sqlx.DB, sessionID int64) (*Encounter, error) {
 	return &encounter, nil
 }
 
+// EndEncounter marks an encounter as over.
+func EndEncounter(tx *sqlx.Tx, id int64) error {
+	query := "UPDATE encounters SET is_active = 0, ended_at = CURRENT_TIMESTAMP WHERE id = ?"
+	if _, err := tx.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to end encounter: %w", err)
+	}
+	return nil
+}
+
+// SetEncounterTurn records the round and whose turn it is.
+func SetEncounterTurn(tx *sqlx.Tx, id, round int64, combatantID *int64) error {
+	query := "UPDATE encounters SET round = ?, current_combatant_id = ? WHERE id = ?"
+	if _, err := tx.Exec(query, round, combatantID, id); err != nil {
+		return fmt.Errorf("failed to set encounter turn: %w", err)
+	}
+	return nil
+}
+
 func AddCombatant(tx *sqlx.Tx, encounterID int64, npcID *int64, characterName *string, initiative int, hpCurrent, hpMax *int) (*InitiativeOrder, error) {
 	initOrder := &InitiativeOrder{
 		EncounterID:   encounterID,
@@ -69,28 +153,140 @@ func AddCombatant(tx *sqlx.Tx, encounterID int64, npcID *int64, characterName *s
 		HPMax:         hpMax,
 		IsActive:      true,
 	}
+	if err := CreateCombatant(tx, initOrder); err != nil {
+		return nil, err
+	}
+	return initOrder, nil
+}
+
+// CreateCombatant inserts a combatant with every field set. An empty side
+// puts it with the monsters.
+func CreateCombatant(tx *sqlx.Tx, combatant *InitiativeOrder) error {
+	if combatant.Side == "" {
+		combatant.Side = SideMonsters
+	}
+	if combatant.Conditions == "" {
+		combatant.Conditions = "[]"
+	}
+	query := `INSERT INTO initiative_order (encounter_id, npc_id, character_name, initiative, hp_current, hp_max, is_active, side, dex, monster_id, 
+			  character_id, armor_class, attack_bonus, damage, conditions) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, combatant.EncounterID, combatant.NPCID, combatant.CharacterName,
+		combatant.Initiative, combatant.HPCurrent, combatant.HPMax, combatant.IsActive, combatant.Side, combatant.Dex,
+		combatant.MonsterID, combatant.CharacterID, combatant.ArmorClass, combatant.AttackBonus, combatant.Damage, combatant.Conditions)
+	if err := row.Scan(&combatant.ID, &combatant.CreatedAt); err != nil {
+		return fmt.Errorf("failed to add combatant: %w", err)
+	}
+	return nil
+}
 
-	query := `INSERT INTO initiative_order (encounter_id, npc_id, character_name, initiative, hp_current, hp_max, is_active) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id, created_at`
-	row := tx.QueryRow(query, initOrder.EncounterID, initOrder.NPCID, initOrder.CharacterName,
-		initOrder.Initiative, initOrder.HPCurrent, initOrder.HPMax, initOrder.IsActive)
-	err := row.Scan(&initOrder.ID, &initOrder.CreatedAt)
+func GetCombatant(db *sqlx.DB, id int64) (*InitiativeOrder, error) {
+	var combatant InitiativeOrder
+	query := `SELECT ` + initiativeOrderColumns + ` FROM initiative_order WHERE id = ?`
+	err := db.Get(&combatant, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to add combatant: %w", err)
+		return nil, fmt.Errorf("failed to get combatant: %w", err)
 	}
-	return initOrder, nil
+	return &combatant, nil
 }
 
-func ListActiveBySort(db *sqlx.DB, encounterID int64) ([]Combatant, error) {
-	query := `SELECT 
+// SetCombatantHP sets a combatant's current hit points.
+func SetCombatantHP(tx *sqlx.Tx, id int64, hp int) error {
+	query := "UPDATE initiative_order SET hp_current = ? WHERE id = ?"
+	if _, err := tx.Exec(query, hp, id); err != nil {
+		return fmt.Errorf("failed to set combatant hp: %w", err)
+	}
+	return nil
+}
+
+// SetCombatantActive takes a combatant out of the turn order, or puts it
+// back.
+func SetCombatantActive(tx *sqlx.Tx, id int64, active bool) error {
+	query := "UPDATE initiative_order SET is_active = ? WHERE id = ?"
+	if _, err := tx.Exec(query, active, id); err != nil {
+		return fmt.Errorf("failed to set combatant active: %w", err)
+	}
+	return nil
+}
+
+func SetCombatantInitiative(tx *sqlx.Tx, id int64, initiative int) error {
+	query := "UPDATE initiative_order SET initiative = ? WHERE id = ?"
+	if _, err := tx.Exec(query, initiative, id); err != nil {
+		return fmt.Errorf("failed to set combatant initiative: %w", err)
+	}
+	return nil
+}
+
+func SetCombatantSide(tx *sqlx.Tx, id int64, side string) error {
+	query := "UPDATE initiative_order SET side = ? WHERE id = ?"
+	if _, err := tx.Exec(query, side, id); err != nil {
+		return fmt.Errorf("failed to set combatant side: %w", err)
+	}
+	return nil
+}
+
+func RemoveCombatant(tx *sqlx.Tx, id int64) error {
+	if _, err := tx.Exec("DELETE FROM initiative_order WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to remove combatant: %w", err)
+	}
+	return nil
+}
+
+// combatantQuery selects combatants with their display names in turn order.
+const combatantQuery = `SELECT 
 				io.id,
-				COALESCE(n.name, io.character_name) as name,
+				io.encounter_id,
+				COALESCE(n.name, io.character_name, 'Combatant ' || io.id) as name,
 				io.initiative,
 				io.hp_current,
 				io.hp_max,
-				CASE WHEN io.npc_id IS NOT NULL THEN 1 ELSE 0 END as is_npc
+				CASE WHEN io.npc_id IS NOT NULL THEN 1 ELSE 0 END as is_npc,
+				io.is_active,
+				io.side,
+				io.dex,
+				io.monster_id,
+				io.character_id,
+				io.armor_class,
+				io.attack_bonus,
+				io.damage,
+				io.conditions
 			  FROM initiative_order io
-			  LEFT JOIN npcs n ON io.npc_id = n.id
+			  LEFT JOIN npcs n ON io.npc_id = n.id`
+
+// FindCombatant returns a combatant with its display name, or nil when it
+// does not exist.
+func FindCombatant(db sqlx.Queryer, id int64) (*Combatant, error) {
+	var combatant Combatant
+	err := sqlx.Get(db, &combatant, combatantQuery+` WHERE io.id = ?`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find combatant: %w", err)
+	}
+	return &combatant, nil
+}
+
+// ListCombatants returns every combatant in an encounter, including those
+// taken out of the turn order, by initiative and then in the order they
+// were added.
+func ListCombatants(db sqlx.Queryer, encounterID int64) ([]Combatant, error) {
+	query := combatantQuery + `
+			  WHERE io.encounter_id = ?
+			  ORDER BY io.initiative DESC, io.id ASC`
+
+	var combatants []Combatant
+	if err := sqlx.Select(db, &combatants, query, encounterID); err != nil {
+		return nil, fmt.Errorf("failed to list combatants: %w", err)
+	}
+	return combatants, nil
+}
+
+func ListActiveBySort(db *sqlx.DB, encounterID int64) ([]Combatant, error) {
+	query := combatantQuery + `
 			  WHERE io.encounter_id = ? AND io.is_active = 1
 			  ORDER BY io.initiative DESC, io.id ASC`
 
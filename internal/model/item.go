@@ -0,0 +1,79 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Item is a stack of gear in a character's inventory. Slots and Weight,
+// in coins, are for the whole stack. Stashed items are left somewhere safe
+// and are not carried.
+type Item struct {
+	ID          int64     `db:"id"`
+	CharacterID int64     `db:"character_id"`
+	Name        string    `db:"name"`
+	Quantity    int       `db:"quantity"`
+	Slots       int       `db:"slots"`
+	Weight      int       `db:"weight"`
+	Stashed     bool      `db:"stashed"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+const itemColumns = `id, character_id, name, quantity, slots, weight, stashed, created_at`
+
+func CreateItem(tx *sqlx.Tx, item *Item) error {
+	if item.Quantity < 1 {
+		item.Quantity = 1
+	}
+	query := `INSERT INTO items (character_id, name, quantity, slots, weight, stashed)
+			  VALUES (?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, item.CharacterID, item.Name, item.Quantity, item.Slots, item.Weight, item.Stashed)
+	if err := row.Scan(&item.ID, &item.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create item: %w", err)
+	}
+	return nil
+}
+
+func UpdateItem(tx *sqlx.Tx, item *Item) error {
+	query := `UPDATE items SET name = ?, quantity = ?, slots = ?, weight = ?, stashed = ? WHERE id = ?`
+	_, err := tx.Exec(query, item.Name, item.Quantity, item.Slots, item.Weight, item.Stashed, item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+	return nil
+}
+
+// GetItemByName looks up an item in a character's inventory by name,
+// ignoring case.
+func GetItemByName(db *sqlx.DB, characterID int64, name string) (*Item, error) {
+	var item Item
+	query := `SELECT ` + itemColumns + ` FROM items WHERE character_id = ? AND name = ?`
+	err := db.Get(&item, query, characterID, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+	return &item, nil
+}
+
+// ListItems returns a character's inventory, carried items first, by name.
+func ListItems(db *sqlx.DB, characterID int64) ([]Item, error) {
+	var items []Item
+	query := `SELECT ` + itemColumns + ` FROM items WHERE character_id = ? ORDER BY stashed, name`
+	if err := db.Select(&items, query, characterID); err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	return items, nil
+}
+
+func DeleteItem(tx *sqlx.Tx, id int64) error {
+	if _, err := tx.Exec("DELETE FROM items WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package model
+
+import "testing"
+
+func TestItem(t *testing.T) {
+	database := newTestDB(t)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	theron := &Character{Name: "Theron", IsActive: true}
+	if err := CreateCharacter(tx, theron); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create character: %v", err)
+	}
+	sword := &Item{CharacterID: theron.ID, Name: "Sword", Slots: 1, Weight: 60}
+	tent := &Item{CharacterID: theron.ID, Name: "Tent", Slots: 4, Weight: 200, Stashed: true}
+	arrows := &Item{CharacterID: theron.ID, Name: "Arrows", Quantity: 20, Slots: 1, Weight: 20}
+	for _, item := range []*Item{sword, tent, arrows} {
+		if err := CreateItem(tx, item); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create item: %v", err)
+		}
+	}
+	if sword.Quantity != 1 {
+		t.Errorf("Expected items to default to 1, got %d", sword.Quantity)
+	}
+	arrows.Quantity = 12
+	arrows.Stashed = true
+	if err := UpdateItem(tx, arrows); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to update item: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	found, err := GetItemByName(database, theron.ID, "ARROWS")
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if found == nil || found.Quantity != 12 || !found.Stashed {
+		t.Errorf("Expected 12 stashed arrows, got %+v", found)
+	}
+
+	items, err := ListItems(database, theron.ID)
+	if err != nil {
+		t.Fatalf("Failed to list items: %v", err)
+	}
+	if len(items) != 3 || items[0].Name != "Sword" || items[1].Name != "Arrows" || items[2].Name != "Tent" {
+		t.Errorf("Expected carried items first, then stashed, by name, got %+v", items)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if err := DeleteItem(tx, sword.ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+	if found, err := GetItemByName(database, theron.ID, "Sword"); err != nil || found != nil {
+		t.Errorf("Expected the sword deleted, got %+v, %v", found, err)
+	}
+}
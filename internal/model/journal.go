@@ -0,0 +1,278 @@
+package model
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/db"
+)
+
+const (
+	JournalUndo = "undo"
+	JournalRedo = "redo"
+)
+
+// journalTables are the tables whose rows journal entries may snapshot and
+// restore.
+var journalTables = map[string]bool{
+	"sessions":         true,
+	"time_events":      true,
+	"wandering_checks": true,
+	"encounters":       true,
+	"initiative_order": true,
+	"npcs":             true,
+	"combat_log":       true,
+	"treasure_found":   true,
+	"xp_ledger":        true,
+	"characters":       true,
+	"consumables":      true,
+	"items":            true,
+}
+
+var columnName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// JournalEntry is one state-changing action. Changes holds the JSON row
+// snapshots taken before and after it; see RowChanges.
+type JournalEntry struct {
+	ID          int64     `db:"id"`
+	SessionID   *int64    `db:"session_id"`
+	Action      string    `db:"action"`
+	Description string    `db:"description"`
+	Changes     string    `db:"changes"`
+	RevertsID   *int64    `db:"reverts_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// RowChange is the state of one row before and after an action. A nil
+// Before means the action created the row, a nil After that it deleted it.
+type RowChange struct {
+	Table  string         `json:"table"`
+	ID     int64          `json:"id"`
+	Before map[string]any `json:"before"`
+	After  map[string]any `json:"after"`
+}
+
+const journalColumns = `id, session_id, action, description, changes, reverts_id, created_at`
+
+// RowChanges decodes the entry's row snapshots.
+func (e JournalEntry) RowChanges() ([]RowChange, error) {
+	decoder := json.NewDecoder(strings.NewReader(e.Changes))
+	decoder.UseNumber()
+
+	var changes []RowChange
+	if err := decoder.Decode(&changes); err != nil {
+		return nil, fmt.Errorf("failed to decode journal entry %d: %w", e.ID, err)
+	}
+	return changes, nil
+}
+
+func CreateJournalEntry(tx *sqlx.Tx, entry *JournalEntry) error {
+	if entry.Changes == "" {
+		entry.Changes = "[]"
+	}
+	query := `INSERT INTO journal (session_id, action, description, changes, reverts_id)
+			  VALUES (?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := db.RetryableQueryRow(tx, query, entry.SessionID, entry.Action, entry.Description,
+		entry.Changes, entry.RevertsID)
+	return row.Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// FinishJournalEntry fills in the description and changes of an entry
+// written at the start of its transaction.
+func FinishJournalEntry(tx *sqlx.Tx, entry *JournalEntry) error {
+	query := `UPDATE journal SET description = ?, changes = ?, reverts_id = ? WHERE id = ?`
+	if _, err := db.RetryableExec(tx, query, entry.Description, entry.Changes, entry.RevertsID, entry.ID); err != nil {
+		return fmt.Errorf("failed to finish journal entry: %w", err)
+	}
+	return nil
+}
+
+// DiscardJournalEntry removes an entry in the transaction that wrote it,
+// for actions that turned out to change nothing.
+func DiscardJournalEntry(tx *sqlx.Tx, id int64) error {
+	if _, err := db.RetryableExec(tx, "DELETE FROM journal WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to discard journal entry: %w", err)
+	}
+	return nil
+}
+
+// ListJournal returns the latest entries, newest first. With a session,
+// only that session's entries and those belonging to no session are
+// listed.
+func ListJournal(db *sqlx.DB, sessionID *int64, limit int) ([]JournalEntry, error) {
+	query := `SELECT ` + journalColumns + ` FROM journal`
+	var args []interface{}
+	if sessionID != nil {
+		query += " WHERE session_id = ? OR session_id IS NULL"
+		args = append(args, *sessionID)
+	}
+	query += " ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	var entries []JournalEntry
+	if err := db.Select(&entries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list journal: %w", err)
+	}
+	return entries, nil
+}
+
+// UndoTarget returns the latest action in a session that is still in
+// effect, or nil when there is nothing to undo.
+func UndoTarget(tx *sqlx.Tx, sessionID int64) (*JournalEntry, error) {
+	query := `SELECT ` + journalColumns + ` FROM journal e
+			  WHERE (e.session_id = ? OR e.session_id IS NULL)
+			  AND e.action NOT IN (?, ?)
+			  AND COALESCE((SELECT r.action FROM journal r WHERE r.reverts_id = e.id
+			                ORDER BY r.id DESC LIMIT 1), ?) = ?
+			  ORDER BY e.id DESC LIMIT 1`
+	return getJournalTarget(tx, query, sessionID, JournalUndo, JournalRedo, JournalRedo, JournalRedo)
+}
+
+// RedoTarget returns the most recently undone action in a session, or nil
+// when there is nothing to redo. Recording a new action after an undo
+// leaves nothing to redo.
+func RedoTarget(tx *sqlx.Tx, sessionID int64) (*JournalEntry, error) {
+	query := `SELECT e.id, e.session_id, e.action, e.description, e.changes, e.reverts_id, e.created_at
+			  FROM journal e
+			  JOIN journal u ON u.id = (SELECT MAX(r.id) FROM journal r WHERE r.reverts_id = e.id)
+			  WHERE (e.session_id = ? OR e.session_id IS NULL)
+			  AND e.action NOT IN (?, ?) AND u.action = ?
+			  AND u.id > COALESCE((SELECT MAX(n.id) FROM journal n
+			                       WHERE (n.session_id = ? OR n.session_id IS NULL)
+			                       AND n.action NOT IN (?, ?)), 0)
+			  ORDER BY u.id DESC LIMIT 1`
+	return getJournalTarget(tx, query, sessionID, JournalUndo, JournalRedo, JournalUndo,
+		sessionID, JournalUndo, JournalRedo)
+}
+
+func getJournalTarget(tx *sqlx.Tx, query string, args ...interface{}) (*JournalEntry, error) {
+	var entry JournalEntry
+	err := tx.Get(&entry, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find journal entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// SnapshotRow returns every column of a row, or nil when it does not
+// exist. Times are stored as SQLite timestamps so they restore unchanged.
+func SnapshotRow(tx *sqlx.Tx, table string, id int64) (map[string]any, error) {
+	if !journalTables[table] {
+		return nil, fmt.Errorf("table %q cannot be journaled", table)
+	}
+
+	rows, err := tx.Queryx(`SELECT * FROM `+table+` WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s %d: %w", table, id, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	values := make(map[string]any)
+	if err := rows.MapScan(values); err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s %d: %w", table, id, err)
+	}
+	for column, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			values[column] = v.UTC().Format("2006-01-02 15:04:05")
+		case []byte:
+			values[column] = string(v)
+		}
+	}
+	return values, nil
+}
+
+// RestoreRow puts a row back to a snapshot: it is updated or re-inserted
+// with the snapshot's values, or deleted when values is nil.
+func RestoreRow(tx *sqlx.Tx, table string, id int64, values map[string]any) error {
+	if !journalTables[table] {
+		return fmt.Errorf("table %q cannot be journaled", table)
+	}
+
+	if values == nil {
+		if _, err := db.RetryableExec(tx, `DELETE FROM `+table+` WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to restore %s %d: %w", table, id, err)
+		}
+		return nil
+	}
+
+	var columns []string
+	for column := range values {
+		if !columnName.MatchString(column) {
+			return fmt.Errorf("invalid column %q in snapshot of %s %d", column, table, id)
+		}
+		if column != "id" {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	args := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		args = append(args, snapshotValue(values[column]))
+	}
+	args = append(args, id)
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = ?"
+	}
+	query := `UPDATE ` + table + ` SET ` + strings.Join(assignments, ", ") + ` WHERE id = ?`
+	result, err := db.RetryableExec(tx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s %d: %w", table, id, err)
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	columns = append(columns, "id")
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query = `INSERT INTO ` + table + ` (` + strings.Join(columns, ", ") + `) VALUES (` + placeholders + `)`
+	if _, err := db.RetryableExec(tx, query, args...); err != nil {
+		return fmt.Errorf("failed to restore %s %d: %w", table, id, err)
+	}
+	return nil
+}
+
+// SnapshotsEqual reports whether two snapshots hold the same values.
+func SnapshotsEqual(a, b map[string]any) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+// snapshotValue converts a value decoded from JSON back into one SQLite
+// stores with the same type it was read with.
+func snapshotValue(value any) any {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if n, err := number.Int64(); err == nil {
+		return n
+	}
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+	return number.String()
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestSnapshotAndRestoreRow(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	event := &TimeEvent{SessionID: session.ID, EventType: "torch_burnout", TriggerTurn: 6, Description: stringPtr("Torch burns out")}
+	if err := CreateTimeEvent(tx, event); err != nil {
+		t.Fatalf("Failed to create time event: %v", err)
+	}
+
+	before, err := SnapshotRow(tx, "time_events", event.ID)
+	if err != nil {
+		t.Fatalf("Failed to snapshot row: %v", err)
+	}
+	if before == nil || before["description"] != "Torch burns out" {
+		t.Fatalf("Unexpected snapshot %v", before)
+	}
+
+	if _, err := tx.Exec("DELETE FROM time_events WHERE id = ?", event.ID); err != nil {
+		t.Fatalf("Failed to delete time event: %v", err)
+	}
+	if missing, err := SnapshotRow(tx, "time_events", event.ID); err != nil || missing != nil {
+		t.Fatalf("Expected no snapshot of a deleted row, got %v, %v", missing, err)
+	}
+
+	// Round-trip the snapshot through JSON as the journal stores it.
+	entry := JournalEntry{Changes: `[{"table":"time_events","id":1,"before":{"id":1,"trigger_turn":6},"after":null}]`}
+	changes, err := entry.RowChanges()
+	if err != nil || len(changes) != 1 || changes[0].After != nil {
+		t.Fatalf("Unexpected decoded changes %+v, %v", changes, err)
+	}
+
+	if err := RestoreRow(tx, "time_events", event.ID, before); err != nil {
+		t.Fatalf("Failed to restore deleted row: %v", err)
+	}
+	restored, err := SnapshotRow(tx, "time_events", event.ID)
+	if err != nil {
+		t.Fatalf("Failed to snapshot restored row: %v", err)
+	}
+	if !SnapshotsEqual(before, restored) {
+		t.Errorf("Expected %v after restoring, got %v", before, restored)
+	}
+
+	if err := RestoreRow(tx, "time_events", event.ID, nil); err != nil {
+		t.Fatalf("Failed to restore row to nothing: %v", err)
+	}
+	if gone, _ := SnapshotRow(tx, "time_events", event.ID); gone != nil {
+		t.Errorf("Expected the row to be deleted, got %v", gone)
+	}
+
+	if _, err := SnapshotRow(tx, "dice_rolls", 1); err == nil {
+		t.Error("Expected an error snapshotting a table that is not journaled")
+	}
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Monster is an OSR stat block in the monster catalog. HitDice is in the
+// usual notation, such as "1-1", "2+1" or "1/2", Damage is a dice
+// expression for each attack, and Movement is in feet per turn.
+type Monster struct {
+	ID          int64     `db:"id"`
+	Name        string    `db:"name"`
+	ArmorClass  int       `db:"armor_class"`
+	HitDice     string    `db:"hit_dice"`
+	Attacks     *string   `db:"attacks"`
+	Damage      *string   `db:"damage"`
+	Movement    int       `db:"movement"`
+	Morale      int       `db:"morale"`
+	XP          int       `db:"xp"`
+	Alignment   *string   `db:"alignment"`
+	Description *string   `db:"description"`
+	Tags        *string   `db:"tags"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+const monsterColumns = `id, name, armor_class, hit_dice, attacks, damage, movement, morale, xp, alignment, 
+			  description, tags, created_at`
+
+func CreateMonster(tx *sqlx.Tx, monster *Monster) error {
+	query := `INSERT INTO monsters (name, armor_class, hit_dice, attacks, damage, movement, morale, xp, alignment, 
+			  description, tags) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, monster.Name, monster.ArmorClass, monster.HitDice, monster.Attacks, monster.Damage,
+		monster.Movement, monster.Morale, monster.XP, monster.Alignment, monster.Description, monster.Tags)
+	if err := row.Scan(&monster.ID, &monster.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create monster: %w", err)
+	}
+	return nil
+}
+
+// UpsertMonster creates a monster, or replaces the stat block of the
+// monster with the same name.
+func UpsertMonster(tx *sqlx.Tx, monster *Monster) error {
+	query := `INSERT INTO monsters (name, armor_class, hit_dice, attacks, damage, movement, morale, xp, alignment, 
+			  description, tags) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT (name) DO UPDATE SET armor_class = excluded.armor_class, hit_dice = excluded.hit_dice, 
+			  attacks = excluded.attacks, damage = excluded.damage, movement = excluded.movement, 
+			  morale = excluded.morale, xp = excluded.xp, alignment = excluded.alignment, 
+			  description = excluded.description, tags = excluded.tags
+			  RETURNING id, created_at`
+	row := tx.QueryRow(query, monster.Name, monster.ArmorClass, monster.HitDice, monster.Attacks, monster.Damage,
+		monster.Movement, monster.Morale, monster.XP, monster.Alignment, monster.Description, monster.Tags)
+	if err := row.Scan(&monster.ID, &monster.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save monster: %w", err)
+	}
+	return nil
+}
+
+func GetMonster(db *sqlx.DB, id int64) (*Monster, error) {
+	var monster Monster
+	query := `SELECT ` + monsterColumns + ` FROM monsters WHERE id = ?`
+	err := db.Get(&monster, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monster: %w", err)
+	}
+	return &monster, nil
+}
+
+// GetMonsterByName looks a monster up by name, ignoring case.
+func GetMonsterByName(db *sqlx.DB, name string) (*Monster, error) {
+	var monster Monster
+	query := `SELECT ` + monsterColumns + ` FROM monsters WHERE name = ?`
+	err := db.Get(&monster, query, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monster: %w", err)
+	}
+	return &monster, nil
+}
+
+// ListMonsters returns the whole catalog by name.
+func ListMonsters(db *sqlx.DB) ([]Monster, error) {
+	var monsters []Monster
+	query := `SELECT ` + monsterColumns + ` FROM monsters ORDER BY name`
+	if err := db.Select(&monsters, query); err != nil {
+		return nil, fmt.Errorf("failed to list monsters: %w", err)
+	}
+	return monsters, nil
+}
+
+func DeleteMonster(tx *sqlx.Tx, id int64) error {
+	if _, err := tx.Exec("DELETE FROM monsters WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete monster: %w", err)
+	}
+	return nil
+}
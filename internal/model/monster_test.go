@@ -0,0 +1,71 @@
+package model
+
+import "testing"
+
+func TestMonsterCRUD(t *testing.T) {
+	database := newTestDB(t)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	goblin := &Monster{Name: "Goblin", ArmorClass: 6, HitDice: "1-1", Attacks: stringPtr("1 × weapon"),
+		Damage: stringPtr("1d6"), Movement: 60, Morale: 7, XP: 5}
+	ogre := &Monster{Name: "Ogre", ArmorClass: 5, HitDice: "4+1", Movement: 90, Morale: 10, XP: 125}
+	for _, monster := range []*Monster{goblin, ogre} {
+		if err := CreateMonster(tx, monster); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create monster: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	found, err := GetMonsterByName(database, "goblin")
+	if err != nil || found == nil || found.ID != goblin.ID || found.HitDice != "1-1" || *found.Damage != "1d6" {
+		t.Fatalf("Expected the goblin by name, got %+v, %v", found, err)
+	}
+	if missing, err := GetMonsterByName(database, "Dragon"); err != nil || missing != nil {
+		t.Fatalf("Expected no dragon, got %+v, %v", missing, err)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	updated := &Monster{Name: "GOBLIN", ArmorClass: 7, HitDice: "1", Movement: 60, Morale: 6, XP: 10}
+	if err := UpsertMonster(tx, updated); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to upsert monster: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+	if updated.ID != goblin.ID {
+		t.Errorf("Expected the upsert to update goblin %d, got %d", goblin.ID, updated.ID)
+	}
+
+	monsters, err := ListMonsters(database)
+	if err != nil {
+		t.Fatalf("Failed to list monsters: %v", err)
+	}
+	if len(monsters) != 2 || monsters[0].Name != "Goblin" || monsters[0].ArmorClass != 7 || monsters[0].Damage != nil {
+		t.Fatalf("Unexpected catalog: %+v", monsters)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if err := DeleteMonster(tx, ogre.ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to delete monster: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+	if gone, err := GetMonster(database, ogre.ID); err != nil || gone != nil {
+		t.Fatalf("Expected the ogre to be deleted, got %+v, %v", gone, err)
+	}
+}
@@ -24,6 +24,13 @@ type NPC struct {
 	CreatedAt     time.Time  `db:"created_at"`
 }
 
+// NPC statuses: how an NPC stands toward the party.
+const (
+	NPCAlly    = "ally"
+	NPCNeutral = "neutral"
+	NPCHostile = "hostile"
+)
+
 func CreateNPC(tx *sqlx.Tx, npc *NPC) error {
 	query := `INSERT INTO npcs (name, description, location, status, motivation, secrets, tags) 
 			  VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id, created_at`
@@ -32,6 +39,18 @@ func CreateNPC(tx *sqlx.Tx, npc *NPC) error {
 	return row.Scan(&npc.ID, &npc.CreatedAt)
 }
 
+// UpdateNPC saves every editable field of an NPC.
+func UpdateNPC(tx *sqlx.Tx, npc *NPC) error {
+	query := `UPDATE npcs SET name = ?, description = ?, location = ?, status = ?, motivation = ?, 
+			  secrets = ?, tags = ? WHERE id = ?`
+	_, err := tx.Exec(query, npc.Name, npc.Description, npc.Location, npc.Status, npc.Motivation,
+		npc.Secrets, npc.Tags, npc.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update npc: %w", err)
+	}
+	return nil
+}
+
 func GetNPC(db *sqlx.DB, id int64) (*NPC, error) {
 	var npc NPC
 	query := `SELECT id, name, description, location, status, motivation, secrets, tags, 
@@ -46,6 +65,21 @@ func GetNPC(db *sqlx.DB, id int64) (*NPC, error) {
 	return &npc, nil
 }
 
+// GetNPCByName looks an NPC up by name, ignoring case.
+func GetNPCByName(db *sqlx.DB, name string) (*NPC, error) {
+	var npc NPC
+	query := `SELECT id, name, description, location, status, motivation, secrets, tags, 
+			  last_mentioned, created_at FROM npcs WHERE name = ? COLLATE NOCASE ORDER BY id LIMIT 1`
+	err := db.Get(&npc, query, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get npc: %w", err)
+	}
+	return &npc, nil
+}
+
 func SearchNPC(db *sqlx.DB, idx search.Index, query string, limit int) ([]NPC, error) {
 	if query == "" {
 		return nil, nil
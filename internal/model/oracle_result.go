@@ -0,0 +1,93 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type OracleResult struct {
+	ID            int64     `db:"id"`
+	TableName     *string   `db:"table_name"`
+	QueryContext  *string   `db:"query_context"`
+	Result        string    `db:"result"`
+	NestedResults *string   `db:"nested_results"`
+	SessionID     *int64    `db:"session_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// OracleResultFilter narrows ListOracleResults. Zero values match everything.
+type OracleResultFilter struct {
+	SessionID *int64
+	TableName string
+	Contains  string
+	Since     *time.Time
+	Limit     int
+}
+
+func CreateOracleResult(tx *sqlx.Tx, result *OracleResult) error {
+	query := `INSERT INTO oracle_results (table_name, query_context, result, nested_results, session_id) 
+			  VALUES (?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, result.TableName, result.QueryContext, result.Result,
+		result.NestedResults, result.SessionID)
+	return row.Scan(&result.ID, &result.CreatedAt)
+}
+
+func GetOracleResult(db *sqlx.DB, id int64) (*OracleResult, error) {
+	var result OracleResult
+	query := `SELECT id, table_name, query_context, result, nested_results, session_id, created_at 
+			  FROM oracle_results WHERE id = ?`
+	err := db.Get(&result, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oracle result: %w", err)
+	}
+	return &result, nil
+}
+
+// ListOracleResults returns past oracle results, newest first.
+func ListOracleResults(db *sqlx.DB, filter OracleResultFilter) ([]OracleResult, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.SessionID != nil {
+		conditions = append(conditions, "session_id = ?")
+		args = append(args, *filter.SessionID)
+	}
+	if filter.TableName != "" {
+		conditions = append(conditions, "table_name = ?")
+		args = append(args, filter.TableName)
+	}
+	if filter.Contains != "" {
+		conditions = append(conditions, "(result LIKE ? OR query_context LIKE ?)")
+		pattern := "%" + filter.Contains + "%"
+		args = append(args, pattern, pattern)
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+
+	query := `SELECT id, table_name, query_context, result, nested_results, session_id, created_at 
+			  FROM oracle_results`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	var results []OracleResult
+	err := db.Select(&results, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oracle results: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOracleResultCRUD(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	results := []*OracleResult{
+		{TableName: stringPtr("tavern_rumor"), QueryContext: stringPtr("[tavern_rumor]"), Result: "The mayor is a doppelganger", SessionID: &session.ID},
+		{QueryContext: stringPtr("1d6 {goblins|orcs}"), Result: "4 orcs", SessionID: &session.ID},
+		{TableName: stringPtr("tavern_rumor"), QueryContext: stringPtr("[tavern_rumor]"), Result: "The well is poisoned", NestedResults: stringPtr(`[]`)},
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	for _, result := range results {
+		if err := CreateOracleResult(tx, result); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create oracle result: %v", err)
+		}
+		if result.ID == 0 {
+			tx.Rollback()
+			t.Fatal("Oracle result ID was not set after creation")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	retrieved, err := GetOracleResult(database, results[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get oracle result: %v", err)
+	}
+	if retrieved == nil || retrieved.Result != results[0].Result || *retrieved.TableName != "tavern_rumor" {
+		t.Fatalf("Unexpected oracle result: %+v", retrieved)
+	}
+
+	missing, err := GetOracleResult(database, 999)
+	if err != nil || missing != nil {
+		t.Fatalf("Expected nil result for missing ID, got %+v, %v", missing, err)
+	}
+
+	all, err := ListOracleResults(database, OracleResultFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list oracle results: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != results[2].ID {
+		t.Fatalf("Expected 3 results newest first, got %+v", all)
+	}
+
+	tests := []struct {
+		name     string
+		filter   OracleResultFilter
+		expected int
+	}{
+		{"by session", OracleResultFilter{SessionID: &session.ID}, 2},
+		{"by table", OracleResultFilter{TableName: "tavern_rumor"}, 2},
+		{"by text", OracleResultFilter{Contains: "mayor"}, 1},
+		{"by query", OracleResultFilter{Contains: "goblins"}, 1},
+		{"combined", OracleResultFilter{SessionID: &session.ID, TableName: "tavern_rumor"}, 1},
+		{"limit", OracleResultFilter{Limit: 1}, 1},
+		{"since past", OracleResultFilter{Since: timePtr(time.Now().Add(-time.Hour))}, 3},
+		{"since future", OracleResultFilter{Since: timePtr(time.Now().Add(time.Hour))}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := ListOracleResults(database, tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to list oracle results: %v", err)
+			}
+			if len(found) != tt.expected {
+				t.Errorf("Expected %d results, got %d", tt.expected, len(found))
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
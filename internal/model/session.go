@@ -9,19 +9,25 @@ import (
 )
 
 type Session struct {
-	ID          int64 `db:"id"`
-	CurrentTurn int64 `db:"current_turn"`
+	ID           int64   `db:"id"`
+	CurrentTurn  int64   `db:"current_turn"`
+	CurrentRound int64   `db:"current_round"`
+	TimeMode     string  `db:"time_mode"`
+	Location     *string `db:"location"`
 }
 
 func (s *Session) Create(tx *sqlx.Tx) error {
-	query := "INSERT INTO sessions (current_turn) VALUES (?) RETURNING id"
-	row := db.RetryableQueryRow(tx, query, s.CurrentTurn)
+	if s.TimeMode == "" {
+		s.TimeMode = "exploration"
+	}
+	query := "INSERT INTO sessions (current_turn, current_round, time_mode, location) VALUES (?, ?, ?, ?) RETURNING id"
+	row := db.RetryableQueryRow(tx, query, s.CurrentTurn, s.CurrentRound, s.TimeMode, s.Location)
 	return row.Scan(&s.ID)
 }
 
 func GetSession(db *sqlx.DB, id int64) (*Session, error) {
 	var session Session
-	query := "SELECT id, current_turn FROM sessions WHERE id = ?"
+	query := "SELECT id, current_turn, current_round, time_mode, location FROM sessions WHERE id = ?"
 	err := db.Get(&session, query, id)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -32,6 +38,21 @@ func GetSession(db *sqlx.DB, id int64) (*Session, error) {
 	return &session, nil
 }
 
+// GetLatestSession returns the most recently created session, which the CLI
+// treats as the current one when no session is given.
+func GetLatestSession(db *sqlx.DB) (*Session, error) {
+	var session Session
+	query := "SELECT id, current_turn, current_round, time_mode, location FROM sessions ORDER BY id DESC LIMIT 1"
+	err := db.Get(&session, query)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest session: %w", err)
+	}
+	return &session, nil
+}
+
 func (s *Session) AdvanceTurn(tx *sqlx.Tx, delta int64) error {
 	query := "UPDATE sessions SET current_turn = current_turn + ? WHERE id = ?"
 	result, err := db.RetryableExec(tx, query, delta, s.ID)
@@ -51,3 +72,59 @@ func (s *Session) AdvanceTurn(tx *sqlx.Tx, delta int64) error {
 	s.CurrentTurn += delta
 	return nil
 }
+
+// AdvanceClock moves the session forward by turns and rounds, carrying
+// every roundsPerTurn rounds into a turn. It returns the new turn and round.
+func (s *Session) AdvanceClock(tx *sqlx.Tx, turns, rounds, roundsPerTurn int64) (int64, int64, error) {
+	query := `UPDATE sessions 
+			  SET current_turn = current_turn + ? + (current_round + ?) / ?, 
+			      current_round = (current_round + ?) % ? 
+			  WHERE id = ? RETURNING current_turn, current_round`
+	row := db.RetryableQueryRow(tx, query, turns, rounds, roundsPerTurn, rounds, roundsPerTurn, s.ID)
+	if err := row.Scan(&s.CurrentTurn, &s.CurrentRound); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, fmt.Errorf("session with id %d not found", s.ID)
+		}
+		return 0, 0, fmt.Errorf("failed to advance clock: %w", err)
+	}
+	return s.CurrentTurn, s.CurrentRound, nil
+}
+
+func (s *Session) SetTimeMode(tx *sqlx.Tx, mode string) error {
+	query := "UPDATE sessions SET time_mode = ? WHERE id = ?"
+	result, err := db.RetryableExec(tx, query, mode, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to set time mode: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("session with id %d not found", s.ID)
+	}
+
+	s.TimeMode = mode
+	return nil
+}
+
+// SetLocation records where the party is; nil means nowhere in particular.
+func (s *Session) SetLocation(tx *sqlx.Tx, location *string) error {
+	query := "UPDATE sessions SET location = ? WHERE id = ?"
+	result, err := db.RetryableExec(tx, query, location, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to set location: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("session with id %d not found", s.ID)
+	}
+
+	s.Location = location
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/script-wizards/spells/internal/db"
 )
 
@@ -101,3 +102,56 @@ func TestGetSessionNotFound(t *testing.T) {
 		t.Error("Expected nil session for non-existent ID")
 	}
 }
+
+func TestGetLatestSession(t *testing.T) {
+	database := newTestDB(t)
+
+	latest, err := GetLatestSession(database)
+	if err != nil {
+		t.Fatalf("Unexpected error with no sessions: %v", err)
+	}
+	if latest != nil {
+		t.Fatal("Expected nil session when none exist")
+	}
+
+	first := createTestSession(t, database)
+	second := createTestSession(t, database)
+
+	latest, err = GetLatestSession(database)
+	if err != nil {
+		t.Fatalf("Failed to get latest session: %v", err)
+	}
+	if latest == nil || latest.ID != second.ID || latest.ID == first.ID {
+		t.Fatalf("Expected latest session %d, got %+v", second.ID, latest)
+	}
+}
+
+func newTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func createTestSession(t *testing.T, database *sqlx.DB) *Session {
+	t.Helper()
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	session := &Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit session: %v", err)
+	}
+	return session
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/db"
+)
+
+const (
+	TimeEventWanderingCheck = "wandering_check"
+	TimeEventSpellEnd       = "spell_end"
+	TimeEventHoliday        = "holiday"
+	TimeEventReminder       = "reminder"
+)
+
+// TimeEvent is something scheduled to happen when a session reaches
+// TriggerTurn. A recurring event has RepeatEvery set and moves forward by
+// that many turns each time it triggers instead of being marked handled.
+type TimeEvent struct {
+	ID          int64     `db:"id"`
+	SessionID   int64     `db:"session_id"`
+	TriggerTurn int64     `db:"trigger_turn"`
+	EventType   string    `db:"event_type"`
+	Description *string   `db:"description"`
+	RepeatEvery *int64    `db:"repeat_every"`
+	Handled     bool      `db:"handled"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+const timeEventColumns = `id, session_id, trigger_turn, event_type, description, repeat_every, handled, created_at`
+
+func CreateTimeEvent(tx *sqlx.Tx, event *TimeEvent) error {
+	query := `INSERT INTO time_events (session_id, trigger_turn, event_type, description, repeat_every, handled) 
+			  VALUES (?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := db.RetryableQueryRow(tx, query, event.SessionID, event.TriggerTurn, event.EventType,
+		event.Description, event.RepeatEvery, event.Handled)
+	return row.Scan(&event.ID, &event.CreatedAt)
+}
+
+func GetTimeEvent(db *sqlx.DB, id int64) (*TimeEvent, error) {
+	var event TimeEvent
+	query := `SELECT ` + timeEventColumns + ` FROM time_events WHERE id = ?`
+	err := db.Get(&event, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time event: %w", err)
+	}
+	return &event, nil
+}
+
+// ListPendingTimeEvents returns a session's unhandled events, soonest first.
+func ListPendingTimeEvents(db *sqlx.DB, sessionID int64) ([]TimeEvent, error) {
+	var events []TimeEvent
+	query := `SELECT ` + timeEventColumns + ` FROM time_events 
+			  WHERE session_id = ? AND handled = 0 ORDER BY trigger_turn, id`
+	if err := db.Select(&events, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list pending time events: %w", err)
+	}
+	return events, nil
+}
+
+// ListDueTimeEvents returns a session's unhandled events that trigger on or
+// before turn, soonest first.
+func ListDueTimeEvents(tx *sqlx.Tx, sessionID, turn int64) ([]TimeEvent, error) {
+	var events []TimeEvent
+	query := `SELECT ` + timeEventColumns + ` FROM time_events 
+			  WHERE session_id = ? AND handled = 0 AND trigger_turn <= ? ORDER BY trigger_turn, id`
+	if err := tx.Select(&events, query, sessionID, turn); err != nil {
+		return nil, fmt.Errorf("failed to list due time events: %w", err)
+	}
+	return events, nil
+}
+
+func MarkTimeEventHandled(tx *sqlx.Tx, id int64) error {
+	query := "UPDATE time_events SET handled = 1 WHERE id = ?"
+	if _, err := db.RetryableExec(tx, query, id); err != nil {
+		return fmt.Errorf("failed to mark time event handled: %w", err)
+	}
+	return nil
+}
+
+// RescheduleTimeEvent moves an event to a new trigger turn.
+func RescheduleTimeEvent(tx *sqlx.Tx, id, triggerTurn int64) error {
+	query := "UPDATE time_events SET trigger_turn = ? WHERE id = ?"
+	if _, err := db.RetryableExec(tx, query, triggerTurn, id); err != nil {
+		return fmt.Errorf("failed to reschedule time event: %w", err)
+	}
+	return nil
+}
+
+func DeleteTimeEvent(tx *sqlx.Tx, id int64) error {
+	query := "DELETE FROM time_events WHERE id = ?"
+	if _, err := db.RetryableExec(tx, query, id); err != nil {
+		return fmt.Errorf("failed to delete time event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package model
+
+import "testing"
+
+func TestTimeEventCRUD(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+	every := int64(2)
+
+	events := []*TimeEvent{
+		{SessionID: session.ID, TriggerTurn: 5, EventType: TimeEventReminder, Description: stringPtr("Guards change")},
+		{SessionID: session.ID, TriggerTurn: 2, EventType: TimeEventWanderingCheck, RepeatEvery: &every},
+		{SessionID: session.ID, TriggerTurn: 9, EventType: TimeEventSpellEnd},
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	for _, event := range events {
+		if err := CreateTimeEvent(tx, event); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create time event: %v", err)
+		}
+	}
+
+	due, err := ListDueTimeEvents(tx, session.ID, 5)
+	if err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to list due events: %v", err)
+	}
+	if len(due) != 2 || due[0].ID != events[1].ID || due[1].ID != events[0].ID {
+		tx.Rollback()
+		t.Fatalf("Expected wandering check then reminder due by turn 5, got %+v", due)
+	}
+
+	if err := MarkTimeEventHandled(tx, events[0].ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to mark event handled: %v", err)
+	}
+	if err := RescheduleTimeEvent(tx, events[1].ID, 6); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to reschedule event: %v", err)
+	}
+	if err := DeleteTimeEvent(tx, events[2].ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to delete event: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	pending, err := ListPendingTimeEvents(database, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to list pending events: %v", err)
+	}
+	if len(pending) != 1 || pending[0].TriggerTurn != 6 || *pending[0].RepeatEvery != 2 {
+		t.Fatalf("Expected the rescheduled wandering check, got %+v", pending)
+	}
+
+	reminder, err := GetTimeEvent(database, events[0].ID)
+	if err != nil || reminder == nil || !reminder.Handled {
+		t.Fatalf("Expected handled reminder, got %+v, %v", reminder, err)
+	}
+
+	missing, err := GetTimeEvent(database, events[2].ID)
+	if err != nil || missing != nil {
+		t.Fatalf("Expected deleted event to be gone, got %+v, %v", missing, err)
+	}
+}
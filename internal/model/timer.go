@@ -0,0 +1,116 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/db"
+)
+
+const (
+	TimerRunning   = "running"
+	TimerCompleted = "completed"
+	TimerCancelled = "cancelled"
+)
+
+// Timer is a real-world countdown, such as a break or a food order. A
+// running timer whose duration has passed is expired until it is completed
+// or cancelled.
+type Timer struct {
+	ID              int64      `db:"id"`
+	Label           string     `db:"label"`
+	DurationSeconds int64      `db:"duration_seconds"`
+	Status          string     `db:"status"`
+	StartedAt       time.Time  `db:"started_at"`
+	CompletedAt     *time.Time `db:"completed_at"`
+	SessionID       *int64     `db:"session_id"`
+}
+
+const timerColumns = `id, label, duration_seconds, status, started_at, completed_at, session_id`
+
+// Duration returns how long the timer runs for.
+func (t Timer) Duration() time.Duration {
+	return time.Duration(t.DurationSeconds) * time.Second
+}
+
+// ExpiresAt returns when the timer runs out.
+func (t Timer) ExpiresAt() time.Time {
+	return t.StartedAt.Add(t.Duration())
+}
+
+// Remaining returns the time left at now, or zero once it has run out.
+func (t Timer) Remaining(now time.Time) time.Duration {
+	return max(t.ExpiresAt().Sub(now), 0)
+}
+
+// Expired reports whether a running timer has run out at now.
+func (t Timer) Expired(now time.Time) bool {
+	return t.Status == TimerRunning && !now.Before(t.ExpiresAt())
+}
+
+func CreateTimer(tx *sqlx.Tx, timer *Timer) error {
+	query := `INSERT INTO timers (label, duration_seconds, session_id) 
+			  VALUES (?, ?, ?) RETURNING id, status, started_at`
+	row := db.RetryableQueryRow(tx, query, timer.Label, timer.DurationSeconds, timer.SessionID)
+	return row.Scan(&timer.ID, &timer.Status, &timer.StartedAt)
+}
+
+func GetTimer(db *sqlx.DB, id int64) (*Timer, error) {
+	var timer Timer
+	query := `SELECT ` + timerColumns + ` FROM timers WHERE id = ?`
+	err := db.Get(&timer, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timer: %w", err)
+	}
+	return &timer, nil
+}
+
+// ListRunningTimers returns the running timers, soonest to expire first.
+func ListRunningTimers(db *sqlx.DB) ([]Timer, error) {
+	var timers []Timer
+	query := `SELECT ` + timerColumns + ` FROM timers WHERE status = ? 
+			  ORDER BY unixepoch(started_at) + duration_seconds, id`
+	if err := db.Select(&timers, query, TimerRunning); err != nil {
+		return nil, fmt.Errorf("failed to list running timers: %w", err)
+	}
+	return timers, nil
+}
+
+// ListTimers returns every timer, newest first.
+func ListTimers(db *sqlx.DB, limit int) ([]Timer, error) {
+	query := `SELECT ` + timerColumns + ` FROM timers ORDER BY started_at DESC, id DESC`
+	var args []interface{}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	var timers []Timer
+	if err := db.Select(&timers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list timers: %w", err)
+	}
+	return timers, nil
+}
+
+// FinishTimer completes or cancels a running timer.
+func FinishTimer(tx *sqlx.Tx, id int64, status string) error {
+	if status != TimerCompleted && status != TimerCancelled {
+		return fmt.Errorf("invalid timer status %q", status)
+	}
+
+	query := `UPDATE timers SET status = ?, completed_at = CURRENT_TIMESTAMP 
+			  WHERE id = ? AND status = ?`
+	result, err := db.RetryableExec(tx, query, status, id, TimerRunning)
+	if err != nil {
+		return fmt.Errorf("failed to finish timer: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("timer %d is not running", id)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerCRUD(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	timers := []*Timer{
+		{Label: "pizza", DurationSeconds: 600, SessionID: &session.ID},
+		{Label: "break", DurationSeconds: 300},
+		{Label: "tea", DurationSeconds: 900},
+	}
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	for _, timer := range timers {
+		if err := CreateTimer(tx, timer); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create timer: %v", err)
+		}
+	}
+	if err := FinishTimer(tx, timers[2].ID, TimerCancelled); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to cancel timer: %v", err)
+	}
+	if err := FinishTimer(tx, timers[2].ID, TimerCompleted); err == nil {
+		tx.Rollback()
+		t.Fatal("Expected an error finishing a cancelled timer")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	running, err := ListRunningTimers(database)
+	if err != nil {
+		t.Fatalf("Failed to list running timers: %v", err)
+	}
+	if len(running) != 2 || running[0].Label != "break" || running[1].Label != "pizza" {
+		t.Fatalf("Expected break then pizza running, got %+v", running)
+	}
+
+	pizza := running[1]
+	if pizza.Status != TimerRunning || pizza.SessionID == nil || *pizza.SessionID != session.ID {
+		t.Errorf("Unexpected timer: %+v", pizza)
+	}
+	if pizza.Expired(pizza.StartedAt.Add(9*time.Minute)) || !pizza.Expired(pizza.StartedAt.Add(10*time.Minute)) {
+		t.Errorf("Expected pizza to expire after exactly 10 minutes")
+	}
+	if got := pizza.Remaining(pizza.StartedAt.Add(4 * time.Minute)); got != 6*time.Minute {
+		t.Errorf("Expected 6 minutes left, got %v", got)
+	}
+
+	tea, err := GetTimer(database, timers[2].ID)
+	if err != nil {
+		t.Fatalf("Failed to get timer: %v", err)
+	}
+	if tea.Status != TimerCancelled || tea.CompletedAt == nil || tea.Expired(tea.ExpiresAt()) {
+		t.Errorf("Expected a cancelled timer that never expires, got %+v", tea)
+	}
+
+	all, err := ListTimers(database, 0)
+	if err != nil {
+		t.Fatalf("Failed to list timers: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != timers[2].ID {
+		t.Errorf("Expected all timers newest first, got %+v", all)
+	}
+
+	missing, err := GetTimer(database, 999)
+	if err != nil || missing != nil {
+		t.Errorf("Expected no timer for a missing ID, got %+v, %v", missing, err)
+	}
+}
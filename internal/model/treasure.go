@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Treasure is something of value the party found. Its gold value counts
+// towards XP once XPAwarded is set.
+type Treasure struct {
+	ID          int64     `db:"id"`
+	SessionID   int64     `db:"session_id"`
+	Description string    `db:"item_description"`
+	GoldValue   int       `db:"gold_value"`
+	Location    *string   `db:"location"`
+	DateFound   time.Time `db:"date_found"`
+	XPAwarded   bool      `db:"xp_awarded"`
+}
+
+const treasureColumns = `id, session_id, item_description, gold_value, location, date_found, xp_awarded`
+
+func CreateTreasure(tx *sqlx.Tx, treasure *Treasure) error {
+	query := `INSERT INTO treasure_found (session_id, item_description, gold_value, location)
+			  VALUES (?, ?, ?, ?) RETURNING id, date_found, xp_awarded`
+	row := tx.QueryRow(query, treasure.SessionID, treasure.Description, treasure.GoldValue, treasure.Location)
+	if err := row.Scan(&treasure.ID, &treasure.DateFound, &treasure.XPAwarded); err != nil {
+		return fmt.Errorf("failed to record treasure: %w", err)
+	}
+	return nil
+}
+
+// ListTreasure returns the treasure found in a session, in the order it
+// was found.
+func ListTreasure(db *sqlx.DB, sessionID int64) ([]Treasure, error) {
+	var treasure []Treasure
+	query := `SELECT ` + treasureColumns + ` FROM treasure_found WHERE session_id = ? ORDER BY id`
+	if err := db.Select(&treasure, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list treasure: %w", err)
+	}
+	return treasure, nil
+}
+
+// ListUnawardedTreasure returns a session's treasure whose XP has not been
+// awarded yet.
+func ListUnawardedTreasure(db *sqlx.DB, sessionID int64) ([]Treasure, error) {
+	var treasure []Treasure
+	query := `SELECT ` + treasureColumns + ` FROM treasure_found WHERE session_id = ? AND xp_awarded = 0 ORDER BY id`
+	if err := db.Select(&treasure, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list unawarded treasure: %w", err)
+	}
+	return treasure, nil
+}
+
+// SetTreasureAwarded marks treasure as having been turned into XP.
+func SetTreasureAwarded(tx *sqlx.Tx, id int64) error {
+	query := "UPDATE treasure_found SET xp_awarded = 1 WHERE id = ?"
+	if _, err := tx.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to mark treasure awarded: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import "testing"
+
+func TestTreasure(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	chalice := &Treasure{SessionID: session.ID, Description: "Gold chalice", GoldValue: 50, Location: stringPtr("Room 12")}
+	coins := &Treasure{SessionID: session.ID, Description: "Copper coins", GoldValue: 3}
+	for _, treasure := range []*Treasure{chalice, coins} {
+		if err := CreateTreasure(tx, treasure); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to record treasure: %v", err)
+		}
+	}
+	if chalice.XPAwarded {
+		t.Error("Expected new treasure not to be awarded")
+	}
+	if err := SetTreasureAwarded(tx, chalice.ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to mark treasure awarded: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	treasure, err := ListTreasure(database, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to list treasure: %v", err)
+	}
+	if len(treasure) != 2 || treasure[0].Description != "Gold chalice" || !treasure[0].XPAwarded ||
+		treasure[0].Location == nil || *treasure[0].Location != "Room 12" {
+		t.Errorf("Unexpected treasure: %+v", treasure)
+	}
+
+	unawarded, err := ListUnawardedTreasure(database, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to list unawarded treasure: %v", err)
+	}
+	if len(unawarded) != 1 || unawarded[0].ID != coins.ID {
+		t.Errorf("Expected only the coins unawarded, got %+v", unawarded)
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/script-wizards/spells/internal/db"
+)
+
+// WanderingCheck rolls for wandering monsters every FrequencyTurns turns:
+// a roll of Chance or less on a DDie finds an encounter from TableName.
+// Location is nil for a check that covers the whole session.
+type WanderingCheck struct {
+	ID              int64     `db:"id"`
+	SessionID       int64     `db:"session_id"`
+	Location        *string   `db:"location"`
+	FrequencyTurns  int64     `db:"frequency_turns"`
+	Chance          int       `db:"chance"`
+	Die             int       `db:"die"`
+	TableName       string    `db:"table_name"`
+	CreateEncounter bool      `db:"create_encounter"`
+	TimeEventID     *int64    `db:"time_event_id"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// RollsAt reports whether the check rolls while the party is at location:
+// a check for the whole session always does, and one for a location only
+// when the party is there.
+func (c WanderingCheck) RollsAt(location *string) bool {
+	if c.Location == nil {
+		return true
+	}
+	return location != nil && strings.EqualFold(*c.Location, *location)
+}
+
+const wanderingCheckColumns = `id, session_id, location, frequency_turns, chance, die, table_name, 
+			  create_encounter, time_event_id, created_at`
+
+func CreateWanderingCheck(tx *sqlx.Tx, check *WanderingCheck) error {
+	query := `INSERT INTO wandering_checks (session_id, location, frequency_turns, chance, die, table_name, 
+			  create_encounter, time_event_id) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := db.RetryableQueryRow(tx, query, check.SessionID, check.Location, check.FrequencyTurns, check.Chance,
+		check.Die, check.TableName, check.CreateEncounter, check.TimeEventID)
+	return row.Scan(&check.ID, &check.CreatedAt)
+}
+
+func GetWanderingCheck(db *sqlx.DB, id int64) (*WanderingCheck, error) {
+	var check WanderingCheck
+	query := `SELECT ` + wanderingCheckColumns + ` FROM wandering_checks WHERE id = ?`
+	err := db.Get(&check, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wandering check: %w", err)
+	}
+	return &check, nil
+}
+
+// GetWanderingCheckByTimeEvent returns the check driven by a time event.
+func GetWanderingCheckByTimeEvent(db *sqlx.DB, timeEventID int64) (*WanderingCheck, error) {
+	var check WanderingCheck
+	query := `SELECT ` + wanderingCheckColumns + ` FROM wandering_checks WHERE time_event_id = ?`
+	err := db.Get(&check, query, timeEventID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wandering check: %w", err)
+	}
+	return &check, nil
+}
+
+func ListWanderingChecks(db *sqlx.DB, sessionID int64) ([]WanderingCheck, error) {
+	var checks []WanderingCheck
+	query := `SELECT ` + wanderingCheckColumns + ` FROM wandering_checks WHERE session_id = ? ORDER BY id`
+	if err := db.Select(&checks, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list wandering checks: %w", err)
+	}
+	return checks, nil
+}
+
+func DeleteWanderingCheck(tx *sqlx.Tx, id int64) error {
+	query := "DELETE FROM wandering_checks WHERE id = ?"
+	if _, err := db.RetryableExec(tx, query, id); err != nil {
+		return fmt.Errorf("failed to delete wandering check: %w", err)
+	}
+	return nil
+}
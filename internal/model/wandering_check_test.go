@@ -0,0 +1,58 @@
+package model
+
+import "testing"
+
+func TestWanderingCheckCRUD(t *testing.T) {
+	database := newTestDB(t)
+	session := createTestSession(t, database)
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	event := &TimeEvent{SessionID: session.ID, TriggerTurn: 2, EventType: TimeEventWanderingCheck}
+	if err := CreateTimeEvent(tx, event); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to create time event: %v", err)
+	}
+	checks := []*WanderingCheck{
+		{SessionID: session.ID, FrequencyTurns: 2, Chance: 1, Die: 6, TableName: "dungeon", TimeEventID: &event.ID},
+		{SessionID: session.ID, Location: stringPtr("Forest"), FrequencyTurns: 24, Chance: 2, Die: 6, TableName: "forest", CreateEncounter: true},
+	}
+	for _, check := range checks {
+		if err := CreateWanderingCheck(tx, check); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to create wandering check: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	byEvent, err := GetWanderingCheckByTimeEvent(database, event.ID)
+	if err != nil || byEvent == nil || byEvent.ID != checks[0].ID {
+		t.Fatalf("Expected check for time event, got %+v, %v", byEvent, err)
+	}
+
+	forest, err := GetWanderingCheck(database, checks[1].ID)
+	if err != nil || forest == nil || *forest.Location != "Forest" || !forest.CreateEncounter {
+		t.Fatalf("Unexpected forest check: %+v, %v", forest, err)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if err := DeleteWanderingCheck(tx, checks[0].ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("Failed to delete wandering check: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	remaining, err := ListWanderingChecks(database, session.ID)
+	if err != nil || len(remaining) != 1 || remaining[0].ID != checks[1].ID {
+		t.Fatalf("Expected only the forest check, got %+v, %v", remaining, err)
+	}
+}
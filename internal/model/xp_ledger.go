@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	XPFromMonster  = "monster"
+	XPFromTreasure = "treasure"
+)
+
+// XPEntry is a line in the XP ledger: XP the party earned as a whole, from
+// a defeated combatant or from treasure.
+type XPEntry struct {
+	ID          int64     `db:"id"`
+	SessionID   int64     `db:"session_id"`
+	Source      string    `db:"source"`
+	Description string    `db:"description"`
+	XP          int       `db:"xp"`
+	CombatantID *int64    `db:"combatant_id"`
+	TreasureID  *int64    `db:"treasure_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+const xpEntryColumns = `id, session_id, source, description, xp, combatant_id, treasure_id, created_at`
+
+func CreateXPEntry(tx *sqlx.Tx, entry *XPEntry) error {
+	query := `INSERT INTO xp_ledger (session_id, source, description, xp, combatant_id, treasure_id)
+			  VALUES (?, ?, ?, ?, ?, ?) RETURNING id, created_at`
+	row := tx.QueryRow(query, entry.SessionID, entry.Source, entry.Description, entry.XP, entry.CombatantID, entry.TreasureID)
+	if err := row.Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return fmt.Errorf("failed to credit xp: %w", err)
+	}
+	return nil
+}
+
+// ListXPEntries returns a session's XP ledger, oldest first.
+func ListXPEntries(db *sqlx.DB, sessionID int64) ([]XPEntry, error) {
+	var entries []XPEntry
+	query := `SELECT ` + xpEntryColumns + ` FROM xp_ledger WHERE session_id = ? ORDER BY id`
+	if err := db.Select(&entries, query, sessionID); err != nil {
+		return nil, fmt.Errorf("failed to list xp ledger: %w", err)
+	}
+	return entries, nil
+}
+
+// CombatantXPCredited reports whether XP has already been credited for
+// defeating a combatant.
+func CombatantXPCredited(db *sqlx.DB, combatantID int64) (bool, error) {
+	var credited bool
+	query := `SELECT EXISTS (SELECT 1 FROM xp_ledger WHERE combatant_id = ?)`
+	if err := db.Get(&credited, query, combatantID); err != nil {
+		return false, fmt.Errorf("failed to check xp ledger: %w", err)
+	}
+	return credited, nil
+}
+
+// SessionXP returns the XP earned in a session.
+func SessionXP(db *sqlx.DB, sessionID int64) (int, error) {
+	var xp int
+	if err := db.Get(&xp, `SELECT COALESCE(SUM(xp), 0) FROM xp_ledger WHERE session_id = ?`, sessionID); err != nil {
+		return 0, fmt.Errorf("failed to total session xp: %w", err)
+	}
+	return xp, nil
+}
+
+// CampaignXP returns the XP earned across every session.
+func CampaignXP(db *sqlx.DB) (int, error) {
+	var xp int
+	if err := db.Get(&xp, `SELECT COALESCE(SUM(xp), 0) FROM xp_ledger`); err != nil {
+		return 0, fmt.Errorf("failed to total campaign xp: %w", err)
+	}
+	return xp, nil
+}
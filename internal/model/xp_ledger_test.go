@@ -0,0 +1,51 @@
+package model
+
+import "testing"
+
+func TestXPLedger(t *testing.T) {
+	database := newTestDB(t)
+	first := createTestSession(t, database)
+	second := createTestSession(t, database)
+
+	combatantID := int64(7)
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	entries := []*XPEntry{
+		{SessionID: first.ID, Source: XPFromMonster, Description: "Goblin 1", XP: 5, CombatantID: &combatantID},
+		{SessionID: first.ID, Source: XPFromTreasure, Description: "Gold chalice", XP: 50},
+		{SessionID: second.ID, Source: XPFromMonster, Description: "Orc", XP: 10},
+	}
+	for _, entry := range entries {
+		if err := CreateXPEntry(tx, entry); err != nil {
+			tx.Rollback()
+			t.Fatalf("Failed to credit xp: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	ledger, err := ListXPEntries(database, first.ID)
+	if err != nil {
+		t.Fatalf("Failed to list xp ledger: %v", err)
+	}
+	if len(ledger) != 2 || ledger[0].Description != "Goblin 1" || ledger[1].Source != XPFromTreasure {
+		t.Errorf("Unexpected ledger: %+v", ledger)
+	}
+
+	if xp, err := SessionXP(database, first.ID); err != nil || xp != 55 {
+		t.Errorf("Expected 55 session XP, got %d, %v", xp, err)
+	}
+	if xp, err := CampaignXP(database); err != nil || xp != 65 {
+		t.Errorf("Expected 65 campaign XP, got %d, %v", xp, err)
+	}
+
+	if credited, err := CombatantXPCredited(database, combatantID); err != nil || !credited {
+		t.Errorf("Expected combatant %d credited, got %v, %v", combatantID, credited, err)
+	}
+	if credited, err := CombatantXPCredited(database, combatantID+1); err != nil || credited {
+		t.Errorf("Expected combatant %d not credited, got %v, %v", combatantID+1, credited, err)
+	}
+}
@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// TableExt is the file extension for single-template table files.
+	TableExt = ".table"
+	// ListExt is the file extension for Perchance-style list files.
+	ListExt = ".perchance"
+)
+
+// LoadTables reads every table file found under the given directories. A
+// .table file holds one template and is named after the file, minus the
+// extension. A .perchance file holds any number of lists in the format read
+// by ParseLists. Directories are loaded in order, so a table in a later
+// directory overrides one of the same name from an earlier directory.
+// Missing directories are skipped.
+func LoadTables(dirs ...string) (map[string]*List, error) {
+	tables := make(map[string]*List)
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if path != dir && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			ext := filepath.Ext(path)
+			if ext != TableExt && ext != ListExt {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read table %s: %w", path, err)
+			}
+
+			if ext == TableExt {
+				name := strings.TrimSuffix(info.Name(), TableExt)
+				tables[name] = TemplateList(name, strings.TrimSpace(string(content)))
+				return nil
+			}
+
+			lists, err := ParseLists(content)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			for name, list := range lists {
+				tables[name] = list
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tables from %s: %w", dir, err)
+		}
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,90 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTable(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+TableExt), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write table: %v", err)
+	}
+}
+
+func TestLoadTables(t *testing.T) {
+	globalDir := t.TempDir()
+	campaignDir := t.TempDir()
+
+	writeTable(t, globalDir, "encounter", "{goblins|orcs}\n")
+	writeTable(t, globalDir, "weather", "rain")
+	writeTable(t, campaignDir, "encounter", "1d4 [creature]")
+	writeTable(t, filepath.Join(campaignDir, "tables"), "creature", "rat")
+	writeTable(t, filepath.Join(campaignDir, ".hidden"), "secret", "nope")
+
+	if err := os.WriteFile(filepath.Join(campaignDir, "notes.md"), []byte("not a table"), 0644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+
+	tables, err := LoadTables(globalDir, campaignDir)
+	if err != nil {
+		t.Fatalf("LoadTables error: %v", err)
+	}
+
+	expected := map[string]string{
+		"encounter": "1d4 [creature]",
+		"weather":   "rain",
+		"creature":  "rat",
+	}
+
+	if len(tables) != len(expected) {
+		t.Fatalf("Expected %d tables, got %d: %v", len(expected), len(tables), tables)
+	}
+	for name, value := range expected {
+		list, exists := tables[name]
+		if !exists {
+			t.Errorf("Expected table %q to be loaded", name)
+			continue
+		}
+		if len(list.Items) != 1 || list.Items[0].Text != value {
+			t.Errorf("Expected table %q to be %q, got %+v", name, value, list.Items)
+		}
+	}
+}
+
+func TestLoadTablesMissingDir(t *testing.T) {
+	tables, err := LoadTables(filepath.Join(t.TempDir(), "missing"), "")
+	if err != nil {
+		t.Fatalf("LoadTables error: %v", err)
+	}
+
+	if len(tables) != 0 {
+		t.Fatalf("Expected no tables, got %v", tables)
+	}
+}
+
+func TestLoadTablesPerchance(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTable(t, dir, "creature", "rat")
+	content := "creature\n  goblin\n  orc\n\nweather\n  rain ^2\n  sun\n"
+	if err := os.WriteFile(filepath.Join(dir, "z_lists"+ListExt), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write list file: %v", err)
+	}
+
+	tables, err := LoadTables(dir)
+	if err != nil {
+		t.Fatalf("LoadTables error: %v", err)
+	}
+
+	if len(tables["creature"].Items) != 2 {
+		t.Errorf("Expected creature list to have 2 items, got %+v", tables["creature"].Items)
+	}
+	if tables["weather"] == nil || tables["weather"].Items[0].Weight != 2 {
+		t.Errorf("Expected weather list with weighted rain, got %+v", tables["weather"])
+	}
+}
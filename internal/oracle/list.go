@@ -0,0 +1,213 @@
+package oracle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// List is a named random table in the Perchance list format. Each item is a
+// template that is resolved after it is picked. An item with nested items is
+// a sub-list: picking it picks again among its own items.
+type List struct {
+	Name  string     `json:"name"`
+	Items []ListItem `json:"items"`
+}
+
+type ListItem struct {
+	Text   string     `json:"text"`
+	Weight float64    `json:"weight"`
+	Items  []ListItem `json:"items,omitempty"`
+}
+
+var weightSuffix = regexp.MustCompile(`\s*\^(\d+(?:\.\d+)?)$`)
+
+// TemplateList wraps a single template string as a one-item list.
+func TemplateList(name, template string) *List {
+	return &List{
+		Name:  name,
+		Items: []ListItem{{Text: template, Weight: 1}},
+	}
+}
+
+// Templates converts a map of template strings into single-item lists.
+func Templates(tables map[string]string) map[string]*List {
+	lists := make(map[string]*List, len(tables))
+	for name, template := range tables {
+		lists[name] = TemplateList(name, template)
+	}
+	return lists
+}
+
+type listLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// ParseLists parses Perchance-style list definitions:
+//
+//	creature
+//	  goblin ^3
+//	  orc
+//	  beast
+//	    wolf
+//	    bear ^0.5
+//
+// Unindented lines name a list and the indented lines below them are its
+// items. A trailing ^weight sets an item's relative chance (default 1).
+// Deeper indentation nests a sub-list under the item above it. Blank lines
+// and lines starting with // are ignored.
+func ParseLists(src []byte) (map[string]*List, error) {
+	var lines []listLine
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	num := 0
+	for scanner.Scan() {
+		num++
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		text := strings.TrimLeft(raw, " \t")
+		if text == "" || strings.HasPrefix(text, "//") {
+			continue
+		}
+		indent := 0
+		for _, ch := range raw[:len(raw)-len(text)] {
+			if ch == '\t' {
+				indent += 2
+			} else {
+				indent++
+			}
+		}
+		lines = append(lines, listLine{num: num, indent: indent, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	lists := make(map[string]*List)
+	for i := 0; i < len(lines); {
+		header := lines[i]
+		if header.indent != 0 {
+			return nil, fmt.Errorf("line %d: unexpected indentation", header.num)
+		}
+		i++
+
+		if i >= len(lines) || lines[i].indent == 0 {
+			return nil, fmt.Errorf("line %d: list %q has no items", header.num, header.text)
+		}
+
+		items, next, err := parseListItems(lines, i, lines[i].indent)
+		if err != nil {
+			return nil, err
+		}
+		i = next
+
+		lists[header.text] = &List{Name: header.text, Items: items}
+	}
+
+	return lists, nil
+}
+
+func parseListItems(lines []listLine, i, indent int) ([]ListItem, int, error) {
+	var items []ListItem
+
+	for i < len(lines) && lines[i].indent >= indent {
+		line := lines[i]
+		if line.indent > indent {
+			return nil, i, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+
+		item, err := parseListItem(line)
+		if err != nil {
+			return nil, i, err
+		}
+		i++
+
+		if i < len(lines) && lines[i].indent > indent {
+			var children []ListItem
+			children, i, err = parseListItems(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, i, err
+			}
+			item.Items = children
+		}
+
+		items = append(items, item)
+	}
+
+	return items, i, nil
+}
+
+func parseListItem(line listLine) (ListItem, error) {
+	item := ListItem{Text: line.text, Weight: 1}
+
+	if match := weightSuffix.FindStringSubmatch(line.text); match != nil {
+		weight, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return item, fmt.Errorf("line %d: invalid weight %q", line.num, match[1])
+		}
+		item.Weight = weight
+		item.Text = strings.TrimSuffix(line.text, match[0])
+	}
+
+	return item, nil
+}
+
+// Pick chooses an item from the list by weight, descending into sub-lists
+// until it reaches an item without nested items.
+func (l *List) Pick(rng *rand.Rand) (ListItem, error) {
+	item, err := pickWeighted(l.Items, rng)
+	if err != nil {
+		return ListItem{}, fmt.Errorf("list %q: %w", l.Name, err)
+	}
+	for len(item.Items) > 0 {
+		item, err = pickWeighted(item.Items, rng)
+		if err != nil {
+			return ListItem{}, fmt.Errorf("list %q: %w", l.Name, err)
+		}
+	}
+	return item, nil
+}
+
+// Sub returns the sub-list reached by following a dotted path of item
+// names, e.g. "beast" in creature.beast.
+func (l *List) Sub(path []string) (*List, bool) {
+	current := l
+	for _, name := range path {
+		var found *List
+		for _, item := range current.Items {
+			if item.Text == name && len(item.Items) > 0 {
+				found = &List{Name: current.Name + "." + name, Items: item.Items}
+				break
+			}
+		}
+		if found == nil {
+			return nil, false
+		}
+		current = found
+	}
+	return current, true
+}
+
+func pickWeighted(items []ListItem, rng *rand.Rand) (ListItem, error) {
+	var total float64
+	for _, item := range items {
+		total += item.Weight
+	}
+	if total <= 0 {
+		return ListItem{}, fmt.Errorf("no items to pick from")
+	}
+
+	target := rng.Float64() * total
+	for _, item := range items {
+		if target < item.Weight {
+			return item, nil
+		}
+		target -= item.Weight
+	}
+	return items[len(items)-1], nil
+}
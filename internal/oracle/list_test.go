@@ -0,0 +1,148 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLists(t *testing.T) {
+	src := `// encounter tables
+creature
+  goblin ^3
+  orc
+  beast
+    wolf
+    bear ^0.5
+
+weather
+	rain
+	fog ^2
+`
+	lists, err := ParseLists([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseLists error: %v", err)
+	}
+
+	if len(lists) != 2 {
+		t.Fatalf("Expected 2 lists, got %d", len(lists))
+	}
+
+	creature := lists["creature"]
+	if creature == nil || len(creature.Items) != 3 {
+		t.Fatalf("Expected creature list with 3 items, got %+v", creature)
+	}
+	if creature.Items[0].Text != "goblin" || creature.Items[0].Weight != 3 {
+		t.Errorf("Expected goblin ^3, got %+v", creature.Items[0])
+	}
+	if creature.Items[1].Weight != 1 {
+		t.Errorf("Expected default weight 1, got %v", creature.Items[1].Weight)
+	}
+
+	beast := creature.Items[2]
+	if beast.Text != "beast" || len(beast.Items) != 2 {
+		t.Fatalf("Expected beast sub-list with 2 items, got %+v", beast)
+	}
+	if beast.Items[1].Text != "bear" || beast.Items[1].Weight != 0.5 {
+		t.Errorf("Expected bear ^0.5, got %+v", beast.Items[1])
+	}
+
+	if weather := lists["weather"]; weather == nil || len(weather.Items) != 2 {
+		t.Errorf("Expected tab-indented weather list with 2 items, got %+v", weather)
+	}
+}
+
+func TestParseListsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no items", "creature\nweather\n  rain\n", "has no items"},
+		{"leading indentation", "  goblin\n", "unexpected indentation"},
+		{"bad dedent", "creature\n  goblin\n      wolf\n    bear\n", "unexpected indentation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLists([]byte(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestListPickWeighted(t *testing.T) {
+	list := &List{
+		Name: "loot",
+		Items: []ListItem{
+			{Text: "copper", Weight: 9},
+			{Text: "gold", Weight: 1},
+			{Text: "nothing", Weight: 0},
+		},
+	}
+
+	counts := make(map[string]int)
+	rng := newTestRng(7)
+	for i := 0; i < 1000; i++ {
+		item, err := list.Pick(rng)
+		if err != nil {
+			t.Fatalf("Pick error: %v", err)
+		}
+		counts[item.Text]++
+	}
+
+	if counts["nothing"] != 0 {
+		t.Errorf("Expected zero-weight item never to be picked, got %d", counts["nothing"])
+	}
+	if counts["copper"] < 800 || counts["gold"] < 50 {
+		t.Errorf("Expected roughly 9:1 split, got %v", counts)
+	}
+}
+
+func TestListPickReproducible(t *testing.T) {
+	lists, err := ParseLists([]byte("creature\n  goblin\n  orc\n  beast\n    wolf\n    bear\n"))
+	if err != nil {
+		t.Fatalf("ParseLists error: %v", err)
+	}
+
+	roll := func() []string {
+		rng := newTestRng(42)
+		var picks []string
+		for i := 0; i < 20; i++ {
+			item, err := lists["creature"].Pick(rng)
+			if err != nil {
+				t.Fatalf("Pick error: %v", err)
+			}
+			picks = append(picks, item.Text)
+		}
+		return picks
+	}
+
+	first, second := roll(), roll()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected seeded picks to match, got %v and %v", first, second)
+		}
+		if first[i] == "beast" {
+			t.Fatalf("Expected sub-list to be descended into, got %q", first[i])
+		}
+	}
+}
+
+func TestResolveListSubTable(t *testing.T) {
+	lists, err := ParseLists([]byte("creature\n  goblin\n  beast\n    wolf\n"))
+	if err != nil {
+		t.Fatalf("ParseLists error: %v", err)
+	}
+
+	resolver := NewResolver(lists, newTestRng(1))
+	result, err := resolver.Resolve("a [creature.beast]")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+
+	if result != "a wolf" {
+		t.Fatalf("Expected 'a wolf', got %s", result)
+	}
+}
@@ -276,72 +276,191 @@ var g = &grammar{
 				expr: &seqExpr{
 					pos: position{line: 44, col: 7, offset: 907},
 					exprs: []any{
-						&labeledExpr{
-							pos:   position{line: 44, col: 7, offset: 907},
-							label: "count",
-							expr: &ruleRefExpr{
-								pos:  position{line: 44, col: 13, offset: 913},
+						&ruleRefExpr{
+							pos:  position{line: 44, col: 7, offset: 907},
+							name: "DiceTerm",
+						},
+						&zeroOrMoreExpr{
+							pos: position{line: 44, col: 16, offset: 916},
+							expr: &seqExpr{
+								pos: position{line: 44, col: 18, offset: 918},
+								exprs: []any{
+									&charClassMatcher{
+										pos:        position{line: 44, col: 18, offset: 918},
+										val:        "[+-]",
+										chars:      []rune{'+', '-'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+									&choiceExpr{
+										pos: position{line: 44, col: 25, offset: 925},
+										alternatives: []any{
+											&ruleRefExpr{
+												pos:  position{line: 44, col: 25, offset: 925},
+												name: "DiceTerm",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 44, col: 36, offset: 936},
+												name: "Integer",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "DiceTerm",
+			pos:  position{line: 48, col: 1, offset: 1002},
+			expr: &seqExpr{
+				pos: position{line: 49, col: 7, offset: 1017},
+				exprs: []any{
+					&zeroOrOneExpr{
+						pos: position{line: 49, col: 7, offset: 1017},
+						expr: &ruleRefExpr{
+							pos:  position{line: 49, col: 7, offset: 1017},
+							name: "Integer",
+						},
+					},
+					&litMatcher{
+						pos:        position{line: 49, col: 16, offset: 1026},
+						val:        "d",
+						ignoreCase: false,
+						want:       "\"d\"",
+					},
+					&choiceExpr{
+						pos: position{line: 49, col: 22, offset: 1032},
+						alternatives: []any{
+							&ruleRefExpr{
+								pos:  position{line: 49, col: 22, offset: 1032},
 								name: "Integer",
 							},
+							&litMatcher{
+								pos:        position{line: 49, col: 32, offset: 1042},
+								val:        "%",
+								ignoreCase: false,
+								want:       "\"%\"",
+							},
 						},
-						&litMatcher{
-							pos:        position{line: 44, col: 21, offset: 921},
-							val:        "d",
-							ignoreCase: false,
-							want:       "\"d\"",
+					},
+					&zeroOrMoreExpr{
+						pos: position{line: 49, col: 38, offset: 1048},
+						expr: &ruleRefExpr{
+							pos:  position{line: 49, col: 38, offset: 1048},
+							name: "DiceModifier",
 						},
-						&labeledExpr{
-							pos:   position{line: 44, col: 25, offset: 925},
-							label: "sides",
-							expr: &ruleRefExpr{
-								pos:  position{line: 44, col: 31, offset: 931},
+					},
+				},
+			},
+		},
+		{
+			name: "DiceModifier",
+			pos:  position{line: 51, col: 1, offset: 1063},
+			expr: &choiceExpr{
+				pos: position{line: 52, col: 7, offset: 1082},
+				alternatives: []any{
+					&seqExpr{
+						pos: position{line: 52, col: 7, offset: 1082},
+						exprs: []any{
+							&choiceExpr{
+								pos: position{line: 52, col: 9, offset: 1084},
+								alternatives: []any{
+									&litMatcher{
+										pos:        position{line: 52, col: 9, offset: 1084},
+										val:        "kh",
+										ignoreCase: false,
+										want:       "\"kh\"",
+									},
+									&litMatcher{
+										pos:        position{line: 52, col: 16, offset: 1091},
+										val:        "kl",
+										ignoreCase: false,
+										want:       "\"kl\"",
+									},
+									&litMatcher{
+										pos:        position{line: 52, col: 23, offset: 1098},
+										val:        "dh",
+										ignoreCase: false,
+										want:       "\"dh\"",
+									},
+									&litMatcher{
+										pos:        position{line: 52, col: 30, offset: 1105},
+										val:        "dl",
+										ignoreCase: false,
+										want:       "\"dl\"",
+									},
+									&litMatcher{
+										pos:        position{line: 52, col: 37, offset: 1112},
+										val:        "k",
+										ignoreCase: false,
+										want:       "\"k\"",
+									},
+									&litMatcher{
+										pos:        position{line: 52, col: 43, offset: 1118},
+										val:        "r",
+										ignoreCase: false,
+										want:       "\"r\"",
+									},
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 52, col: 49, offset: 1124},
 								name: "Integer",
 							},
 						},
 					},
+					&litMatcher{
+						pos:        position{line: 53, col: 7, offset: 1138},
+						val:        "!",
+						ignoreCase: false,
+						want:       "\"!\"",
+					},
 				},
 			},
 		},
 		{
 			name: "Text",
-			pos:  position{line: 48, col: 1, offset: 1013},
+			pos:  position{line: 55, col: 1, offset: 1143},
 			expr: &actionExpr{
-				pos: position{line: 49, col: 7, offset: 1024},
+				pos: position{line: 56, col: 7, offset: 1154},
 				run: (*parser).callonText1,
 				expr: &labeledExpr{
-					pos:   position{line: 49, col: 7, offset: 1024},
+					pos:   position{line: 56, col: 7, offset: 1154},
 					label: "chars",
 					expr: &oneOrMoreExpr{
-						pos: position{line: 49, col: 13, offset: 1030},
+						pos: position{line: 56, col: 13, offset: 1160},
 						expr: &seqExpr{
-							pos: position{line: 49, col: 15, offset: 1032},
+							pos: position{line: 56, col: 15, offset: 1162},
 							exprs: []any{
 								&notExpr{
-									pos: position{line: 49, col: 15, offset: 1032},
+									pos: position{line: 56, col: 15, offset: 1162},
 									expr: &choiceExpr{
-										pos: position{line: 49, col: 18, offset: 1035},
+										pos: position{line: 56, col: 18, offset: 1165},
 										alternatives: []any{
 											&litMatcher{
-												pos:        position{line: 49, col: 18, offset: 1035},
+												pos:        position{line: 56, col: 18, offset: 1165},
 												val:        "{",
 												ignoreCase: false,
 												want:       "\"{\"",
 											},
 											&litMatcher{
-												pos:        position{line: 49, col: 24, offset: 1041},
+												pos:        position{line: 56, col: 24, offset: 1171},
 												val:        "[",
 												ignoreCase: false,
 												want:       "\"[\"",
 											},
 											&ruleRefExpr{
-												pos:  position{line: 49, col: 30, offset: 1047},
+												pos:  position{line: 56, col: 30, offset: 1177},
 												name: "Dice",
 											},
 										},
 									},
 								},
 								&anyMatcher{
-									line: 49, col: 37, offset: 1054,
+									line: 56, col: 37, offset: 1183,
 								},
 							},
 						},
@@ -351,14 +470,14 @@ var g = &grammar{
 		},
 		{
 			name: "Integer",
-			pos:  position{line: 53, col: 1, offset: 1116},
+			pos:  position{line: 60, col: 1, offset: 1246},
 			expr: &actionExpr{
-				pos: position{line: 54, col: 7, offset: 1130},
+				pos: position{line: 61, col: 7, offset: 1260},
 				run: (*parser).callonInteger1,
 				expr: &oneOrMoreExpr{
-					pos: position{line: 54, col: 7, offset: 1130},
+					pos: position{line: 61, col: 7, offset: 1260},
 					expr: &charClassMatcher{
-						pos:        position{line: 54, col: 7, offset: 1130},
+						pos:        position{line: 61, col: 7, offset: 1260},
 						val:        "[0-9]",
 						ranges:     []rune{'0', '9'},
 						ignoreCase: false,
@@ -369,11 +488,11 @@ var g = &grammar{
 		},
 		{
 			name: "_",
-			pos:  position{line: 58, col: 1, offset: 1190},
+			pos:  position{line: 65, col: 1, offset: 1320},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 59, col: 7, offset: 1198},
+				pos: position{line: 66, col: 7, offset: 1328},
 				expr: &charClassMatcher{
-					pos:        position{line: 59, col: 7, offset: 1198},
+					pos:        position{line: 66, col: 7, offset: 1328},
 					val:        "[ \\t\\n\\r]",
 					chars:      []rune{' ', '\t', '\n', '\r'},
 					ignoreCase: false,
@@ -383,11 +502,11 @@ var g = &grammar{
 		},
 		{
 			name: "EOF",
-			pos:  position{line: 61, col: 1, offset: 1210},
+			pos:  position{line: 68, col: 1, offset: 1340},
 			expr: &notExpr{
-				pos: position{line: 62, col: 7, offset: 1220},
+				pos: position{line: 69, col: 7, offset: 1350},
 				expr: &anyMatcher{
-					line: 62, col: 8, offset: 1221,
+					line: 69, col: 8, offset: 1350,
 				},
 			},
 		},
@@ -478,15 +597,15 @@ func (p *parser) callonTableName1() (any, error) {
 	return p.cur.onTableName1(stack["chars"])
 }
 
-func (c *current) onDice1(count, sides any) (any, error) {
-	return Dice{Count: count.(int), Sides: sides.(int)}, nil
+func (c *current) onDice1() (any, error) {
+	return newDice(string(c.text)), nil
 
 }
 
 func (p *parser) callonDice1() (any, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onDice1(stack["count"], stack["sides"])
+	return p.cur.onDice1()
 }
 
 func (c *current) onText1(chars any) (any, error) {
@@ -72,6 +72,55 @@ func TestParseDice(t *testing.T) {
 	}
 }
 
+func TestParseDiceExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		expr  string
+		rest  string
+	}{
+		{"2d6+1", "2d6+1", ""},
+		{"4d6kh3 gold", "4d6kh3", " gold"},
+		{"1d8r1-1d4", "1d8r1-1d4", ""},
+		{"1d6! rats", "1d6!", " rats"},
+		{"1d%", "1d%", ""},
+		{"1d6+ rats", "1d6", "+ rats"},
+		{"d6 rats", "d6", " rats"},
+		{"d20", "d20", ""},
+		{"d%", "d%", ""},
+		{"d6+1", "d6+1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := Parse("", []byte(tt.input))
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+
+			parts := result.([]interface{})
+			dice, ok := parts[0].(Dice)
+			if !ok {
+				t.Fatalf("Expected Dice, got %T", parts[0])
+			}
+			if dice.Expr != tt.expr {
+				t.Errorf("Expected expression %q, got %q", tt.expr, dice.Expr)
+			}
+
+			rest := ""
+			for _, part := range parts[1:] {
+				text, ok := part.(Text)
+				if !ok {
+					t.Fatalf("Expected Text, got %T", part)
+				}
+				rest += text.Value
+			}
+			if rest != tt.rest {
+				t.Errorf("Expected remaining text %q, got %q", tt.rest, rest)
+			}
+		})
+	}
+}
+
 func TestParseText(t *testing.T) {
 	input := "hello world"
 	result, err := Parse("", []byte(input))
@@ -8,72 +8,177 @@ import (
 	"github.com/script-wizards/spells/internal/dice"
 )
 
+// DefaultMaxDepth is how many levels of nested table references are
+// resolved automatically. Deeper references are left as [table_name] so they
+// can be expanded by hand. Cycles are caught as a CycleError well before
+// this, so it only bounds long chains of distinct tables.
+const DefaultMaxDepth = 10
+
 type Resolver struct {
-	tables map[string]string
-	rng    *rand.Rand
+	tables   map[string]*List
+	rng      *rand.Rand
+	maxDepth int
+}
+
+// CycleError reports a table that refers back to itself, directly or through
+// other tables. Path lists the tables in the order they were entered and ends
+// with the repeated table.
+type CycleError struct {
+	Path []string
 }
 
-func NewResolver(tables map[string]string, rng *rand.Rand) *Resolver {
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("circular table reference: %s", strings.Join(e.Path, " → "))
+}
+
+func NewResolver(tables map[string]*List, rng *rand.Rand) *Resolver {
 	return &Resolver{
-		tables: tables,
-		rng:    rng,
+		tables:   tables,
+		rng:      rng,
+		maxDepth: DefaultMaxDepth,
 	}
 }
 
+// SetMaxDepth sets how many levels of nested table references are resolved.
+func (r *Resolver) SetMaxDepth(depth int) {
+	r.maxDepth = depth
+}
+
 func (r *Resolver) Resolve(input string) (string, error) {
+	tree, err := r.ResolveTree(input)
+	if err != nil {
+		return "", err
+	}
+	return tree.Result, nil
+}
+
+// ResolveTree expands input and returns the full resolution: the root node's
+// Result is the same string Resolve returns, and its children record where
+// each piece of that string came from.
+func (r *Resolver) ResolveTree(input string) (*Node, error) {
+	children, result, err := r.resolve(input, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{
+		Type:     NodeExpression,
+		Source:   input,
+		Result:   result,
+		Children: children,
+	}, nil
+}
+
+// resolve expands input, where stack holds the tables currently being
+// resolved, outermost first.
+func (r *Resolver) resolve(input string, stack []string) ([]*Node, string, error) {
 	parsed, err := Parse("", []byte(input))
 	if err != nil {
-		return "", fmt.Errorf("parse error: %w", err)
+		return nil, "", fmt.Errorf("parse error: %w", err)
 	}
 
 	parts, ok := parsed.([]interface{})
 	if !ok {
-		return "", fmt.Errorf("unexpected parse result type")
+		return nil, "", fmt.Errorf("unexpected parse result type")
 	}
 
+	nodes := make([]*Node, 0, len(parts))
 	var result strings.Builder
 	for _, part := range parts {
-		resolved, err := r.resolvePart(part)
+		node, err := r.resolvePart(part, stack)
 		if err != nil {
-			return "", err
+			return nil, "", err
 		}
-		result.WriteString(resolved)
+		nodes = append(nodes, node)
+		result.WriteString(node.Result)
 	}
 
-	return result.String(), nil
+	return nodes, result.String(), nil
 }
 
-func (r *Resolver) resolvePart(part interface{}) (string, error) {
+func (r *Resolver) resolvePart(part interface{}, stack []string) (*Node, error) {
 	switch p := part.(type) {
 	case Choice:
 		if len(p.Options) == 0 {
-			return "", fmt.Errorf("empty choice")
+			return nil, fmt.Errorf("empty choice")
 		}
 		idx := r.rng.Intn(len(p.Options))
-		return strings.TrimSpace(p.Options[idx]), nil
+		option := strings.TrimSpace(p.Options[idx])
+		return &Node{
+			Type:   NodeChoice,
+			Source: "{" + strings.Join(p.Options, "|") + "}",
+			Option: option,
+			Result: option,
+		}, nil
 
 	case Table:
-		if r.tables == nil {
-			return fmt.Sprintf("[%s]", p.Name), nil
+		node := &Node{
+			Type:   NodeTable,
+			Source: fmt.Sprintf("[%s]", p.Name),
+			Table:  p.Name,
+		}
+		for i, name := range stack {
+			if name == p.Name {
+				path := append(append([]string{}, stack[i:]...), p.Name)
+				return nil, &CycleError{Path: path}
+			}
+		}
+		list, exists := r.lookup(p.Name)
+		if !exists || len(stack) >= r.maxDepth {
+			node.Result = node.Source
+			node.Unresolved = true
+			return node, nil
 		}
-		value, exists := r.tables[p.Name]
-		if !exists {
-			return fmt.Sprintf("[%s]", p.Name), nil
+		item, err := list.Pick(r.rng)
+		if err != nil {
+			return nil, err
+		}
+		children, result, err := r.resolve(item.Text, append(stack, p.Name))
+		if err != nil {
+			return nil, err
 		}
-		return r.Resolve(value)
+		node.Option = item.Text
+		node.Result = result
+		node.Children = children
+		return node, nil
 
 	case Dice:
-		expr := fmt.Sprintf("%dd%d", p.Count, p.Sides)
-		total, _, err := dice.Roll(expr, r.rng)
+		total, rolls, err := dice.Roll(p.Expr, r.rng)
 		if err != nil {
-			return "", fmt.Errorf("dice roll error: %w", err)
+			return nil, fmt.Errorf("dice roll error: %w", err)
 		}
-		return fmt.Sprintf("%d", total), nil
+		return &Node{
+			Type:   NodeDice,
+			Source: p.Expr,
+			Rolls:  rolls,
+			Result: fmt.Sprintf("%d", total),
+		}, nil
 
 	case Text:
-		return p.Value, nil
+		return &Node{
+			Type:   NodeText,
+			Source: p.Value,
+			Result: p.Value,
+		}, nil
 
 	default:
-		return "", fmt.Errorf("unknown part type: %T", part)
+		return nil, fmt.Errorf("unknown part type: %T", part)
+	}
+}
+
+// lookup finds a table by name. Dotted names such as creature.beast select a
+// sub-list of a table.
+func (r *Resolver) lookup(name string) (*List, bool) {
+	if r.tables == nil {
+		return nil, false
+	}
+	if list, exists := r.tables[name]; exists {
+		return list, true
+	}
+
+	path := strings.Split(name, ".")
+	list, exists := r.tables[path[0]]
+	if !exists {
+		return nil, false
 	}
+	return list.Sub(path[1:])
 }
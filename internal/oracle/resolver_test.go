@@ -1,7 +1,10 @@
 package oracle
 
 import (
+	"errors"
 	"math/rand"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -31,7 +34,7 @@ func TestResolveTable(t *testing.T) {
 		"weapon":   "{sword|axe}",
 	}
 	rng := newTestRng(1)
-	resolver := NewResolver(tables, rng)
+	resolver := NewResolver(Templates(tables), rng)
 
 	result, err := resolver.Resolve("[creature]")
 	if err != nil {
@@ -48,7 +51,7 @@ func TestResolveTableWithChoice(t *testing.T) {
 		"weapon": "{sword|axe}",
 	}
 	rng := newTestRng(2)
-	resolver := NewResolver(tables, rng)
+	resolver := NewResolver(Templates(tables), rng)
 
 	result, err := resolver.Resolve("[weapon]")
 	if err != nil {
@@ -75,6 +78,51 @@ func TestResolveDice(t *testing.T) {
 	}
 }
 
+func TestResolveBareDice(t *testing.T) {
+	resolver := NewResolver(nil, newTestRng(3))
+
+	tree, err := resolver.ResolveTree("d6 bandits")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+
+	node := tree.Children[0]
+	if node.Type != NodeDice || node.Source != "d6" || len(node.Rolls) != 1 {
+		t.Fatalf("Expected one roll for d6, got %s %q %v", node.Type, node.Source, node.Rolls)
+	}
+	if total, err := strconv.Atoi(node.Result); err != nil || total < 1 || total > 6 {
+		t.Fatalf("Expected a result between 1 and 6, got %q", node.Result)
+	}
+	if !strings.HasSuffix(tree.Result, " bandits") {
+		t.Errorf("Expected the text to follow the roll, got %q", tree.Result)
+	}
+}
+
+func TestResolveDiceExpression(t *testing.T) {
+	resolver := NewResolver(nil, newTestRng(7))
+
+	tree, err := resolver.ResolveTree("4d6kh3+2 gold")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+
+	node := tree.Children[0]
+	if node.Type != NodeDice || node.Source != "4d6kh3+2" {
+		t.Fatalf("Expected dice node for 4d6kh3+2, got %s %q", node.Type, node.Source)
+	}
+	if len(node.Rolls) != 4 {
+		t.Fatalf("Expected 4 rolls, got %v", node.Rolls)
+	}
+
+	total, err := strconv.Atoi(node.Result)
+	if err != nil {
+		t.Fatalf("Expected numeric result, got %q", node.Result)
+	}
+	if total < 5 || total > 20 {
+		t.Fatalf("Expected total between 5 and 20, got %d", total)
+	}
+}
+
 func TestResolveText(t *testing.T) {
 	resolver := NewResolver(nil, nil)
 
@@ -93,7 +141,7 @@ func TestResolveNested(t *testing.T) {
 		"creature": "rat",
 	}
 	rng := newTestRng(3)
-	resolver := NewResolver(tables, rng)
+	resolver := NewResolver(Templates(tables), rng)
 
 	result, err := resolver.Resolve("You encounter 1d4 [creature]")
 	if err != nil {
@@ -120,3 +168,133 @@ func TestResolveMissingTable(t *testing.T) {
 		t.Fatalf("Expected '[missing]', got %s", result)
 	}
 }
+
+func TestResolveDepthLimit(t *testing.T) {
+	tables := map[string]string{
+		"a": "A[b]",
+		"b": "B[c]",
+		"c": "C[d]",
+		"d": "D",
+	}
+
+	resolver := NewResolver(Templates(tables), newTestRng(1))
+	result, err := resolver.Resolve("[a]")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if result != "ABCD" {
+		t.Fatalf("Expected 'ABCD' at default depth, got %s", result)
+	}
+
+	resolver.SetMaxDepth(2)
+	result, err = resolver.Resolve("[a]")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if result != "AB[c]" {
+		t.Fatalf("Expected 'AB[c]' with depth 2, got %s", result)
+	}
+}
+
+func TestResolveCircularReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables map[string]string
+		input  string
+		path   []string
+	}{
+		{
+			name:   "self reference",
+			tables: map[string]string{"loop": "again [loop]"},
+			input:  "[loop]",
+			path:   []string{"loop", "loop"},
+		},
+		{
+			name:   "two table cycle",
+			tables: map[string]string{"a": "[b]", "b": "[a]"},
+			input:  "start [a]",
+			path:   []string{"a", "b", "a"},
+		},
+		{
+			name:   "cycle below entry table",
+			tables: map[string]string{"x": "[a]", "a": "[b]", "b": "[a]"},
+			input:  "[x]",
+			path:   []string{"a", "b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewResolver(Templates(tt.tables), newTestRng(1))
+
+			_, err := resolver.Resolve(tt.input)
+			var cycleErr *CycleError
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("Expected CycleError, got %v", err)
+			}
+			if !reflect.DeepEqual(cycleErr.Path, tt.path) {
+				t.Fatalf("Expected cycle path %v, got %v", tt.path, cycleErr.Path)
+			}
+			if !strings.Contains(err.Error(), strings.Join(tt.path, " → ")) {
+				t.Fatalf("Expected error to show cycle path, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestResolveTree(t *testing.T) {
+	tables := map[string]string{
+		"patrol": "1d4 {goblins|orcs} with [gear]",
+		"gear":   "short swords",
+	}
+	resolver := NewResolver(Templates(tables), newTestRng(5))
+
+	tree, err := resolver.ResolveTree("[patrol] near [lair]")
+	if err != nil {
+		t.Fatalf("ResolveTree error: %v", err)
+	}
+
+	if tree.Type != NodeExpression || tree.Source != "[patrol] near [lair]" {
+		t.Fatalf("Unexpected root node: %+v", tree)
+	}
+	if len(tree.Children) != 3 {
+		t.Fatalf("Expected 3 root children, got %d", len(tree.Children))
+	}
+
+	patrol := tree.Children[0]
+	if patrol.Type != NodeTable || patrol.Table != "patrol" || patrol.Option != tables["patrol"] {
+		t.Fatalf("Unexpected patrol node: %+v", patrol)
+	}
+
+	var types []string
+	for _, child := range patrol.Children {
+		types = append(types, child.Type)
+	}
+	expected := []string{NodeDice, NodeText, NodeChoice, NodeText, NodeTable}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("Expected patrol children %v, got %v", expected, types)
+	}
+
+	diceNode := patrol.Children[0]
+	if diceNode.Source != "1d4" || len(diceNode.Rolls) != 1 || diceNode.Result != strconv.Itoa(diceNode.Rolls[0]) {
+		t.Errorf("Unexpected dice node: %+v", diceNode)
+	}
+
+	choiceNode := patrol.Children[2]
+	if choiceNode.Option != choiceNode.Result || (choiceNode.Option != "goblins" && choiceNode.Option != "orcs") {
+		t.Errorf("Unexpected choice node: %+v", choiceNode)
+	}
+
+	lair := tree.Children[2]
+	if !lair.Unresolved || lair.Result != "[lair]" {
+		t.Errorf("Expected missing table to be unresolved, got %+v", lair)
+	}
+
+	var rebuilt strings.Builder
+	for _, child := range tree.Children {
+		rebuilt.WriteString(child.Result)
+	}
+	if rebuilt.String() != tree.Result {
+		t.Errorf("Expected children to rebuild %q, got %q", tree.Result, rebuilt.String())
+	}
+}
@@ -1,5 +1,7 @@
 package oracle
 
+import "github.com/script-wizards/spells/internal/dice"
+
 type Choice struct {
 	Options []string `json:"options"`
 }
@@ -8,11 +10,52 @@ type Table struct {
 	Name string `json:"name"`
 }
 
+// Dice is a dice expression such as 2d6+1 or 4d6kh3. Count and Sides
+// describe its first dice term.
 type Dice struct {
-	Count int `json:"count"`
-	Sides int `json:"sides"`
+	Expr  string `json:"expr"`
+	Count int    `json:"count"`
+	Sides int    `json:"sides"`
+}
+
+func newDice(expr string) Dice {
+	d := Dice{Expr: expr}
+	if parsed, err := dice.Parse(expr); err == nil {
+		for _, term := range parsed.Terms {
+			if term.IsDice() {
+				d.Count = term.Count
+				d.Sides = term.Sides
+				break
+			}
+		}
+	}
+	return d
 }
 
 type Text struct {
 	Value string `json:"value"`
 }
+
+const (
+	NodeExpression = "expression"
+	NodeChoice     = "choice"
+	NodeTable      = "table"
+	NodeDice       = "dice"
+	NodeText       = "text"
+)
+
+// Node is one step of an oracle resolution. Source is the syntax that was
+// resolved and Result the text it produced. Option is the choice or table
+// item that was picked, Rolls the individual dice, and Children the nodes
+// produced by resolving a picked table item. Unresolved marks a table that
+// was missing or beyond the depth limit.
+type Node struct {
+	Type       string  `json:"type"`
+	Source     string  `json:"source"`
+	Table      string  `json:"table,omitempty"`
+	Option     string  `json:"option,omitempty"`
+	Rolls      []int   `json:"rolls,omitempty"`
+	Result     string  `json:"result"`
+	Unresolved bool    `json:"unresolved,omitempty"`
+	Children   []*Node `json:"children,omitempty"`
+}
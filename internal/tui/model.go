@@ -2,9 +2,12 @@ package tui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/script-wizards/spells/internal/clock"
 	"github.com/script-wizards/spells/internal/engine"
 	"github.com/script-wizards/spells/internal/model"
 	"github.com/script-wizards/spells/internal/search"
@@ -16,18 +19,49 @@ const (
 	NormalMode Mode = iota
 	SearchMode
 	AddCombatantMode
+	DamageMode
+	HealMode
+	ConditionMode
 )
 
 type Model struct {
 	engine        *engine.Engine
 	session       *model.Session
 	sessionID     int64
+	calendar      *clock.Calendar
 	mode          Mode
 	searchQuery   string
 	searchIndex   search.Index
 	searchResults []model.NPC
 	encounter     *model.Encounter
 	combatants    []model.Combatant
+	selected      int
+	input         string
+	timeEvents    []model.TimeEvent
+	timers        []model.Timer
+	journal       []model.JournalEntry
+	xpEarned      int
+	light         *model.Character
+	now           time.Time
+	alerts        []string
+	events        chan engine.Event
+}
+
+// maxAlerts is how many triggered events the alerts pane keeps.
+const maxAlerts = 5
+
+// maxJournal is how many recent actions the journal pane lists.
+const maxJournal = 5
+
+// timerTickInterval is how often the timers pane counts down.
+const timerTickInterval = time.Second
+
+// timerTickMsg redraws the timers pane with the time left.
+type timerTickMsg time.Time
+
+// busEventMsg carries an engine event from the EventBus into Update.
+type busEventMsg struct {
+	event engine.Event
 }
 
 func NewModel(eng *engine.Engine, sessionID int64) (Model, error) {
@@ -47,46 +81,550 @@ func NewModel(eng *engine.Engine, sessionID int64) (Model, error) {
 
 	var combatants []model.Combatant
 	if encounter != nil {
-		combatants, _ = model.ListActiveBySort(eng.DB, encounter.ID)
+		combatants, _ = eng.Combatants(encounter.ID)
+	}
+
+	timeEvents, _ := model.ListPendingTimeEvents(eng.DB, sessionID)
+	timers, _ := model.ListRunningTimers(eng.DB)
+	journal, _ := model.ListJournal(eng.DB, &sessionID, maxJournal)
+	xpEarned, _ := model.SessionXP(eng.DB, sessionID)
+
+	calendar, err := eng.Calendar()
+	if err != nil {
+		return Model{}, err
 	}
 
 	m := Model{
 		engine:      eng,
 		session:     session,
 		sessionID:   sessionID,
+		calendar:    calendar,
 		mode:        NormalMode,
 		searchQuery: "",
 		searchIndex: searchIndex,
 		encounter:   encounter,
 		combatants:  combatants,
+		timeEvents:  timeEvents,
+		timers:      timers,
+		journal:     journal,
+		xpEarned:    xpEarned,
+		now:         time.Now(),
 	}
+	m.refreshLight()
 
 	if eng.EventBus != nil {
-		eng.EventBus.Subscribe("TurnAdvanced", func(event engine.Event) {
-			if turnEvent, ok := event.(engine.TurnAdvanced); ok && turnEvent.SessionID == sessionID {
-				m.session.CurrentTurn = turnEvent.NewTurn
+		m.events = make(chan engine.Event, 64)
+		forward := func(event engine.Event) {
+			select {
+			case m.events <- event:
+			default:
 			}
-		})
+		}
+		eng.EventBus.Subscribe("TurnAdvanced", forward)
+		eng.EventBus.Subscribe("RoundAdvanced", forward)
+		eng.EventBus.Subscribe("TimeModeChanged", forward)
+		eng.EventBus.Subscribe("EventTriggered", forward)
+		eng.EventBus.Subscribe("WanderingCheckRolled", forward)
+		eng.EventBus.Subscribe("TimerStarted", forward)
+		eng.EventBus.Subscribe("TimerExpired", forward)
+		eng.EventBus.Subscribe("TimerFinished", forward)
+		eng.EventBus.Subscribe("DataChanged", forward)
+		eng.EventBus.Subscribe("ActionRecorded", forward)
+		for _, eventType := range []string{"EncounterStarted", "EncounterEnded", "CombatantAdded", "CombatantRemoved",
+			"CombatantStatusChanged", "DamageApplied", "CombatantHealed", "CombatantDefeated", "CombatTurnStarted",
+			"SideInitiativeRolled", "ConditionAdded", "ConditionRemoved", "ConditionExpired", "MoraleChecked",
+			"AttackResolved", "XPEarned", "LightLow", "LightOut", "ConsumableRanOut"} {
+			eng.EventBus.Subscribe(eventType, forward)
+		}
 	}
 
 	return m, nil
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	if m.engine == nil {
+		return waitForEvent(m.events)
+	}
+	return tea.Batch(waitForEvent(m.events), tickTimers())
+}
+
+func tickTimers() tea.Cmd {
+	return tea.Tick(timerTickInterval, func(t time.Time) tea.Msg {
+		return timerTickMsg(t)
+	})
+}
+
+// waitForEvent returns a command that delivers the next engine event.
+func waitForEvent(events chan engine.Event) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return busEventMsg{event: <-events}
+	}
+}
+
+func (m *Model) handleEvent(event engine.Event) {
+	switch event := event.(type) {
+	case engine.TurnAdvanced:
+		if event.SessionID == m.sessionID {
+			if m.session != nil {
+				m.session.CurrentTurn = event.NewTurn
+			}
+			m.refreshTimeEvents()
+			m.refreshLight()
+		}
+	case engine.RoundAdvanced:
+		if event.SessionID == m.sessionID && m.session != nil {
+			m.session.CurrentTurn = event.NewTurn
+			m.session.CurrentRound = event.NewRound
+		}
+	case engine.TimeModeChanged:
+		if event.SessionID == m.sessionID && m.session != nil {
+			m.session.TimeMode = event.Mode
+		}
+	case engine.EventTriggered:
+		if event.SessionID == m.sessionID {
+			alert := fmt.Sprintf("Turn %d: %s", event.TriggerTurn, event.EventType)
+			if event.Description != "" {
+				alert = fmt.Sprintf("Turn %d: %s", event.TriggerTurn, event.Description)
+			}
+			m.addAlert(alert)
+		}
+	case engine.WanderingCheckRolled:
+		if event.SessionID == m.sessionID {
+			where := ""
+			if event.Location != "" {
+				where = " (" + event.Location + ")"
+			}
+			if !event.Encountered {
+				m.addAlert(fmt.Sprintf("Wandering check%s: %d on d%d, nothing", where, event.Roll, event.Die))
+				return
+			}
+			m.addAlert(fmt.Sprintf("Wandering check%s: %d on d%d, %s!", where, event.Roll, event.Die, event.Result))
+			if event.EncounterID != nil {
+				m.refreshEncounter()
+			}
+		}
+	case engine.TimerExpired:
+		m.addAlert(fmt.Sprintf("Timer expired: %s", event.Label))
+		m.refreshTimers()
+	case engine.TimerStarted, engine.TimerFinished:
+		m.refreshTimers()
+	case engine.CombatantDefeated:
+		if event.SessionID == m.sessionID {
+			m.addAlert(fmt.Sprintf("%s is defeated", event.Name))
+			m.refreshEncounter()
+		}
+	case engine.MoraleChecked:
+		if event.SessionID == m.sessionID {
+			m.addAlert(fmt.Sprintf("Morale (%s, %s): %s", event.Side, event.Trigger, event.Summary()))
+			m.refreshEncounter()
+		}
+	case engine.AttackResolved:
+		if event.SessionID == m.sessionID {
+			m.addAlert(event.Summary())
+			m.refreshEncounter()
+		}
+	case engine.XPEarned:
+		if event.SessionID == m.sessionID {
+			m.refreshXP()
+		}
+	case engine.LightLow:
+		if event.SessionID == m.sessionID {
+			m.addAlert(event.Summary())
+		}
+	case engine.LightOut:
+		if event.SessionID == m.sessionID {
+			m.addAlert(event.Summary())
+			m.refreshLight()
+		}
+	case engine.ConsumableRanOut:
+		m.addAlert(event.Summary())
+	case engine.ConditionExpired:
+		if event.SessionID == m.sessionID {
+			m.addAlert(fmt.Sprintf("%s is no longer %s", event.Name, event.Condition))
+			m.refreshEncounter()
+		}
+	case engine.ConditionAdded, engine.ConditionRemoved:
+		m.refreshEncounter()
+	case engine.SideInitiativeRolled:
+		if event.SessionID == m.sessionID {
+			m.addAlert(fmt.Sprintf("Round %d initiative: %s", event.Round, event.Summary()))
+		}
+	case engine.EncounterStarted, engine.EncounterEnded, engine.CombatantAdded, engine.CombatantRemoved,
+		engine.CombatantStatusChanged, engine.DamageApplied, engine.CombatantHealed, engine.CombatTurnStarted:
+		m.refreshEncounter()
+	case engine.ActionRecorded:
+		if event.SessionID == nil || *event.SessionID == m.sessionID {
+			m.refreshJournal()
+		}
+	case engine.DataChanged:
+		if event.SessionID != nil && *event.SessionID != m.sessionID {
+			return
+		}
+		switch event.Table {
+		case "sessions", "time_events":
+			m.refreshTimeEvents()
+		case "encounters", "initiative_order":
+			m.refreshEncounter()
+		case "timers":
+			m.refreshTimers()
+		case "npcs":
+			m.refreshSearchIndex()
+		case "journal":
+			m.refreshJournal()
+		case "xp_ledger":
+			m.refreshXP()
+		case "characters":
+			m.refreshLight()
+		}
+	}
+}
+
+func (m *Model) addAlert(alert string) {
+	m.alerts = append(m.alerts, alert)
+	if len(m.alerts) > maxAlerts {
+		m.alerts = m.alerts[len(m.alerts)-maxAlerts:]
+	}
+}
+
+func (m *Model) refreshEncounter() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	encounter, err := model.GetActiveEncounter(m.engine.DB, m.sessionID)
+	if err != nil {
+		return
+	}
+	m.encounter = encounter
+	m.combatants = nil
+	if encounter != nil {
+		m.combatants, _ = m.engine.Combatants(encounter.ID)
+	}
+	m.selected = max(min(m.selected, len(m.combatants)-1), 0)
+}
+
+// sideInitiative reports whether the campaign rolls initiative by side.
+func (m Model) sideInitiative() bool {
+	return m.engine != nil && m.engine.Config != nil && m.engine.Config.Combat.Initiative == engine.InitiativeSide
+}
+
+// selectedCombatant returns the combatant under the cursor in the
+// initiative pane, or nil when there is none.
+func (m *Model) selectedCombatant() *model.Combatant {
+	if m.selected < 0 || m.selected >= len(m.combatants) {
+		return nil
+	}
+	return &m.combatants[m.selected]
+}
+
+// combat runs a combat action, showing any error as an alert, and reloads
+// the encounter.
+func (m *Model) combat(action func(*engine.Engine) error) {
+	if m.engine == nil || m.sessionID == 0 {
+		return
+	}
+	if err := action(m.engine); err != nil {
+		m.addAlert(err.Error())
+	}
+	m.refreshEncounter()
+	m.refreshTimeEvents()
+	m.refreshJournal()
+}
+
+// addCombatant adds the combatant typed in the add combatant modal as
+// "name [initiative [hp]]", starting an encounter when none is running.
+func (m *Model) addCombatant(input string) {
+	name, initiative, hp, err := parseCombatant(input)
+	if err != nil {
+		m.addAlert(err.Error())
+		return
+	}
+	m.combat(func(eng *engine.Engine) error {
+		encounterID := int64(0)
+		if m.encounter != nil {
+			encounterID = m.encounter.ID
+		} else {
+			encounter, err := eng.StartEncounter(m.sessionID, "")
+			if err != nil {
+				return err
+			}
+			encounterID = encounter.ID
+		}
+
+		if hp == 0 {
+			monster, count, err := eng.FindMonsters(name)
+			if err != nil {
+				return err
+			}
+			if monster != nil {
+				_, err := eng.AddMonsters(encounterID, monster, count, initiative, "")
+				return err
+			}
+		}
+
+		combatant := &model.InitiativeOrder{EncounterID: encounterID, CharacterName: &name, Initiative: initiative}
+		if hp > 0 {
+			combatant.HPCurrent, combatant.HPMax = &hp, &hp
+		}
+		return eng.AddCombatant(combatant)
+	})
+}
+
+// parseCombatant splits "Goblin 12 4" into a name, initiative and HP. The
+// numbers are optional.
+func parseCombatant(input string) (string, int, int, error) {
+	fields := strings.Fields(input)
+	var numbers []int
+	for len(fields) > 1 && len(numbers) < 2 {
+		n, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			break
+		}
+		numbers = append([]int{n}, numbers...)
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) == 0 {
+		return "", 0, 0, fmt.Errorf("combatant needs a name")
+	}
+
+	name := strings.Join(fields, " ")
+	switch len(numbers) {
+	case 2:
+		return name, numbers[0], numbers[1], nil
+	case 1:
+		return name, numbers[0], 0, nil
+	default:
+		return name, 0, 0, nil
+	}
+}
+
+// applyCondition puts the typed condition on the selected combatant, or
+// takes it off when it starts with "-".
+func (m *Model) applyCondition() {
+	combatant := m.selectedCombatant()
+	if combatant == nil {
+		return
+	}
+	input := strings.TrimSpace(m.input)
+	if name, remove := strings.CutPrefix(input, "-"); remove {
+		m.combat(func(eng *engine.Engine) error {
+			return eng.RemoveCondition(combatant.ID, strings.TrimSpace(name))
+		})
+		return
+	}
+
+	name, duration, unit, err := parseCondition(input)
+	if err != nil {
+		m.addAlert(err.Error())
+		return
+	}
+	m.combat(func(eng *engine.Engine) error {
+		return eng.AddCondition(combatant.ID, name, duration, unit)
+	})
+}
+
+// parseCondition splits "poisoned 3" or "asleep 2t" into a condition name
+// and a duration in rounds, or turns with a "t" suffix. Without a
+// duration the condition lasts until removed.
+func parseCondition(input string) (string, int64, string, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return "", 0, "", fmt.Errorf("condition needs a name")
+	}
+	if len(fields) == 1 {
+		return fields[0], 0, "", nil
+	}
+
+	last := strings.ToLower(fields[len(fields)-1])
+	unit := model.ConditionRounds
+	if number, turns := strings.CutSuffix(last, "t"); turns {
+		last, unit = number, model.ConditionTurns
+	} else {
+		last = strings.TrimSuffix(last, "r")
+	}
+	duration, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return strings.Join(fields, " "), 0, "", nil
+	}
+	return strings.Join(fields[:len(fields)-1], " "), duration, unit, nil
+}
+
+// applyHP damages or heals the selected combatant by the typed amount.
+func (m *Model) applyHP(heal bool) {
+	combatant := m.selectedCombatant()
+	amount, err := strconv.Atoi(strings.TrimSpace(m.input))
+	if combatant == nil || err != nil {
+		return
+	}
+	m.combat(func(eng *engine.Engine) error {
+		if heal {
+			return eng.Heal(combatant.ID, amount)
+		}
+		return eng.Damage(combatant.ID, amount)
+	})
+}
+
+func (m *Model) refreshTimeEvents() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	if session, err := model.GetSession(m.engine.DB, m.sessionID); err == nil && session != nil {
+		m.session = session
+	}
+	if events, err := model.ListPendingTimeEvents(m.engine.DB, m.sessionID); err == nil {
+		m.timeEvents = events
+	}
+}
+
+func (m *Model) refreshSearchIndex() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	if names, err := model.GetAllNPCNames(m.engine.DB); err == nil {
+		m.searchIndex = search.BuildIndex(names)
+		m.updateSearchResults()
+	}
+}
+
+func (m *Model) refreshTimers() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	if timers, err := model.ListRunningTimers(m.engine.DB); err == nil {
+		m.timers = timers
+	}
+}
+
+func (m *Model) refreshJournal() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	if journal, err := model.ListJournal(m.engine.DB, &m.sessionID, maxJournal); err == nil {
+		m.journal = journal
+	}
+}
+
+func (m *Model) refreshXP() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	if xp, err := model.SessionXP(m.engine.DB, m.sessionID); err == nil {
+		m.xpEarned = xp
+	}
+}
+
+// refreshLight finds the party's light with the fewest turns left to show
+// in the header.
+func (m *Model) refreshLight() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	party, err := model.ListCharacters(m.engine.DB, true)
+	if err != nil {
+		return
+	}
+	m.light = nil
+	for i, character := range party {
+		if character.Lit() && character.LightDuration != nil &&
+			(m.light == nil || *character.LightRemaining < *m.light.LightRemaining) {
+			m.light = &party[i]
+		}
+	}
+}
+
+// lightTorch lights a torch for the first active character without a
+// light, or when the whole party has one, replaces the light closest to
+// going out.
+func (m *Model) lightTorch() {
+	if m.engine == nil || m.engine.DB == nil {
+		return
+	}
+	party, err := model.ListCharacters(m.engine.DB, true)
+	if err != nil {
+		m.addAlert(err.Error())
+		return
+	}
+
+	bearer := m.light
+	for i, character := range party {
+		if !character.Lit() {
+			bearer = &party[i]
+			break
+		}
+	}
+	if bearer == nil {
+		m.addAlert("No one in the party to light a torch")
+		return
+	}
+	if _, err := m.engine.LightSource(bearer.ID, model.LightTorch); err != nil {
+		m.addAlert(err.Error())
+	}
+	m.refreshLight()
+	m.refreshJournal()
+}
+
+// lightInfo shows a light's turns left, e.g. "Torch: 3/10".
+func lightInfo(character model.Character) string {
+	source := "Light"
+	if character.LightSource != nil && *character.LightSource != "" {
+		source = strings.ToUpper((*character.LightSource)[:1]) + (*character.LightSource)[1:]
+	}
+	return fmt.Sprintf("%s: %d/%d", source, *character.LightRemaining, *character.LightDuration)
+}
+
+// revert undoes or redoes the latest action and reloads everything it may
+// have touched.
+func (m *Model) revert(action string) {
+	if m.engine == nil || m.sessionID == 0 {
+		return
+	}
+
+	var err error
+	if action == model.JournalUndo {
+		_, err = m.engine.Undo(m.sessionID)
+	} else {
+		_, err = m.engine.Redo(m.sessionID)
+	}
+	if err != nil {
+		m.addAlert(err.Error())
+		return
+	}
+
+	m.refreshTimeEvents()
+	m.refreshEncounter()
+	m.refreshSearchIndex()
+	m.refreshJournal()
+	m.refreshXP()
+	m.refreshLight()
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case timerTickMsg:
+		m.now = time.Time(msg)
+		m.refreshTimers()
+		return m, tickTimers()
+	case busEventMsg:
+		m.handleEvent(msg.event)
+		return m, waitForEvent(m.events)
 	case tea.KeyMsg:
 		switch m.mode {
 		case NormalMode:
 			switch msg.Type {
 			case tea.KeyCtrlC:
 				return m, tea.Quit
+			case tea.KeyCtrlR:
+				m.revert(model.JournalRedo)
+			case tea.KeyUp:
+				m.selected = max(m.selected-1, 0)
+			case tea.KeyDown:
+				m.selected = max(min(m.selected+1, len(m.combatants)-1), 0)
 			case tea.KeySpace:
 				if m.engine != nil && m.sessionID > 0 {
-					m.engine.Advance(m.sessionID, 1)
+					m.engine.Step(m.sessionID)
+					m.refreshTimeEvents()
 				}
 			default:
 				if msg.Type == tea.KeyRunes {
@@ -97,6 +635,94 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.searchResults = nil
 					case "i":
 						m.mode = AddCombatantMode
+						m.input = ""
+					case "n":
+						if m.encounter != nil {
+							encounterID := m.encounter.ID
+							m.combat(func(eng *engine.Engine) error {
+								_, err := eng.NextTurn(encounterID)
+								return err
+							})
+						}
+					case "m":
+						if combatant := m.selectedCombatant(); combatant != nil {
+							m.combat(func(eng *engine.Engine) error {
+								_, err := eng.CheckMorale(combatant.EncounterID, combatant.Side)
+								return err
+							})
+						}
+					case "P":
+						if m.encounter != nil {
+							encounterID := m.encounter.ID
+							m.combat(func(eng *engine.Engine) error {
+								_, err := eng.AddParty(encounterID, 0)
+								return err
+							})
+						}
+					case "f":
+						// Whoever's turn it is attacks the selected combatant.
+						if target := m.selectedCombatant(); target != nil && m.encounter != nil && m.encounter.CurrentCombatantID != nil {
+							attackerID := *m.encounter.CurrentCombatantID
+							m.combat(func(eng *engine.Engine) error {
+								_, err := eng.Attack(attackerID, target.ID, "")
+								return err
+							})
+						}
+					case "p":
+						if m.selectedCombatant() != nil {
+							m.mode = ConditionMode
+							m.input = ""
+						}
+					case "d", "h":
+						if m.selectedCombatant() != nil {
+							m.mode = DamageMode
+							if string(msg.Runes) == "h" {
+								m.mode = HealMode
+							}
+							m.input = ""
+						}
+					case "a":
+						if combatant := m.selectedCombatant(); combatant != nil {
+							m.combat(func(eng *engine.Engine) error {
+								return eng.SetCombatantActive(combatant.ID, !combatant.IsActive)
+							})
+						}
+					case "s":
+						if combatant := m.selectedCombatant(); combatant != nil {
+							side := model.SideParty
+							if combatant.Side == model.SideParty {
+								side = model.SideMonsters
+							}
+							m.combat(func(eng *engine.Engine) error {
+								return eng.SetCombatantSide(combatant.ID, side)
+							})
+						}
+					case "x":
+						if combatant := m.selectedCombatant(); combatant != nil {
+							m.combat(func(eng *engine.Engine) error {
+								return eng.RemoveCombatant(combatant.ID)
+							})
+						}
+					case "e":
+						if m.encounter != nil {
+							encounterID := m.encounter.ID
+							m.combat(func(eng *engine.Engine) error {
+								return eng.EndEncounter(encounterID)
+							})
+						}
+					case "c":
+						if m.engine != nil && m.session != nil {
+							mode := clock.ModeCombat
+							if m.session.TimeMode == clock.ModeCombat {
+								mode = clock.ModeExploration
+							}
+							m.engine.SetTimeMode(m.sessionID, mode)
+							m.refreshTimeEvents()
+						}
+					case "t":
+						m.lightTorch()
+					case "u":
+						m.revert(model.JournalUndo)
 					}
 				}
 			}
@@ -121,12 +747,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.updateSearchResults()
 				}
 			}
-		case AddCombatantMode:
+		case AddCombatantMode, DamageMode, HealMode, ConditionMode:
 			switch msg.Type {
 			case tea.KeyCtrlC:
 				return m, tea.Quit
 			case tea.KeyEsc:
 				m.mode = NormalMode
+				m.input = ""
+			case tea.KeyEnter:
+				switch m.mode {
+				case AddCombatantMode:
+					m.addCombatant(m.input)
+				case ConditionMode:
+					m.applyCondition()
+				default:
+					m.applyHP(m.mode == HealMode)
+				}
+				m.mode = NormalMode
+				m.input = ""
+			case tea.KeyBackspace:
+				if len(m.input) > 0 {
+					m.input = m.input[:len(m.input)-1]
+				}
+			case tea.KeySpace:
+				m.input += " "
+			default:
+				if msg.Type == tea.KeyRunes {
+					m.input += string(msg.Runes)
+				}
 			}
 		}
 	}
@@ -151,6 +799,20 @@ func (m Model) View() string {
 	turnInfo := "Turn: Not loaded"
 	if m.session != nil {
 		turnInfo = fmt.Sprintf("Turn: %d", m.session.CurrentTurn)
+		if m.session.TimeMode == clock.ModeCombat {
+			turnInfo += fmt.Sprintf("  Round: %d", m.session.CurrentRound+1)
+		}
+		turnInfo += "  XP Earned: " + thousands(m.xpEarned)
+		if m.light != nil {
+			turnInfo += "  " + lightInfo(*m.light)
+		}
+		if m.engine != nil {
+			now := m.engine.Clock().At(m.session.CurrentTurn, m.session.CurrentRound)
+			turnInfo += fmt.Sprintf("\nTime: %s (%s, watch %d)", now, now.TimeOfDay(), now.Watch)
+			if m.calendar != nil {
+				turnInfo += "\n" + calendarInfo(m.calendar, now.Day)
+			}
+		}
 	}
 
 	var view strings.Builder
@@ -178,10 +840,33 @@ func (m Model) View() string {
 			view.WriteString("No NPCs found.\n")
 		}
 	case AddCombatantMode:
-		view.WriteString("Add Combatant Modal (ESC to exit)\n")
-		view.WriteString("This is a stub - implementation pending\n")
+		view.WriteString("Add Combatant (ESC to cancel, Enter to add)\n")
+		view.WriteString("Name, initiative and HP, e.g. \"Goblin 12 4\", or monsters from the catalog, e.g. \"3 goblins\"\n")
+		view.WriteString(fmt.Sprintf("> %s\n", m.input))
+	case DamageMode, HealMode:
+		verb := "Damage"
+		if m.mode == HealMode {
+			verb = "Heal"
+		}
+		name := ""
+		if combatant := m.selectedCombatant(); combatant != nil {
+			name = combatant.Name
+		}
+		view.WriteString(fmt.Sprintf("%s %s by (ESC to cancel, Enter to apply)\n", verb, name))
+		view.WriteString(fmt.Sprintf("> %s\n", m.input))
+	case ConditionMode:
+		name := ""
+		if combatant := m.selectedCombatant(); combatant != nil {
+			name = combatant.Name
+		}
+		view.WriteString(fmt.Sprintf("Condition on %s (ESC to cancel, Enter to apply)\n", name))
+		view.WriteString("e.g. \"poisoned 3\" for 3 rounds, \"asleep 2t\" for 2 turns, \"prone\", or \"-prone\" to remove\n")
+		view.WriteString(fmt.Sprintf("> %s\n", m.input))
 	default:
 		view.WriteString("Initiative Order:\n")
+		if m.encounter != nil && m.encounter.Round > 0 {
+			view.WriteString(fmt.Sprintf("  Round %d\n", m.encounter.Round))
+		}
 		if len(m.combatants) > 0 {
 			for i, combatant := range m.combatants {
 				hpDisplay := "Unknown HP"
@@ -195,9 +880,33 @@ func (m Model) View() string {
 				if combatant.IsNPC {
 					npcIndicator = " (NPC)"
 				}
+				if combatant.CharacterID != nil {
+					npcIndicator = " (PC)"
+				}
+				if m.sideInitiative() {
+					npcIndicator += " [" + combatant.Side + "]"
+				}
+				switch {
+				case combatant.Dead():
+					npcIndicator += " [defeated]"
+				case !combatant.IsActive:
+					npcIndicator += " [inactive]"
+				}
+				if conditions := model.FormatConditions(combatant.Conditions); conditions != "" {
+					npcIndicator += " {" + conditions + "}"
+				}
+
+				cursor := " "
+				if i == m.selected {
+					cursor = ">"
+				}
+				turn := " "
+				if m.encounter != nil && m.encounter.CurrentCombatantID != nil && *m.encounter.CurrentCombatantID == combatant.ID {
+					turn = "▶"
+				}
 
-				view.WriteString(fmt.Sprintf("  %d. %s - Init %d - %s%s\n",
-					i+1, combatant.Name, combatant.Initiative, hpDisplay, npcIndicator))
+				view.WriteString(fmt.Sprintf("%s%s%d. %s - Init %d - %s%s\n",
+					cursor, turn, i+1, combatant.Name, combatant.Initiative, hpDisplay, npcIndicator))
 			}
 		} else if m.encounter != nil {
 			view.WriteString("  No combatants in active encounter\n")
@@ -205,12 +914,102 @@ func (m Model) View() string {
 			view.WriteString("  No active encounter\n")
 		}
 
+		view.WriteString("\nUpcoming Events:\n")
+		if len(m.timeEvents) > 0 {
+			for i, event := range m.timeEvents {
+				if i >= 5 {
+					break
+				}
+				view.WriteString(fmt.Sprintf("  %s @turn %d\n", timeEventLabel(event), event.TriggerTurn))
+			}
+		} else {
+			view.WriteString("  Nothing scheduled\n")
+		}
+
+		if len(m.timers) > 0 {
+			view.WriteString("\nTimers:\n")
+			for _, timer := range m.timers {
+				view.WriteString(fmt.Sprintf("  %s %s\n", timer.Label, timerLabel(timer, m.now)))
+			}
+		}
+
+		if len(m.journal) > 0 {
+			view.WriteString("\nRecent Actions:\n")
+			for _, entry := range m.journal {
+				view.WriteString(fmt.Sprintf("  %s\n", entry.Description))
+			}
+		}
+
+		if len(m.alerts) > 0 {
+			view.WriteString("\nAlerts:\n")
+			for _, alert := range m.alerts {
+				view.WriteString(fmt.Sprintf("  ⚠ %s\n", alert))
+			}
+		}
+
 		view.WriteString("\nOther panes:\n")
 		view.WriteString("- Sessions\n")
 		view.WriteString("- Characters\n")
 		view.WriteString("- Spells\n\n")
-		view.WriteString("Press '/' for NPC search, 'i' to add combatant, Space to advance turn, Ctrl+C to quit")
+		view.WriteString("Press '/' for NPC search, 'i' to add combatant, Space to advance time, 'c' to toggle combat time, 't' to light a torch, 'u' to undo, Ctrl+R to redo, Ctrl+C to quit\n")
+		view.WriteString("Combat: Up/Down to select, 'P' add party, 'n' next turn, 'd' damage, 'h' heal, 'f' attack, 'p' condition, 'm' morale, 'a' toggle active, 's' switch side, 'x' remove, 'e' end encounter")
 	}
 
 	return view.String()
 }
+
+// calendarInfo shows the date, moon phases and any holidays on a day.
+func calendarInfo(calendar *clock.Calendar, day int64) string {
+	info := "Date: " + calendar.Date(day).String()
+
+	var moons []string
+	for _, moon := range calendar.Moons(day) {
+		moons = append(moons, moon.Moon+" "+moon.Phase)
+	}
+	if len(moons) > 0 {
+		info += "\nMoons: " + strings.Join(moons, ", ")
+	}
+
+	var holidays []string
+	for _, holiday := range calendar.Holidays(day, 0) {
+		holidays = append(holidays, holiday.Holiday.Name)
+	}
+	if len(holidays) > 0 {
+		info += "\nHoliday: " + strings.Join(holidays, ", ")
+	}
+	return info
+}
+
+// timerLabel shows the time left on a timer, counting down in seconds.
+// thousands formats a number with commas between groups of three digits,
+// e.g. "1,247".
+func thousands(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return sign + digits
+}
+
+func timerLabel(timer model.Timer, now time.Time) string {
+	if timer.Expired(now) {
+		return "EXPIRED"
+	}
+	remaining := timer.Remaining(now).Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+}
+
+func timeEventLabel(event model.TimeEvent) string {
+	label := event.EventType
+	if event.Description != nil && *event.Description != "" {
+		label = *event.Description
+	}
+	if event.RepeatEvery != nil {
+		label += fmt.Sprintf(" (every %d)", *event.RepeatEvery)
+	}
+	return label
+}
@@ -1,9 +1,17 @@
 package tui
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/script-wizards/spells/internal/clock"
+	"github.com/script-wizards/spells/internal/config"
+	"github.com/script-wizards/spells/internal/db"
+	"github.com/script-wizards/spells/internal/engine"
+	"github.com/script-wizards/spells/internal/model"
 )
 
 func TestModel_Init(t *testing.T) {
@@ -32,3 +40,552 @@ func TestModel_Update_CtrlC(t *testing.T) {
 	// We can't directly compare functions, but we know tea.Quit is returned
 	// This smoke test verifies the Update function doesn't panic and returns a command
 }
+
+func TestModel_HandleEventTriggered(t *testing.T) {
+	m := Model{sessionID: 1}
+
+	for turn := int64(1); turn <= maxAlerts+2; turn++ {
+		newModel, _ := m.Update(busEventMsg{event: engine.EventTriggered{
+			SessionID:   1,
+			EventType:   model.TimeEventReminder,
+			Description: "Guards change",
+			TriggerTurn: turn,
+		}})
+		m = newModel.(Model)
+	}
+	newModel, _ := m.Update(busEventMsg{event: engine.EventTriggered{SessionID: 2, TriggerTurn: 99}})
+	m = newModel.(Model)
+
+	if len(m.alerts) != maxAlerts {
+		t.Fatalf("expected %d alerts, got %d", maxAlerts, len(m.alerts))
+	}
+	if m.alerts[len(m.alerts)-1] != "Turn 7: Guards change" {
+		t.Errorf("unexpected latest alert %q", m.alerts[len(m.alerts)-1])
+	}
+	if !strings.Contains(m.View(), "⚠ Turn 7: Guards change") {
+		t.Error("expected the alert to be shown")
+	}
+}
+
+func TestModel_HandleWanderingCheck(t *testing.T) {
+	m := Model{sessionID: 1}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.WanderingCheckRolled{
+		SessionID: 1, Location: "Level 1", Roll: 4, Chance: 1, Die: 6,
+	}})
+	newModel, _ = newModel.Update(busEventMsg{event: engine.WanderingCheckRolled{
+		SessionID: 1, Roll: 1, Chance: 1, Die: 6, Encountered: true, Result: "3 goblins",
+	}})
+	m = newModel.(Model)
+
+	expected := []string{
+		"Wandering check (Level 1): 4 on d6, nothing",
+		"Wandering check: 1 on d6, 3 goblins!",
+	}
+	if len(m.alerts) != 2 || m.alerts[0] != expected[0] || m.alerts[1] != expected[1] {
+		t.Errorf("expected alerts %q, got %q", expected, m.alerts)
+	}
+}
+
+func TestModel_ViewGameClock(t *testing.T) {
+	m := Model{
+		engine:    &engine.Engine{},
+		sessionID: 1,
+		session:   &model.Session{ID: 1, CurrentTurn: 4, CurrentRound: 2, TimeMode: clock.ModeCombat},
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "Turn: 4  Round: 3") {
+		t.Errorf("expected the combat round in the view, got %q", view)
+	}
+	if !strings.Contains(view, "Time: Day 1, 08:40:20 (morning, watch 3)") {
+		t.Errorf("expected the game clock in the view, got %q", view)
+	}
+}
+
+func TestModel_ViewCalendar(t *testing.T) {
+	calendar, err := clock.NewCalendar(config.CalendarConfig{
+		Months:   []config.MonthConfig{{Name: "Deepwinter", Days: 30}},
+		Moons:    []config.MoonConfig{{Name: "Selune", Cycle: 8, Offset: 4}},
+		Holidays: []config.HolidayConfig{{Name: "Midwinter", Month: "Deepwinter", Day: 1}},
+		Start:    config.DateConfig{Year: 1023},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build calendar: %v", err)
+	}
+
+	m := Model{
+		engine:    &engine.Engine{},
+		sessionID: 1,
+		session:   &model.Session{ID: 1},
+		calendar:  calendar,
+	}
+
+	view := m.View()
+	for _, expected := range []string{"Date: 1 Deepwinter 1023", "Moons: Selune full", "Holiday: Midwinter"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("expected %q in the view, got %q", expected, view)
+		}
+	}
+}
+
+func TestModel_Timers(t *testing.T) {
+	started := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	m := Model{
+		sessionID: 1,
+		now:       started.Add(90 * time.Second),
+		timers: []model.Timer{
+			{ID: 1, Label: "pizza", DurationSeconds: 600, Status: model.TimerRunning, StartedAt: started},
+			{ID: 2, Label: "break", DurationSeconds: 60, Status: model.TimerRunning, StartedAt: started},
+		},
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "pizza 8:30") || !strings.Contains(view, "break EXPIRED") {
+		t.Errorf("expected the timers pane to count down, got %q", view)
+	}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.TimerExpired{TimerID: 2, Label: "break"}})
+	m = newModel.(Model)
+	if len(m.alerts) != 1 || m.alerts[0] != "Timer expired: break" {
+		t.Errorf("expected a timer alert, got %v", m.alerts)
+	}
+}
+
+func TestModel_HandleDataChanged(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "campaign.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	m, err := NewModel(&engine.Engine{DB: database, EventBus: engine.NewEventBus()}, session.ID)
+	if err != nil {
+		t.Fatalf("failed to create model: %v", err)
+	}
+
+	// Another process advances the turn without this model's EventBus.
+	other := &engine.Engine{DB: database}
+	if err := other.Advance(session.ID, 3); err != nil {
+		t.Fatalf("failed to advance: %v", err)
+	}
+	if _, err := other.StartTimer("pizza", time.Minute, nil); err != nil {
+		t.Fatalf("failed to start timer: %v", err)
+	}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.DataChanged{Table: "sessions", RowID: session.ID, SessionID: &session.ID}})
+	m = newModel.(Model)
+	newModel, _ = m.Update(busEventMsg{event: engine.DataChanged{Table: "timers", Action: model.ChangeInsert}})
+	m = newModel.(Model)
+
+	if m.session.CurrentTurn != 3 {
+		t.Errorf("expected the turn from the other process, got %d", m.session.CurrentTurn)
+	}
+	if len(m.timers) != 1 || m.timers[0].Label != "pizza" {
+		t.Errorf("expected the other process's timer, got %+v", m.timers)
+	}
+}
+
+func TestModel_UndoRedo(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "campaign.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	m, err := NewModel(&engine.Engine{DB: database}, session.ID)
+	if err != nil {
+		t.Fatalf("failed to create model: %v", err)
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	m = newModel.(Model)
+	if m.session.CurrentTurn != 0 {
+		t.Errorf("expected undo to rewind the turn, got %d", m.session.CurrentTurn)
+	}
+	if !strings.Contains(m.View(), "Recent Actions:\n  Undo: Advanced 1 turn to turn 1") {
+		t.Errorf("expected the undo in recent actions, got %q", m.View())
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = newModel.(Model)
+	if m.session.CurrentTurn != 1 {
+		t.Errorf("expected redo to replay the turn, got %d", m.session.CurrentTurn)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = newModel.(Model)
+	if len(m.alerts) != 1 || m.alerts[0] != "nothing to redo" {
+		t.Errorf("expected a nothing to redo alert, got %v", m.alerts)
+	}
+}
+
+func TestModel_Combat(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "campaign.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	m, err := NewModel(&engine.Engine{DB: database}, session.ID)
+	if err != nil {
+		t.Fatalf("failed to create model: %v", err)
+	}
+
+	keys := func(m Model, input ...string) Model {
+		for _, key := range input {
+			var msg tea.KeyMsg
+			switch key {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "down":
+				msg = tea.KeyMsg{Type: tea.KeyDown}
+			case " ":
+				msg = tea.KeyMsg{Type: tea.KeySpace}
+			default:
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+			}
+			newModel, _ := m.Update(msg)
+			m = newModel.(Model)
+		}
+		return m
+	}
+
+	m = keys(m, "i", "Fighter", " ", "15", " ", "8", "enter")
+	m = keys(m, "i", "Goblin", " ", "12", " ", "4", "enter")
+	if m.encounter == nil || len(m.combatants) != 2 {
+		t.Fatalf("expected an encounter with two combatants, got %+v", m.combatants)
+	}
+
+	m = keys(m, "n", "down", "d", "6", "enter")
+	view := m.View()
+	for _, expected := range []string{
+		"Round 1",
+		" ▶1. Fighter - Init 15 - 8/8 HP",
+		"> 2. Goblin - Init 12 - 0/4 HP [defeated]",
+	} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("expected %q in the view, got %q", expected, view)
+		}
+	}
+	if m.session.TimeMode != clock.ModeCombat {
+		t.Errorf("expected combat time during the encounter, got %s", m.session.TimeMode)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	if err := model.CreateMonster(tx, &model.Monster{Name: "Orc", ArmorClass: 6, HitDice: "1", Movement: 120, Morale: 8, XP: 10}); err != nil {
+		t.Fatalf("failed to create monster: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	m = keys(m, "i", "2", " ", "orcs", "enter")
+	if len(m.combatants) != 4 || m.combatants[2].Name != "Orc 1" || m.combatants[3].Name != "Orc 2" ||
+		m.combatants[2].HPMax == nil || m.combatants[2].MonsterID == nil {
+		t.Errorf("expected two orcs from the catalog, got %+v", m.combatants)
+	}
+
+	tx, err = database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	hp := 12
+	if err := model.CreateCharacter(tx, &model.Character{Name: "Theron", HPMax: &hp, HPCurrent: &hp, IsActive: true}); err != nil {
+		t.Fatalf("failed to create character: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	m = keys(m, "P")
+	if view := m.View(); !strings.Contains(view, "5. Theron - Init 0 - 12/12 HP (PC)") {
+		t.Errorf("expected the party in the initiative order, got %q", view)
+	}
+
+	m = keys(m, "e")
+	if m.encounter != nil {
+		t.Errorf("expected the encounter to end, got %+v", m.encounter)
+	}
+}
+
+func TestModel_ViewSides(t *testing.T) {
+	m := Model{
+		engine:    &engine.Engine{Config: &config.Config{Combat: config.CombatConfig{Initiative: engine.InitiativeSide}}},
+		sessionID: 1,
+		encounter: &model.Encounter{ID: 1, SessionID: 1, IsActive: true, Round: 2},
+		combatants: []model.Combatant{
+			{ID: 1, Name: "Thief", Initiative: 5, Side: model.SideParty, IsActive: true},
+		},
+	}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.SideInitiativeRolled{
+		SessionID: 1, Round: 2, Rolls: map[string]int{model.SideParty: 5, model.SideMonsters: 2},
+	}})
+	m = newModel.(Model)
+
+	view := m.View()
+	for _, expected := range []string{"Thief - Init 5 - Unknown HP [party]", "Round 2 initiative: party 5, monsters 2"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("expected %q in the view, got %q", expected, view)
+		}
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		input    string
+		name     string
+		duration int64
+		unit     string
+	}{
+		{"prone", "prone", 0, ""},
+		{"poisoned 3", "poisoned", 3, model.ConditionRounds},
+		{"magically asleep 2t", "magically asleep", 2, model.ConditionTurns},
+		{"held 4r", "held", 4, model.ConditionRounds},
+		{"hold person", "hold person", 0, ""},
+	}
+	for _, test := range tests {
+		name, duration, unit, err := parseCondition(test.input)
+		if err != nil || name != test.name || duration != test.duration || unit != test.unit {
+			t.Errorf("parseCondition(%q) = %q, %d, %q, %v", test.input, name, duration, unit, err)
+		}
+	}
+}
+
+func TestModel_ViewConditions(t *testing.T) {
+	m := Model{
+		sessionID: 1,
+		encounter: &model.Encounter{ID: 1, SessionID: 1, IsActive: true},
+		combatants: []model.Combatant{
+			{ID: 1, Name: "Goblin", IsActive: true, Conditions: `[{"name":"poisoned","unit":"round","remaining":2},{"name":"prone"}]`},
+		},
+	}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.ConditionExpired{SessionID: 1, CombatantID: 1, Name: "Goblin", Condition: "asleep"}})
+	m = newModel.(Model)
+
+	view := m.View()
+	for _, expected := range []string{"Goblin - Init 0 - Unknown HP {poisoned (2 rounds), prone}", "Goblin is no longer asleep"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("expected %q in the view, got %q", expected, view)
+		}
+	}
+}
+
+func TestModel_MoraleAlert(t *testing.T) {
+	m := Model{sessionID: 1}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.MoraleChecked{
+		SessionID: 1, Side: model.SideMonsters, Trigger: engine.MoraleFirstDeath,
+		Rolls: []engine.MoraleRoll{{Name: "Goblin 2", Morale: 7, Roll: 9, Fled: true}},
+	}})
+	m = newModel.(Model)
+
+	expected := "Morale (monsters, first death): Goblin 2 flees (9 vs ML 7)"
+	if view := m.View(); !strings.Contains(view, expected) {
+		t.Errorf("expected %q in the view, got %q", expected, view)
+	}
+}
+
+func TestModel_AttackAlert(t *testing.T) {
+	m := Model{sessionID: 1}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.AttackResolved{
+		SessionID: 1, Attacker: "Fighter", Target: "Goblin",
+		Rolls:  []engine.AttackRoll{{Roll: 14, Needed: 12, Hit: true, Damage: 3}},
+		Damage: 3, HPCurrent: 1,
+	}})
+	m = newModel.(Model)
+
+	expected := "Fighter attacks Goblin: hit for 3 (14 vs 12); Goblin at 1 HP"
+	if view := m.View(); !strings.Contains(view, expected) {
+		t.Errorf("expected %q in the view, got %q", expected, view)
+	}
+}
+
+func TestModel_ViewXPEarned(t *testing.T) {
+	m := Model{
+		sessionID: 1,
+		session:   &model.Session{ID: 1, CurrentTurn: 47},
+		xpEarned:  1247,
+	}
+
+	if view := m.View(); !strings.Contains(view, "Turn: 47  XP Earned: 1,247") {
+		t.Errorf("expected the XP earned in the header, got %q", view)
+	}
+}
+
+func TestThousands(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1247: "1,247", 1000000: "1,000,000", -4500: "-4,500"}
+	for n, expected := range tests {
+		if got := thousands(n); got != expected {
+			t.Errorf("thousands(%d) = %q, want %q", n, got, expected)
+		}
+	}
+}
+
+func TestModel_Lights(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "campaign.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	eng := &engine.Engine{DB: database}
+	theron := &model.Character{Name: "Theron", IsActive: true}
+	if err := eng.AddCharacter(theron); err != nil {
+		t.Fatalf("failed to add character: %v", err)
+	}
+	if _, err := eng.LightSource(theron.ID, model.LightTorch); err != nil {
+		t.Fatalf("failed to light torch: %v", err)
+	}
+	if err := eng.Advance(session.ID, 7); err != nil {
+		t.Fatalf("failed to advance: %v", err)
+	}
+
+	m, err := NewModel(eng, session.ID)
+	if err != nil {
+		t.Fatalf("failed to create model: %v", err)
+	}
+	if view := m.View(); !strings.Contains(view, "XP Earned: 0  Torch: 3/10") {
+		t.Errorf("expected the torch in the header, got %q", view)
+	}
+
+	newModel, _ := m.Update(busEventMsg{event: engine.LightLow{
+		SessionID: session.ID, CharacterID: theron.ID, Name: "Theron", Source: model.LightTorch, Remaining: 1,
+	}})
+	m = newModel.(Model)
+	expected := "Theron's torch is about to go out (1 turn left)"
+	if view := m.View(); !strings.Contains(view, expected) {
+		t.Errorf("expected %q in the view, got %q", expected, view)
+	}
+
+	if err := eng.Advance(session.ID, 3); err != nil {
+		t.Fatalf("failed to advance: %v", err)
+	}
+	newModel, _ = m.Update(busEventMsg{event: engine.LightOut{
+		SessionID: session.ID, CharacterID: theron.ID, Name: "Theron", Source: model.LightTorch,
+	}})
+	m = newModel.(Model)
+	if view := m.View(); strings.Contains(view, "Torch: ") || !strings.Contains(view, "Theron's torch goes out") {
+		t.Errorf("expected the torch gone from the header with an alert, got %q", view)
+	}
+}
+
+func TestModel_LightTorchKey(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "campaign.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	tx, err := database.Beginx()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	session := &model.Session{}
+	if err := session.Create(tx); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	eng := &engine.Engine{DB: database}
+	m, err := NewModel(eng, session.ID)
+	if err != nil {
+		t.Fatalf("failed to create model: %v", err)
+	}
+	press := func() {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+		m = newModel.(Model)
+	}
+
+	press()
+	if view := m.View(); !strings.Contains(view, "No one in the party to light a torch") {
+		t.Errorf("expected an alert without a party, got %q", view)
+	}
+
+	mira := &model.Character{Name: "Mira", IsActive: true}
+	theron := &model.Character{Name: "Theron", IsActive: true}
+	for _, character := range []*model.Character{mira, theron} {
+		if err := eng.AddCharacter(character); err != nil {
+			t.Fatalf("failed to add character: %v", err)
+		}
+	}
+
+	press()
+	if err := eng.Advance(session.ID, 4); err != nil {
+		t.Fatalf("failed to advance: %v", err)
+	}
+	press()
+	if view := m.View(); !strings.Contains(view, "Torch: 6/10") {
+		t.Errorf("expected Mira's torch to be the one closest to going out, got %q", view)
+	}
+	press()
+	for _, character := range []*model.Character{mira, theron} {
+		lit, err := model.GetCharacter(database, character.ID)
+		if err != nil {
+			t.Fatalf("failed to get character: %v", err)
+		}
+		if !lit.Lit() || *lit.LightRemaining != 10 {
+			t.Errorf("expected %s to carry a fresh torch, got %+v", lit.Name, lit)
+		}
+	}
+
+	events, err := model.ListPendingTimeEvents(database, session.ID)
+	if err != nil || len(events) != 0 {
+		t.Errorf("expected torches to be tracked on characters only, got %+v, %v", events, err)
+	}
+}